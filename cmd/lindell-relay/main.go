@@ -0,0 +1,46 @@
+// Command lindell-relay serves the HTTP/JSON relay through which two signing parties exchange their messages.
+//
+//	lindell-relay [-listen 127.0.0.1:8080] [-ttl 5m]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"go-rust/lindell/transport/relay"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8080", "address to listen on")
+	ttl := flag.Duration("ttl", 5*time.Minute, "idle time after which a session expires")
+	flag.Parse()
+
+	if err := run(*listen, *ttl); err != nil {
+		fmt.Fprintln(os.Stderr, "lindell-relay:", err)
+		os.Exit(1)
+	}
+}
+
+func run(listen string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("-ttl must be positive")
+	}
+	srv := relay.NewServer(ttl)
+	stop := make(chan struct{})
+	defer close(stop)
+	go srv.Run(stop)
+
+	hs := &http.Server{
+		Addr:              listen,
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+		// polls are held open for up to relay.PollWait
+		WriteTimeout: relay.PollWait + 10*time.Second,
+	}
+	log.Printf("relay listening on %s, sessions expire after %s idle", listen, ttl)
+	return hs.ListenAndServe()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"go-rust/lindell/address"
+)
+
+func runAddress(args []string) error {
+	fs := flag.NewFlagSet("address", flag.ExitOnError)
+	keyFile := fs.String("key", "", "key share")
+	path := fs.String("path", "", "non-hardened BIP-32 path of a child key, e.g. m/44/60/0/0")
+	chainCode := fs.String("chaincode", "", "hex chain code of the key, required with -path")
+	network := fs.String("network", address.BitcoinMainnet.Name, "bitcoin network: mainnet or testnet")
+	cosmosHRP := fs.String("cosmos-hrp", address.CosmosHRP, "bech32 prefix of the cosmos address")
+	asJSON := fs.Bool("json", false, "print the addresses as JSON")
+	fs.Parse(args)
+
+	net, err := address.NetworkByName(*network)
+	if err != nil {
+		return err
+	}
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	pub, err := address.PublicKey(key)
+	if err != nil {
+		return err
+	}
+	if pub, err = address.DerivePath(pub, *path, *chainCode); err != nil {
+		return err
+	}
+
+	addrs, err := address.Derive(pub, net, *cosmosHRP)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(addrs)
+	}
+	return addrs.WriteText(os.Stdout)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"go-rust/lindell/backup"
+
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+)
+
+const paillierGenTimeout = 5 * time.Minute
+
+func runEscrowKey(args []string) error {
+	fs := flag.NewFlagSet("escrow-key", flag.ExitOnError)
+	out := fs.String("out", "", "file to write the escrow private key to, keep it offline")
+	pubOut := fs.String("pub", "", "file to write the escrow public key to, handed to the parties")
+	bits := fs.Int("bits", 3072, "modulus size in bits")
+	fs.Parse(args)
+
+	if *out == "" || *pubOut == "" {
+		return errors.New("-out and -pub are required")
+	}
+	if *bits < backup.MinEscrowBits {
+		return fmt.Errorf("-bits must be at least %d", backup.MinEscrowBits)
+	}
+	sk, err := generatePaillierKey(*bits)
+	if err != nil {
+		return err
+	}
+	if err = writeJSONFile(*out, sk, 0600); err != nil {
+		return err
+	}
+	if err = writeJSONFile(*pubOut, sk.PublicKey, 0644); err != nil {
+		return err
+	}
+	fmt.Println("wrote", *out, "and", *pubOut)
+	return nil
+}
+
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	keyFile := fs.String("key", "", "key share to back up")
+	escrowFile := fs.String("escrow", "", "escrow public key written by escrow-key -pub")
+	peer := fs.Int("peer", -1, "original index of the party that verifies the backup; defaults to the other party of a two-party share")
+	out := fs.String("out", "", "file to write the backup to")
+	fs.Parse(args)
+
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	escrow := new(paillier.PublicKey)
+	if err = readJSONFile(*escrowFile, escrow); err != nil {
+		return err
+	}
+	verifier, err := verifierOf(key, *peer)
+	if err != nil {
+		return err
+	}
+	b, err := backup.New(key, escrow, verifier)
+	if err != nil {
+		return err
+	}
+	if err = b.Save(*out); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s, key id %s, hand it to the counterparty to verify\n", *out, b.KeyID)
+	return nil
+}
+
+func runVerifyBackup(args []string) error {
+	fs := flag.NewFlagSet("verify-backup", flag.ExitOnError)
+	keyFile := fs.String("key", "", "this party's key share")
+	backupFile := fs.String("backup", "", "the counterparty's backup")
+	escrowFile := fs.String("escrow", "", "escrow public key the backup must be encrypted to, required")
+	fs.Parse(args)
+	if *escrowFile == "" {
+		return errors.New("-escrow is required")
+	}
+
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	b, err := backup.Load(*backupFile)
+	if err != nil {
+		return err
+	}
+	escrow := new(paillier.PublicKey)
+	if err = readJSONFile(*escrowFile, escrow); err != nil {
+		return err
+	}
+	if err = backup.Verify(key, b, escrow); err != nil {
+		return err
+	}
+	fmt.Printf("backup of share %s of key %s is valid\n", b.ShareID.Text(16), b.KeyID)
+	return nil
+}
+
+func runRecover(args []string) error {
+	fs := flag.NewFlagSet("recover", flag.ExitOnError)
+	backupFile := fs.String("backup", "", "backup of the lost share")
+	escrowFile := fs.String("escrow-key", "", "escrow private key written by escrow-key -out")
+	out := fs.String("out", "", "file to write the restored key share to")
+	encrypt := fs.Bool("encrypt", false, "write the share to a keystore under $"+passphraseEnv)
+	fs.Parse(args)
+
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	b, err := backup.Load(*backupFile)
+	if err != nil {
+		return err
+	}
+	escrow := new(paillier.PrivateKey)
+	if err = readJSONFile(*escrowFile, escrow); err != nil {
+		return err
+	}
+	// the lost share's Paillier key went with it
+	paillierSK, err := generatePaillierKey(2048)
+	if err != nil {
+		return err
+	}
+	key, err := backup.Restore(escrow, b, paillierSK)
+	if err != nil {
+		return err
+	}
+	if err = saveKey(*out, key, *encrypt); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s; the share has a new paillier key, a client must verify the server afresh\n", *out)
+	return nil
+}
+
+// verifierOf returns the share id of the party at index peer, or of the other party of a two-party share
+func verifierOf(key keygen.LocalPartySaveData, peer int) (*big.Int, error) {
+	if peer < 0 {
+		pIDs, self, err := sessionParties(key)
+		if err != nil {
+			return nil, fmt.Errorf("%v, or set -peer", err)
+		}
+		return peerOf(pIDs, self).KeyInt(), nil
+	}
+	if peer >= len(key.Ks) {
+		return nil, fmt.Errorf("-peer %d is out of range, the share has %d parties", peer, len(key.Ks))
+	}
+	return key.Ks[peer], nil
+}
+
+func generatePaillierKey(bits int) (*paillier.PrivateKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), paillierGenTimeout)
+	defer cancel()
+	sk, _, err := paillier.GenerateKeyPair(ctx, bits)
+	return sk, err
+}
+
+func readJSONFile(path string, v interface{}) error {
+	if path == "" {
+		return errors.New("a required file flag is missing")
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(bz, v); err != nil {
+		return fmt.Errorf("parsing %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	keyFile := fs.String("key", "", "key share of a larger committee (keygen.LocalPartySaveData as JSON)")
+	peer := fs.Int("peer", -1, "original index of the party to sign with, i.e. its position in the share's Ks; the committee's threshold must be 1")
+	out := fs.String("out", "", "file to write the two-party share to")
+	encrypt := fs.Bool("encrypt", false, "write the share to a keystore under $"+passphraseEnv)
+	fs.Parse(args)
+
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	converted, err := convertKey(key, *peer)
+	if err != nil {
+		return err
+	}
+	if err = saveKey(*out, converted, *encrypt); err != nil {
+		return err
+	}
+	fmt.Println("wrote", *out)
+	return nil
+}
+
+// convertKey keeps the parts of key that concern this party and the peer at index peer
+func convertKey(key keygen.LocalPartySaveData, peer int) (keygen.LocalPartySaveData, error) {
+	self, err := key.OriginalIndex()
+	if err != nil {
+		return key, err
+	}
+	if peer < 0 || peer >= len(key.Ks) || peer == self {
+		return key, fmt.Errorf("-peer must be the index of another party, between 0 and %d", len(key.Ks)-1)
+	}
+	pIDs := tss.SortPartyIDs(tss.UnSortedPartyIDs{
+		tss.NewPartyID(fmt.Sprintf("%d", self+1), "", new(big.Int).Set(key.Ks[self])),
+		tss.NewPartyID(fmt.Sprintf("%d", peer+1), "", new(big.Int).Set(key.Ks[peer])),
+	})
+	return keygen.BuildLocalSaveDataSubset(key, pIDs), nil
+}
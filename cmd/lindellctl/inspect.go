@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/keystore"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+)
+
+func runInspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: lindellctl inspect <key share or state file>")
+	}
+	bz, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var probe map[string]json.RawMessage
+	if err = json.Unmarshal(bz, &probe); err != nil {
+		return fmt.Errorf("%s is not JSON: %v", fs.Arg(0), err)
+	}
+	switch {
+	case isKeystore(bz):
+		f, err := keystore.Load(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return inspectKeystore(f)
+	case probe["session_id"] != nil:
+		var state sessionState
+		if err = json.Unmarshal(bz, &state); err != nil {
+			return err
+		}
+		return inspectState(&state)
+	case probe["ShareID"] != nil:
+		key, err := loadKey(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return inspectKey(key)
+	default:
+		return fmt.Errorf("%s is neither a key share, a keystore nor a session state file", fs.Arg(0))
+	}
+}
+
+func inspectKey(key keygen.LocalPartySaveData) error {
+	index, err := key.OriginalIndex()
+	if err != nil {
+		return err
+	}
+	curve, err := ffi.CurveName(key.ECDSAPub.Curve())
+	if err != nil {
+		return err
+	}
+	pub := key.ECDSAPub
+	size := (pub.Curve().Params().BitSize + 7) / 8
+	compressed := make([]byte, 1+size)
+	compressed[0] = 2 | byte(pub.Y().Bit(0))
+	pub.X().FillBytes(compressed[1:])
+
+	fmt.Printf("kind:          key share\n")
+	fmt.Printf("curve:         %s\n", curve)
+	fmt.Printf("public key:    %s\n", hex.EncodeToString(compressed))
+	fmt.Printf("parties:       %d\n", len(key.Ks))
+	fmt.Printf("this party:    index %d, share id %s\n", index, key.ShareID.Text(16))
+	if key.PaillierSK != nil {
+		fmt.Printf("paillier key:  %d bits\n", key.PaillierSK.PublicKey.N.BitLen())
+	}
+	fmt.Printf("pre-params ok: %v\n", key.LocalPreParams.Validate())
+	if len(key.Ks) != 2 {
+		fmt.Println("note:          convert the share to a two-party share before signing")
+	}
+	return nil
+}
+
+// inspectKeystore shows what a keystore reveals without its passphrase
+func inspectKeystore(f *keystore.File) error {
+	fmt.Printf("kind:        keystore v%d\n", f.Version)
+	fmt.Printf("key id:      %s\n", f.Metadata.KeyID)
+	fmt.Printf("curve:       %s\n", f.Metadata.Curve)
+	fmt.Printf("public key:  %s\n", f.Metadata.PublicKey)
+	fmt.Printf("share id:    %s\n", f.Metadata.ShareID)
+	if f.Metadata.Role != "" {
+		fmt.Printf("role:        %s\n", f.Metadata.Role)
+	}
+	if f.Metadata.ChainCode != "" {
+		fmt.Printf("chain code:  %s\n", f.Metadata.ChainCode)
+	}
+	fmt.Printf("created at:  %s\n", f.Metadata.CreatedAt.Format("2006-01-02 15:04:05Z07:00"))
+	fmt.Printf("encryption:  %s, %s\n", f.Cipher, f.KDF.Name)
+	return nil
+}
+
+func inspectState(state *sessionState) error {
+	fmt.Printf("kind:        session state\n")
+	fmt.Printf("session:     %s\n", state.SessionID)
+	fmt.Printf("status:      %s\n", state.Status)
+	if state.Error != "" {
+		fmt.Printf("error:       %s\n", state.Error)
+	}
+	fmt.Printf("role:        %s\n", state.Role)
+	fmt.Printf("transport:   %s (secured: %v)\n", state.Transport, state.Secured)
+	fmt.Printf("self:        %s\n", state.Self)
+	fmt.Printf("peer:        %s\n", state.Peer)
+	fmt.Printf("hash:        %s\n", state.Hash)
+	fmt.Printf("started at:  %s\n", state.StartedAt.Format("2006-01-02 15:04:05Z07:00"))
+	if state.FinishedAt != nil {
+		fmt.Printf("finished at: %s (%s)\n", state.FinishedAt.Format("2006-01-02 15:04:05Z07:00"), state.FinishedAt.Sub(state.StartedAt))
+	}
+	if state.Signature != nil {
+		fmt.Printf("signature:   %s\n", state.Signature.DER)
+	}
+	return nil
+}
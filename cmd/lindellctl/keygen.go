@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// runKeygen runs all parties of a tss-lib keygen in this process. Whoever runs it sees every share, so it suits
+// test keys and fixtures and ceremonies where the shares are handed out afterwards. Shares of a larger committee of
+// threshold 1 sign two-party once converted, see runConvert.
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", ".", "directory to write keygen_data_0.json, keygen_data_1.json, ... to")
+	partyCount := fs.Int("parties", 2, "number of parties holding a share")
+	threshold := fs.Int("threshold", 1, "threshold of the key: any threshold+1 parties can sign")
+	curve := fs.String("curve", "secp256k1", "curve of the key: secp256k1 or secp256r1")
+	encrypt := fs.Bool("encrypt", false, "write the shares to keystores under $"+passphraseEnv)
+	timeout := fs.Duration("timeout", 10*time.Minute, "time allowed for generating the Paillier and safe-prime parameters")
+	fs.Parse(args)
+
+	ec, err := parseCurve(*curve)
+	if err != nil {
+		return err
+	}
+	if *partyCount < 2 || *threshold < 1 || *threshold >= *partyCount {
+		return fmt.Errorf("a key needs at least 2 parties and a threshold from 1 to parties-1, got %d and %d", *partyCount, *threshold)
+	}
+	if err = os.MkdirAll(*out, 0700); err != nil {
+		return err
+	}
+	if *encrypt {
+		// fail before the slow part
+		if _, err = passphrase(passphraseEnv); err != nil {
+			return err
+		}
+	}
+	pIDs := tss.GenerateTestPartyIDs(*partyCount)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	out2 := make(chan tss.Message, len(pIDs)*4)
+	end := make(chan keygen.LocalPartySaveData, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+
+	parties := make([]tss.Party, len(pIDs))
+	for i, pID := range pIDs {
+		fmt.Printf("generating the pre-parameters of party %d, this takes a while\n", i)
+		preParams, err := keygen.GeneratePreParams(*timeout)
+		if err != nil {
+			return err
+		}
+		parties[i] = keygen.NewLocalParty(tss.NewParameters(ec, p2pCtx, pID, len(pIDs), *threshold), out2, end, *preParams)
+	}
+	for _, p := range parties {
+		go func(p tss.Party) {
+			if err := p.Start(); err != nil {
+				errCh <- err
+			}
+		}(p)
+	}
+
+	deadline := time.NewTimer(*timeout)
+	defer deadline.Stop()
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for done := 0; done < len(pIDs); {
+		select {
+		case err := <-errCh:
+			return err
+		case msg := <-out2:
+			if err := route(parties, msg, errCh); err != nil {
+				return err
+			}
+		case key := <-end:
+			index, err := key.OriginalIndex()
+			if err != nil {
+				return err
+			}
+			keys[index] = key
+			done++
+		case <-deadline.C:
+			return errors.New("keygen timed out")
+		}
+	}
+
+	for i, key := range keys {
+		path := filepath.Join(*out, fmt.Sprintf("keygen_data_%d.json", i))
+		if err = saveKey(path, key, *encrypt); err != nil {
+			return err
+		}
+		fmt.Println("wrote", path)
+	}
+	return nil
+}
+
+// route hands msg to the parties it is addressed to. Updates run on their own goroutines because a party holds
+// its lock while it sends on out.
+func route(parties []tss.Party, msg tss.Message, errCh chan<- *tss.Error) error {
+	bz, routing, err := msg.WireBytes()
+	if err != nil {
+		return err
+	}
+	for _, p := range parties {
+		if p.PartyID().Index == routing.From.Index {
+			continue
+		}
+		if !routing.IsBroadcast && !addressedTo(routing.To, p.PartyID()) {
+			continue
+		}
+		go func(p tss.Party) {
+			if _, err := p.UpdateFromBytes(bz, routing.From, routing.IsBroadcast); err != nil {
+				errCh <- err
+			}
+		}(p)
+	}
+	return nil
+}
+
+func addressedTo(to []*tss.PartyID, p *tss.PartyID) bool {
+	for _, t := range to {
+		if t.Index == p.Index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/keystore"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func parseCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case ffi.CurveSecp256k1:
+		return tss.S256(), nil
+	case ffi.CurveSecp256r1, "p256":
+		return elliptic.P256(), nil
+	default:
+		return nil, fmt.Errorf("unknown curve %q, expected %s or %s", name, ffi.CurveSecp256k1, ffi.CurveSecp256r1)
+	}
+}
+
+const (
+	passphraseEnv    = "LINDELL_PASSPHRASE"
+	newPassphraseEnv = "LINDELL_NEW_PASSPHRASE"
+)
+
+// passphrase reads a keystore passphrase from the environment, so it stays out of the shell history
+func passphrase(env string) ([]byte, error) {
+	p := os.Getenv(env)
+	if p == "" {
+		return nil, fmt.Errorf("set %s to the keystore passphrase", env)
+	}
+	return []byte(p), nil
+}
+
+// loadKey reads a key share from a keystore file, or from plain JSON such as the test fixtures
+func loadKey(path string) (keygen.LocalPartySaveData, error) {
+	key, _, err := loadKeyWithMetadata(path)
+	return key, err
+}
+
+// loadKeyWithMetadata is loadKey that also returns the keystore metadata, nil for plain JSON
+func loadKeyWithMetadata(path string) (keygen.LocalPartySaveData, *keystore.Metadata, error) {
+	var key keygen.LocalPartySaveData
+	var meta *keystore.Metadata
+	if path == "" {
+		return key, nil, errors.New("-key is required")
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return key, nil, err
+	}
+	if isKeystore(bz) {
+		pass, err := passphrase(passphraseEnv)
+		if err != nil {
+			return key, nil, err
+		}
+		if key, meta, err = keystore.LoadKeyShare(path, pass); err != nil {
+			return key, nil, err
+		}
+	} else if err = json.Unmarshal(bz, &key); err != nil {
+		return key, nil, fmt.Errorf("parsing key share %s: %v", path, err)
+	}
+	if key.ShareID == nil || key.ECDSAPub == nil || len(key.Ks) < 2 {
+		return key, nil, fmt.Errorf("%s is not a key share", path)
+	}
+	return key, meta, nil
+}
+
+// saveKey writes a key share readable by its owner only, into a keystore under the passphrase if encrypt is set
+func saveKey(path string, key keygen.LocalPartySaveData, encrypt bool) error {
+	if !encrypt {
+		return writeJSONFile(path, key, 0600)
+	}
+	pass, err := passphrase(passphraseEnv)
+	if err != nil {
+		return err
+	}
+	meta, err := keystore.NewMetadata(key, "", nil)
+	if err != nil {
+		return err
+	}
+	f, err := keystore.Encrypt(key, meta, pass, keystore.Scrypt)
+	if err != nil {
+		return err
+	}
+	return f.Save(path)
+}
+
+func isKeystore(bz []byte) bool {
+	var probe struct {
+		Version    int    `json:"version"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	return json.Unmarshal(bz, &probe) == nil && probe.Version != 0 && len(probe.Ciphertext) > 0
+}
+
+func writeJSONFile(path string, v interface{}, perm os.FileMode) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bz, '\n'), perm)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// sessionParties returns the party set of a two-party share, named the way the test fixtures are, and this party
+func sessionParties(key keygen.LocalPartySaveData) (tss.SortedPartyIDs, *tss.PartyID, error) {
+	if len(key.Ks) != 2 {
+		return nil, nil, fmt.Errorf("the key share belongs to %d parties, convert it to a two-party share first", len(key.Ks))
+	}
+	unsorted := make(tss.UnSortedPartyIDs, len(key.Ks))
+	for i, k := range key.Ks {
+		moniker := fmt.Sprintf("%d", i+1)
+		unsorted[i] = tss.NewPartyID(moniker, moniker, k)
+	}
+	pIDs := tss.SortPartyIDs(unsorted)
+	self := pIDs.FindByKey(key.ShareID)
+	if self == nil {
+		return nil, nil, errors.New("the key share's own share id is not among its parties")
+	}
+	return pIDs, self, nil
+}
+
+func peerOf(pIDs tss.SortedPartyIDs, self *tss.PartyID) *tss.PartyID {
+	for _, p := range pIDs {
+		if p.KeyInt().Cmp(self.KeyInt()) != 0 {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+
+	"go-rust/lindell/keystore"
+)
+
+func runEncrypt(args []string) error {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	keyFile := fs.String("key", "", "plain key share (keygen.LocalPartySaveData as JSON)")
+	out := fs.String("out", "", "keystore file to write")
+	role := fs.String("role", "", "signing role to record: server or client")
+	chainCode := fs.String("chaincode", "", "hex chain code to record with the key")
+	kdf := fs.String("kdf", keystore.KDFScrypt, "passphrase KDF: scrypt or argon2id")
+	fs.Parse(args)
+
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	if *role != "" && *role != "server" && *role != "client" {
+		return fmt.Errorf("unknown role %q", *role)
+	}
+	params, err := kdfParams(*kdf)
+	if err != nil {
+		return err
+	}
+	cc, err := hex.DecodeString(*chainCode)
+	if err != nil {
+		return fmt.Errorf("invalid chain code: %v", err)
+	}
+	pass, err := passphrase(passphraseEnv)
+	if err != nil {
+		return err
+	}
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	meta, err := keystore.NewMetadata(key, *role, cc)
+	if err != nil {
+		return err
+	}
+	f, err := keystore.Encrypt(key, meta, pass, params)
+	if err != nil {
+		return err
+	}
+	if err = f.Save(*out); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s, key id %s\n", *out, meta.KeyID)
+	return nil
+}
+
+func runRekey(args []string) error {
+	fs := flag.NewFlagSet("rekey", flag.ExitOnError)
+	path := fs.String("keystore", "", "keystore file to re-encrypt in place")
+	kdf := fs.String("kdf", "", "switch to another passphrase KDF: scrypt or argon2id")
+	fs.Parse(args)
+
+	f, err := keystore.Load(*path)
+	if err != nil {
+		return err
+	}
+	var params keystore.KDFParams
+	if *kdf != "" {
+		if params, err = kdfParams(*kdf); err != nil {
+			return err
+		}
+	}
+	oldPass, err := passphrase(passphraseEnv)
+	if err != nil {
+		return err
+	}
+	newPass, err := passphrase(newPassphraseEnv)
+	if err != nil {
+		return err
+	}
+	if err = f.Rekey(oldPass, newPass, params); err != nil {
+		return err
+	}
+	return f.Save(*path)
+}
+
+func kdfParams(name string) (keystore.KDFParams, error) {
+	switch name {
+	case keystore.KDFScrypt:
+		return keystore.Scrypt, nil
+	case keystore.KDFArgon2id:
+		return keystore.Argon2id, nil
+	default:
+		return keystore.KDFParams{}, fmt.Errorf("unknown KDF %q", name)
+	}
+}
@@ -0,0 +1,81 @@
+// Command lindellctl runs two-party ceremonies from the command line: it generates and converts key shares, runs
+// either side of a signing session over one of the transports, verifies signatures, prints the addresses of a key
+// and inspects key shares and recorded session state.
+//
+// Key shares are read from keystore files or plain JSON. The passphrase of a keystore is taken from
+// $LINDELL_PASSPHRASE, rekey takes the new one from $LINDELL_NEW_PASSPHRASE.
+//
+//	lindellctl keygen -out dir [-parties 2 -threshold 1]
+//	lindellctl convert -key keygen_data_0.json -peer 2 -out share.json [-encrypt]
+//	lindellctl encrypt -key share.json -out share.keystore [-role server -chaincode <hex>]
+//	lindellctl rekey -keystore share.keystore
+//	lindellctl identity -out identity.json
+//	lindellctl relay-session -relay http://relay:8080 -key share.json
+//	lindellctl sign -key share.json -hash <hex> -session id -transport grpc|ws|relay [-listen addr | -connect addr]
+//	                [-log-level debug|info|warn|error]
+//	lindellctl verify -key share.json -hash <hex> -sig <hex>
+//	lindellctl address -key share.json [-path m/44/60/0/0 -chaincode <hex>] [-network testnet] [-json]
+//	lindellctl inspect share.json|share.keystore|state.json
+//	lindellctl escrow-key -out escrow.json -pub escrow.pub.json
+//	lindellctl backup -key share.json -escrow escrow.pub.json -out backup.json
+//	lindellctl verify-backup -key share.json -backup backup.json -escrow escrow.pub.json
+//	lindellctl recover -backup backup.json -escrow-key escrow.json -out share.json [-encrypt]
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"keygen":        {"generate the key shares of a new key in-process", runKeygen},
+	"convert":       {"reduce a key share of a larger committee to a two-party share", runConvert},
+	"encrypt":       {"encrypt a plain key share into a keystore", runEncrypt},
+	"rekey":         {"change the passphrase of a keystore", runRekey},
+	"identity":      {"generate a long-term identity key for the secure channel", runIdentity},
+	"relay-session": {"create a signing session on a relay and print its roles and tokens", runRelaySession},
+	"sign":          {"run this party's side of a signing session", runSign},
+	"verify":        {"verify an ECDSA signature", runVerify},
+	"address":       {"print the public keys and addresses of a key", runAddress},
+	"inspect":       {"summarise a key share, a keystore or a recorded session state file", runInspect},
+	"escrow-key":    {"generate an escrow key for share backups", runEscrowKey},
+	"backup":        {"back up a key share encrypted to an escrow key, with a proof for the counterparty", runBackup},
+	"verify-backup": {"check the counterparty's backup against this party's key share", runVerifyBackup},
+	"recover":       {"restore a lost key share from its backup with the escrow private key", runRecover},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "lindellctl: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "lindellctl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: lindellctl <command> [flags]")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "run lindellctl <command> -h for the flags of a command")
+}
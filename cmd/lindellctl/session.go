@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go-rust/lindell/transport"
+	"go-rust/lindell/transport/grpctransport"
+	"go-rust/lindell/transport/relay"
+	"go-rust/lindell/transport/securechannel"
+	"go-rust/lindell/transport/wstransport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func runIdentity(args []string) error {
+	fs := flag.NewFlagSet("identity", flag.ExitOnError)
+	out := fs.String("out", "", "file to write the identity key pair to")
+	fs.Parse(args)
+
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	id, err := securechannel.GenerateIdentity()
+	if err != nil {
+		return err
+	}
+	if err = writeJSONFile(*out, id, 0600); err != nil {
+		return err
+	}
+	fmt.Println("public key:", hex.EncodeToString(id.Public))
+	return nil
+}
+
+func runRelaySession(args []string) error {
+	fs := flag.NewFlagSet("relay-session", flag.ExitOnError)
+	relayURL := fs.String("relay", "", "base URL of the relay")
+	keyFile := fs.String("key", "", "two-party key share of either party")
+	fs.Parse(args)
+
+	if *relayURL == "" {
+		return errors.New("-relay is required")
+	}
+	key, err := loadKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	pIDs, _, err := sessionParties(key)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := relay.NewClient(*relayURL, nil).CreateSession(ctx, tss.UnSortedPartyIDs(pIDs))
+	if err != nil {
+		return err
+	}
+	return printJSON(resp)
+}
+
+// connFlags select the transport of a session and this side's end of it
+type connFlags struct {
+	transport    string
+	listen       string
+	connect      string
+	token        string
+	identityFile string
+	peerIdentity string
+}
+
+func (f *connFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.transport, "transport", "grpc", "transport: grpc, ws or relay")
+	fs.StringVar(&f.listen, "listen", "", "grpc/ws: address to accept the peer on")
+	fs.StringVar(&f.connect, "connect", "", "grpc: address of the peer; ws: ws:// URL of the peer; relay: base URL of the relay")
+	fs.StringVar(&f.token, "token", "", "relay: this party's session token, see relay-session")
+	fs.StringVar(&f.identityFile, "identity", "", "identity key file; secures the session, requires -peer-identity")
+	fs.StringVar(&f.peerIdentity, "peer-identity", "", "hex public identity key of the peer")
+}
+
+// dial opens this party's end of the session. The returned cleanup releases what dial set up.
+func (f *connFlags) dial(ctx context.Context, sessionID string, self, peer *tss.PartyID) (transport.Conn, func(), error) {
+	conn, cleanup, err := f.dialInsecure(ctx, sessionID, self)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.identityFile == "" {
+		return conn, cleanup, nil
+	}
+	secured, err := f.secure(conn, sessionID, self, peer)
+	if err != nil {
+		conn.Close()
+		cleanup()
+		return nil, nil, err
+	}
+	return secured, cleanup, nil
+}
+
+func (f *connFlags) dialInsecure(ctx context.Context, sessionID string, self *tss.PartyID) (transport.Conn, func(), error) {
+	if (f.listen == "") == (f.connect == "") {
+		return nil, nil, errors.New("exactly one of -listen and -connect is required")
+	}
+	switch {
+	case f.transport == "grpc" && f.listen != "":
+		lis, err := net.Listen("tcp", f.listen)
+		if err != nil {
+			return nil, nil, err
+		}
+		srv := grpctransport.NewServer()
+		gs := grpc.NewServer()
+		grpctransport.RegisterTransportServer(gs, srv)
+		go gs.Serve(lis)
+		conn, _, err := srv.Accept(ctx, sessionID)
+		if err != nil {
+			gs.Stop()
+			return nil, nil, err
+		}
+		return conn, gs.Stop, nil
+
+	case f.transport == "grpc":
+		cc, err := grpc.Dial(f.connect, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, nil, err
+		}
+		conn, err := grpctransport.Dial(ctx, cc, sessionID, self)
+		if err != nil {
+			cc.Close()
+			return nil, nil, err
+		}
+		return conn, func() { cc.Close() }, nil
+
+	case f.transport == "ws" && f.listen != "":
+		srv := wstransport.NewServer(time.Minute, nil)
+		hs := &http.Server{Addr: f.listen, Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+		go hs.ListenAndServe()
+		conn, _, err := srv.Accept(ctx, sessionID)
+		if err != nil {
+			hs.Close()
+			return nil, nil, err
+		}
+		return conn, func() { hs.Close() }, nil
+
+	case f.transport == "ws":
+		client, err := wstransport.Dial(ctx, f.connect, nil, time.Minute)
+		if err != nil {
+			return nil, nil, err
+		}
+		conn, err := client.Open(sessionID, self)
+		if err != nil {
+			client.Close()
+			return nil, nil, err
+		}
+		return conn, func() { client.Close() }, nil
+
+	case f.transport == "relay":
+		if f.connect == "" || f.token == "" {
+			return nil, nil, errors.New("the relay transport takes -connect <relay URL> and -token")
+		}
+		conn, err := relay.NewClient(f.connect, nil).Join(ctx, sessionID, self, f.token)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, func() {}, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown transport %q", f.transport)
+	}
+}
+
+func (f *connFlags) secure(conn transport.Conn, sessionID string, self, peer *tss.PartyID) (transport.Conn, error) {
+	bz, err := os.ReadFile(f.identityFile)
+	if err != nil {
+		return nil, err
+	}
+	var id securechannel.Identity
+	if err = json.Unmarshal(bz, &id); err != nil {
+		return nil, fmt.Errorf("parsing identity %s: %v", f.identityFile, err)
+	}
+	peerKey, err := hex.DecodeString(strings.TrimPrefix(f.peerIdentity, "0x"))
+	if err != nil || len(peerKey) == 0 {
+		return nil, errors.New("-peer-identity must be the hex public identity key of the peer")
+	}
+	return securechannel.Handshake(conn, sessionID, self, &id, securechannel.Peer{ID: peer, StaticKey: peerKey})
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go-rust/lindell/logging"
+	"go-rust/lindell/signing"
+	"go-rust/lindell/signing/sigenc"
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	stateRunning = "running"
+	stateDone    = "done"
+	stateFailed  = "failed"
+)
+
+// sessionState is what sign records about a session in its -state file
+type sessionState struct {
+	SessionID  string           `json:"session_id"`
+	Role       string           `json:"role"`
+	Transport  string           `json:"transport"`
+	Secured    bool             `json:"secured"`
+	Self       string           `json:"self"`
+	Peer       string           `json:"peer"`
+	Hash       string           `json:"hash"`
+	Status     string           `json:"status"`
+	Error      string           `json:"error,omitempty"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	Signature  *signatureOutput `json:"signature,omitempty"`
+}
+
+type signatureOutput struct {
+	R          string `json:"r"`
+	S          string `json:"s"`
+	RecoveryID int    `json:"recovery_id"`
+	DER        string `json:"der"`
+	Compact    string `json:"compact"`
+}
+
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	keyFile := fs.String("key", "", "two-party key share")
+	curve := fs.String("curve", "secp256k1", "curve of the key: secp256k1 or secp256r1")
+	hashHex := fs.String("hash", "", "hex of the 32-byte message hash to sign")
+	sessionID := fs.String("session", "", "session id, the same on both sides")
+	stateFile := fs.String("state", "", "file to record the session state in, see inspect")
+	timeout := fs.Duration("timeout", 5*time.Minute, "time allowed for the whole session")
+	logLevel := fs.String("log-level", "warn", "level of the session's log on stderr: debug, info, warn or error")
+	var cf connFlags
+	cf.register(fs)
+	fs.Parse(args)
+
+	ec, err := parseCurve(*curve)
+	if err != nil {
+		return err
+	}
+	hash, err := hex.DecodeString(strings.TrimPrefix(*hashHex, "0x"))
+	if err != nil || len(hash) != 32 {
+		return errors.New("-hash must be 32 bytes of hex")
+	}
+	if *sessionID == "" {
+		return errors.New("-session is required")
+	}
+	key, meta, err := loadKeyWithMetadata(*keyFile)
+	if err != nil {
+		return err
+	}
+	pIDs, self, err := sessionParties(key)
+	if err != nil {
+		return err
+	}
+	peer := peerOf(pIDs, self)
+	// a role pinned in the keystore wins over the one derived from the party keys
+	role := signing.RoleAuto
+	if meta != nil && meta.Role != "" {
+		if role, err = signing.ParseRole(meta.Role); err != nil {
+			return err
+		}
+	}
+	params, err := signing.NewLindellSignParametersWithRole(ec, tss.NewPeerContext(pIDs), self, len(pIDs), 1, role)
+	if err != nil {
+		return err
+	}
+	logger, err := logging.New(logging.Config{Level: *logLevel, Encoding: "console"})
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+	params.SetLogger(logger)
+	params.SetSessionID(*sessionID)
+
+	state := &sessionState{
+		SessionID: *sessionID,
+		Role:      params.Role().String(),
+		Transport: cf.transport,
+		Secured:   cf.identityFile != "",
+		Self:      self.String(),
+		Peer:      peer.String(),
+		Hash:      hex.EncodeToString(hash),
+		Status:    stateRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	record := func() error {
+		if *stateFile == "" {
+			return nil
+		}
+		return writeJSONFile(*stateFile, state, 0600)
+	}
+	if err = record(); err != nil {
+		return err
+	}
+
+	sig, err := sign(ec, params, key, new(big.Int).SetBytes(hash), *sessionID, pIDs, self, peer, &cf, *timeout)
+	finished := time.Now().UTC()
+	state.FinishedAt = &finished
+	if err != nil {
+		state.Status, state.Error = stateFailed, err.Error()
+		record()
+		return err
+	}
+	state.Status, state.Signature = stateDone, sig
+	if err = record(); err != nil {
+		return err
+	}
+	if sig == nil {
+		fmt.Println("signed; the server side holds the signature")
+		return nil
+	}
+	return printJSON(sig)
+}
+
+func sign(ec elliptic.Curve, params *signing.LindellSignParameters, key keygen.LocalPartySaveData, msg *big.Int, sessionID string,
+	pIDs tss.SortedPartyIDs, self, peer *tss.PartyID, cf *connFlags, timeout time.Duration) (*signatureOutput, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, cleanup, err := cf.dial(ctx, sessionID, self, peer)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	defer conn.Close()
+
+	out, end := make(chan tss.Message, len(pIDs)), make(chan common.SignatureData, 1)
+	party := signing.NewLocalParty(msg, params, key, out, end)
+	data, err := transport.Run(ctx, conn, sessionID, pIDs, party, out, end)
+	if err != nil {
+		return nil, err
+	}
+	if data.GetR() == nil {
+		// the client's output is empty
+		return nil, nil
+	}
+	return newSignatureOutput(ec, &data)
+}
+
+func newSignatureOutput(ec elliptic.Curve, sig *common.SignatureData) (*signatureOutput, error) {
+	der, err := sigenc.DER(ec, sig)
+	if err != nil {
+		return nil, err
+	}
+	compact, err := sigenc.Compact(ec, sig)
+	if err != nil {
+		return nil, err
+	}
+	recid := 0
+	if len(sig.GetSignatureRecovery()) > 0 {
+		recid = int(sig.GetSignatureRecovery()[0])
+	}
+	return &signatureOutput{
+		R:          hex.EncodeToString(sig.GetR()),
+		S:          hex.EncodeToString(sig.GetS()),
+		RecoveryID: recid,
+		DER:        hex.EncodeToString(der),
+		Compact:    hex.EncodeToString(compact),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/signing/sigenc"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	keyFile := fs.String("key", "", "key share whose public key signed, or use -pub")
+	pubHex := fs.String("pub", "", "hex SEC1 public key, compressed or uncompressed")
+	curve := fs.String("curve", "secp256k1", "curve of the key: secp256k1 or secp256r1")
+	hashHex := fs.String("hash", "", "hex of the signed 32-byte message hash")
+	sigHex := fs.String("sig", "", "hex signature, DER or 64-byte compact r||s")
+	fs.Parse(args)
+
+	ec, err := parseCurve(*curve)
+	if err != nil {
+		return err
+	}
+	var pub *crypto.ECPoint
+	switch {
+	case *keyFile != "" && *pubHex == "":
+		key, err := loadKey(*keyFile)
+		if err != nil {
+			return err
+		}
+		pub = key.ECDSAPub
+	case *pubHex != "" && *keyFile == "":
+		if pub, err = parsePublicKey(ec, *pubHex); err != nil {
+			return err
+		}
+	default:
+		return errors.New("exactly one of -key and -pub is required")
+	}
+	hash, err := hex.DecodeString(strings.TrimPrefix(*hashHex, "0x"))
+	if err != nil || len(hash) != 32 {
+		return errors.New("-hash must be 32 bytes of hex")
+	}
+	sigBz, err := hex.DecodeString(strings.TrimPrefix(*sigHex, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid -sig: %v", err)
+	}
+	var sig *common.SignatureData
+	if len(sigBz) == 2*((ec.Params().BitSize+7)/8) {
+		sig, err = sigenc.ParseCompact(ec, sigBz)
+	} else {
+		sig, err = sigenc.ParseDER(ec, sigBz)
+	}
+	if err != nil {
+		return err
+	}
+
+	pk := ecdsa.PublicKey{Curve: ec, X: pub.X(), Y: pub.Y()}
+	if !ecdsa.Verify(&pk, hash, new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())) {
+		return errors.New("the signature does not verify")
+	}
+	fmt.Println("OK")
+	return nil
+}
+
+func parsePublicKey(ec elliptic.Curve, pubHex string) (*crypto.ECPoint, error) {
+	bz, err := hex.DecodeString(strings.TrimPrefix(pubHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+	size := (ec.Params().BitSize + 7) / 8
+	switch {
+	case len(bz) == 1+size && (bz[0] == 2 || bz[0] == 3):
+		name, err := ffi.CurveName(ec)
+		if err != nil {
+			return nil, err
+		}
+		return ffi.Point{Curve: name, Point: ffi.Bytes2Uint(bz)}.ECPoint(ec)
+	case len(bz) == 1+2*size && bz[0] == 4:
+		return crypto.NewECPoint(ec, new(big.Int).SetBytes(bz[1:1+size]), new(big.Int).SetBytes(bz[1+size:]))
+	default:
+		return nil, errors.New("the public key must be a SEC1 point")
+	}
+}
@@ -1,37 +1,205 @@
 extern crate libc;
-use crate::lindell::{
-    round_1, round_2, round_3, Round1Result, Round2Input, Round2Result, Round3Input, Round3Result,
-};
+use crate::lindell::{round_1, round_2, round_3, Round2Input, Round3Input};
+use curv::elliptic::curves::{Curve, Secp256k1, Secp256r1};
+use serde::{Deserialize, Serialize};
 use std::ffi::{CStr, CString};
+use std::panic::{self, AssertUnwindSafe};
+use zeroize::{Zeroize, Zeroizing};
 
-#[no_mangle]
-pub unsafe extern "C" fn lindell_round1() -> *const libc::c_char {
-    let round1_result: Round1Result = round_1();
+// large enough for the output of any round, so serde_json never grows the buffer and leaves a copy behind
+const OUTPUT_CAPACITY: usize = 16 * 1024;
 
-    let str_round1_rst = serde_json::to_string(&round1_result).unwrap();
-    return CString::new(str_round1_rst).unwrap().into_raw();
+// every input names the curve it was built for; the rest of the payload is parsed once the curve is known
+#[derive(Deserialize)]
+struct CurveSelector {
+    curve: String,
 }
 
-#[no_mangle]
-pub unsafe extern "C" fn lindell_round2(input: *const libc::c_char) -> *const libc::c_char {
+// a round's output, or the reason it failed, which is returned to Go as {"error": "..."}
+type RoundOutput = Result<Zeroizing<Vec<u8>>, String>;
+
+fn by_curve(
+    input: &str,
+    run_secp256k1: fn(&str) -> RoundOutput,
+    run_secp256r1: fn(&str) -> RoundOutput,
+) -> RoundOutput {
+    let selector: CurveSelector =
+        serde_json::from_str(input).map_err(|_| "input does not name a curve".to_string())?;
+    if selector.curve == Secp256k1::CURVE_NAME {
+        run_secp256k1(input)
+    } else if selector.curve == Secp256r1::CURVE_NAME {
+        run_secp256r1(input)
+    } else {
+        Err(format!("unsupported curve: {}", selector.curve))
+    }
+}
+
+// serde's errors may quote the input, which holds secret shares, so only the round is named
+fn parse<'a, T: Deserialize<'a>>(input: &'a str, round: &str) -> Result<T, String> {
+    serde_json::from_str(input).map_err(|_| format!("malformed {} input", round))
+}
+
+fn run_round1<E: Curve>(_input: &str) -> RoundOutput {
+    Ok(to_json(&round_1::<E>()))
+}
+
+fn run_round2<E: Curve>(input: &str) -> RoundOutput {
+    let round2_input: Round2Input<E> = parse(input, "round 2")?;
+    Ok(to_json(&round_2(round2_input)?))
+}
+
+fn run_round3<E: Curve>(input: &str) -> RoundOutput {
+    let round3_input: Round3Input<E> = parse(input, "round 3")?;
+    Ok(to_json(&round_3(round3_input)?))
+}
+
+#[derive(Serialize)]
+struct ErrorOutput {
+    error: String,
+}
+
+// run returns the output of a round for Go. A panic must not unwind into Go, so one left in lindellcore or its
+// dependencies is caught and returned as an error too.
+unsafe fn run(
+    input: *const libc::c_char,
+    round: impl FnOnce(&str) -> RoundOutput,
+) -> *const libc::c_char {
+    let output = panic::catch_unwind(AssertUnwindSafe(|| round(unsafe { input_str(input) }?)))
+        .unwrap_or_else(|_| Err("lindellcore panicked".to_string()));
+    match output {
+        Ok(json) => output_ptr(json),
+        Err(error) => output_ptr(to_json(&ErrorOutput { error })),
+    }
+}
+
+fn to_json<T: Serialize>(value: &T) -> Zeroizing<Vec<u8>> {
+    let mut json = Zeroizing::new(Vec::with_capacity(OUTPUT_CAPACITY));
+    serde_json::to_writer(&mut *json, value).unwrap();
+    json
+}
+
+// the input is borrowed rather than copied: the Go side owns it and wipes it
+unsafe fn input_str<'a>(input: *const libc::c_char) -> Result<&'a str, String> {
     let cstr_input = unsafe { CStr::from_ptr(input) };
-    let str_input = cstr_input.to_str().unwrap().to_string();
-    let round2_input: Round2Input = serde_json::from_str(&str_input).unwrap();
+    cstr_input
+        .to_str()
+        .map_err(|_| "input is not UTF-8".to_string())
+}
 
-    let round2_result: Round2Result = round_2(round2_input);
+// the output is handed over without a copy; it must be released with lindell_free, which wipes it
+fn output_ptr(json: Zeroizing<Vec<u8>>) -> *const libc::c_char {
+    // room for the terminating nul, so CString does not reallocate
+    let mut output = Vec::with_capacity(json.len() + 1);
+    output.extend_from_slice(&json);
+    CString::new(output).unwrap().into_raw()
+}
 
-    let str_round2_rst = serde_json::to_string(&round2_result).unwrap();
-    return CString::new(str_round2_rst).unwrap().into_raw();
+#[no_mangle]
+pub unsafe extern "C" fn lindell_round1(input: *const libc::c_char) -> *const libc::c_char {
+    run(input, |input| {
+        by_curve(input, run_round1::<Secp256k1>, run_round1::<Secp256r1>)
+    })
+}
+
+#[no_mangle]
+pub unsafe extern "C" fn lindell_round2(input: *const libc::c_char) -> *const libc::c_char {
+    run(input, |input| {
+        by_curve(input, run_round2::<Secp256k1>, run_round2::<Secp256r1>)
+    })
 }
 
 #[no_mangle]
 pub unsafe extern "C" fn lindell_round3(input: *const libc::c_char) -> *const libc::c_char {
-    let cstr_input = unsafe { CStr::from_ptr(input) };
-    let str_input = cstr_input.to_str().unwrap().to_string();
-    let round3_input: Round3Input = serde_json::from_str(&str_input).unwrap();
+    run(input, |input| {
+        by_curve(input, run_round3::<Secp256k1>, run_round3::<Secp256r1>)
+    })
+}
+
+// lindell_free wipes and releases the output of a round
+#[no_mangle]
+pub unsafe extern "C" fn lindell_free(output: *mut libc::c_char) {
+    if output.is_null() {
+        return;
+    }
+    let mut bytes = unsafe { CString::from_raw(output) }.into_bytes_with_nul();
+    bytes.zeroize();
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_output_is_released() {
+        let output = output_ptr(to_json(&serde_json::json!({"curve": "secp256k1"})));
+        let json = unsafe { CStr::from_ptr(output) }.to_str().unwrap().to_string();
+        assert_eq!(json, r#"{"curve":"secp256k1"}"#);
+        unsafe { lindell_free(output as *mut libc::c_char) };
+        unsafe { lindell_free(std::ptr::null_mut()) };
+    }
+
+    fn call(
+        round: unsafe extern "C" fn(*const libc::c_char) -> *const libc::c_char,
+        input: &str,
+    ) -> String {
+        let input = CString::new(input).unwrap();
+        let output = unsafe { round(input.as_ptr()) };
+        let json = unsafe { CStr::from_ptr(output) }
+            .to_str()
+            .unwrap()
+            .to_string();
+        unsafe { lindell_free(output as *mut libc::c_char) };
+        json
+    }
 
-    let round3_result: Round3Result = round_3(round3_input);
+    #[test]
+    fn test_errors_are_returned() {
+        assert_eq!(
+            call(lindell_round1, r#"{"curve":"ed25519"}"#),
+            r#"{"error":"unsupported curve: ed25519"}"#
+        );
+        assert_eq!(
+            call(lindell_round2, "not json"),
+            r#"{"error":"input does not name a curve"}"#
+        );
+        assert_eq!(
+            call(lindell_round3, r#"{"curve":"secp256k1","plain_sign":"1"}"#),
+            r#"{"error":"malformed round 3 input"}"#
+        );
+    }
 
-    let str_round3_rst = serde_json::to_string(&round3_result).unwrap();
-    return CString::new(str_round3_rst).unwrap().into_raw();
+    #[test]
+    fn test_invalid_proof_is_returned() {
+        let round1 = |curve: &str| -> serde_json::Value {
+            serde_json::from_str(&call(
+                lindell_round1,
+                &format!(r#"{{"curve":"{}"}}"#, curve),
+            ))
+            .unwrap()
+        };
+        for curve in ["secp256k1", "secp256r1"] {
+            // a well-formed proof of another ephemeral share
+            let mut first_message = round1(curve)["eph_party_one_first_message"].clone();
+            first_message["d_log_proof"] =
+                round1(curve)["eph_party_one_first_message"]["d_log_proof"].clone();
+            let input = serde_json::json!({
+                "curve": curve,
+                "paillier_n": "1",
+                "encrypted_share": "1",
+                "ec_key_pair_party2": round1(curve)["eph_ec_key_pair_party1"],
+                "message": "1234",
+                "eph_party_one_first_message": first_message,
+            });
+            let output: serde_json::Value =
+                serde_json::from_str(&call(lindell_round2, &input.to_string())).unwrap();
+            assert!(
+                output["error"]
+                    .as_str()
+                    .unwrap()
+                    .starts_with("party1 DLog proof failed"),
+                "{}",
+                output
+            );
+        }
+    }
 }
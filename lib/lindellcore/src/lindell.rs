@@ -1,25 +1,353 @@
-use curv::elliptic::curves::Point;
-use curv::BigInt;
-use multi_party_ecdsa::protocols::two_party_ecdsa::lindell_2017::party_one::{
-    EphEcKeyPair, EphKeyGenFirstMsg, Party1Private, Signature,
+//! Ephemeral key exchange and signing of Lindell 2017 two-party ECDSA, generic over the curve.
+//!
+//! The `lindell_2017` module of multi-party-ecdsa is pinned to secp256k1, so the signing
+//! steps are ported here on top of curv's generic `Point<E>` / `Scalar<E>`. Every type keeps
+//! the JSON shape of its multi-party-ecdsa counterpart, which is what the Go side parses.
+use curv::arithmetic::traits::*;
+use curv::cryptographic_primitives::commitments::hash_commitment::HashCommitment;
+use curv::cryptographic_primitives::commitments::traits::Commitment;
+use curv::cryptographic_primitives::hashing::{Digest, DigestExt};
+use curv::cryptographic_primitives::proofs::sigma_ec_ddh::{
+    ECDDHProof, ECDDHStatement, ECDDHWitness,
 };
-use multi_party_ecdsa::protocols::two_party_ecdsa::lindell_2017::party_two::{
-    EphKeyGenSecondMsg, PartialSig,
+use curv::elliptic::curves::{Curve, Point, Scalar};
+use curv::BigInt;
+use paillier::{
+    Add, Encrypt, EncryptionKey, MinimalEncryptionKey, Mul, Paillier, RawCiphertext, RawPlaintext,
 };
-use multi_party_ecdsa::protocols::two_party_ecdsa::lindell_2017::{party_one, party_two};
-use paillier::{Decrypt, EncryptionKey, MinimalEncryptionKey, Paillier, RawCiphertext};
 use serde::{Deserialize, Serialize};
-use std::str;
+use sha2::Sha256;
+use zeroize::Zeroize;
+
+const SECURITY_BITS: usize = 256;
+
+// ----- party one (server) ----- //
+
+#[derive(Serialize, Clone, Debug, Deserialize)]
+#[serde(bound = "")]
+pub struct EphEcKeyPair<E: Curve> {
+    pub public_share: Point<E>,
+    pub secret_share: Scalar<E>,
+}
+
+#[derive(Serialize, Clone, Debug, Deserialize)]
+#[serde(bound = "")]
+pub struct EphKeyGenFirstMsg<E: Curve> {
+    pub d_log_proof: ECDDHProof<E, Sha256>,
+    pub public_share: Point<E>,
+    pub c: Point<E>, // c = secret_share * base_point2
+}
+
+#[derive(Serialize, Clone, Debug, Deserialize)]
+pub struct Signature {
+    #[serde(with = "paillier::serialize::bigint")]
+    pub s: BigInt,
+    #[serde(with = "paillier::serialize::bigint")]
+    pub r: BigInt,
+}
+
+impl<E: Curve> EphKeyGenFirstMsg<E> {
+    pub fn create() -> (EphKeyGenFirstMsg<E>, EphEcKeyPair<E>) {
+        let (d_log_proof, public_share, c, secret_share) = prove_eph_key::<E>();
+        let ec_key_pair = EphEcKeyPair {
+            public_share: public_share.clone(),
+            secret_share,
+        };
+        (
+            EphKeyGenFirstMsg {
+                d_log_proof,
+                public_share,
+                c,
+            },
+            ec_key_pair,
+        )
+    }
+}
+
+pub fn verify_commitments_and_dlog_proof<E: Curve>(
+    party_two_first_message: &PartyTwoEphKeyGenFirstMsg,
+    party_two_second_message: &EphKeyGenSecondMsg<E>,
+) -> Result<(), String> {
+    let witness = &party_two_second_message.comm_witness;
+
+    let pk_commitment = HashCommitment::<Sha256>::create_commitment_with_user_defined_randomness(
+        &BigInt::from_bytes(witness.public_share.to_bytes(true).as_ref()),
+        &witness.pk_commitment_blind_factor,
+    );
+    let zk_pok_commitment =
+        HashCommitment::<Sha256>::create_commitment_with_user_defined_randomness(
+            &Sha256::new()
+                .chain_points([&witness.d_log_proof.a1, &witness.d_log_proof.a2])
+                .result_bigint(),
+            &witness.zk_pok_blind_factor,
+        );
+    if pk_commitment != party_two_first_message.pk_commitment
+        || zk_pok_commitment != party_two_first_message.zk_pok_commitment
+    {
+        return Err("party2 commitments do not open to the revealed values".to_string());
+    }
+
+    verify_eph_key(&witness.d_log_proof, &witness.public_share, &witness.c)
+}
+
+// s = k1^-1 * s' where s' is the decrypted partial signature of party two. s is returned as is, never normalised to
+// the lower half of the order: the caller derives the recovery id from the nonce point and flips it together with s.
+pub fn compute_signature<E: Curve>(
+    plain_sign: &BigInt,
+    ephemeral_local_share: &EphEcKeyPair<E>,
+    nonce_point: &Point<E>,
+) -> Signature {
+    let q = Scalar::<E>::group_order();
+    let rx = BigInt::modulus(&nonce_point.x_coord().expect("R is the point at infinity"), q);
+
+    let k1_inv = ephemeral_local_share
+        .secret_share
+        .invert()
+        .expect("ephemeral secret share is zero");
+    let s = (Scalar::<E>::from_bigint(plain_sign) * k1_inv).to_bigint();
+
+    Signature { s, r: rx }
+}
+
+// ----- party two (client) ----- //
+
+#[derive(Serialize, Clone, Debug, Deserialize)]
+#[serde(bound = "")]
+pub struct EcKeyPair<E: Curve> {
+    pub public_share: Point<E>,
+    pub secret_share: Scalar<E>,
+}
+
+#[derive(Serialize, Clone, Debug, Deserialize)]
+pub struct PartyTwoEphKeyGenFirstMsg {
+    #[serde(with = "paillier::serialize::bigint")]
+    pub pk_commitment: BigInt,
+    #[serde(with = "paillier::serialize::bigint")]
+    pub zk_pok_commitment: BigInt,
+}
 
 #[derive(Serialize, Clone, Debug, Deserialize)]
-pub struct Round1Result {
-    pub eph_party_one_first_message: EphKeyGenFirstMsg,
-    pub eph_ec_key_pair_party1: EphEcKeyPair,
+#[serde(bound = "")]
+pub struct EphCommWitness<E: Curve> {
+    #[serde(with = "paillier::serialize::bigint")]
+    pub pk_commitment_blind_factor: BigInt,
+    #[serde(with = "paillier::serialize::bigint")]
+    pub zk_pok_blind_factor: BigInt,
+    pub public_share: Point<E>,
+    pub d_log_proof: ECDDHProof<E, Sha256>,
+    pub c: Point<E>, // c = secret_share * base_point2
 }
 
-pub fn round_1() -> Round1Result {
-    let (eph_party_one_first_message, eph_ec_key_pair_party1) =
-        party_one::EphKeyGenFirstMsg::create();
+#[derive(Serialize, Clone, Debug, Deserialize)]
+#[serde(bound = "")]
+pub struct EphKeyGenSecondMsg<E: Curve> {
+    pub comm_witness: EphCommWitness<E>,
+}
+
+#[derive(Serialize, Clone, Debug, Deserialize)]
+pub struct PartialSig {
+    #[serde(with = "paillier::serialize::bigint")]
+    pub c3: BigInt,
+}
+
+pub fn create_commitments<E: Curve>() -> (
+    PartyTwoEphKeyGenFirstMsg,
+    EphCommWitness<E>,
+    EphEcKeyPair<E>,
+) {
+    let (d_log_proof, public_share, c, secret_share) = prove_eph_key::<E>();
+
+    let pk_commitment_blind_factor = BigInt::sample(SECURITY_BITS);
+    let pk_commitment = HashCommitment::<Sha256>::create_commitment_with_user_defined_randomness(
+        &BigInt::from_bytes(public_share.to_bytes(true).as_ref()),
+        &pk_commitment_blind_factor,
+    );
+    let zk_pok_blind_factor = BigInt::sample(SECURITY_BITS);
+    let zk_pok_commitment =
+        HashCommitment::<Sha256>::create_commitment_with_user_defined_randomness(
+            &Sha256::new()
+                .chain_points([&d_log_proof.a1, &d_log_proof.a2])
+                .result_bigint(),
+            &zk_pok_blind_factor,
+        );
+
+    let ec_key_pair = EphEcKeyPair {
+        public_share: public_share.clone(),
+        secret_share,
+    };
+    (
+        PartyTwoEphKeyGenFirstMsg {
+            pk_commitment,
+            zk_pok_commitment,
+        },
+        EphCommWitness {
+            pk_commitment_blind_factor,
+            zk_pok_blind_factor,
+            public_share,
+            d_log_proof,
+            c,
+        },
+        ec_key_pair,
+    )
+}
+
+// c3 = Enc(rho * q + k2^-1 * (m + r * x2)) + k2^-1 * r * Enc(x1), with x = x1 + x2 additively shared
+// and r the x coordinate of nonce_point
+pub fn compute_partial_sig<E: Curve>(
+    ek: &EncryptionKey,
+    encrypted_share: &BigInt,
+    local_share: &EcKeyPair<E>,
+    ephemeral_local_share: &EphEcKeyPair<E>,
+    nonce_point: &Point<E>,
+    message: &BigInt,
+) -> PartialSig {
+    let q = Scalar::<E>::group_order();
+    let rx = BigInt::modulus(&nonce_point.x_coord().expect("R is the point at infinity"), q);
+
+    let mut rho = BigInt::sample_below(&(q * q));
+    let mut k2 = ephemeral_local_share.secret_share.to_bigint();
+    let mut x2 = local_share.secret_share.to_bigint();
+    let mut k2_inv = BigInt::mod_inv(&k2, q).expect("ephemeral secret share is zero");
+    let mut k2_inv_m = BigInt::mod_mul(&k2_inv, message, q);
+    let mut k2_inv_r = BigInt::mod_mul(&k2_inv, &rx, q);
+    let mut k2_inv_r_x2 = BigInt::mod_mul(&k2_inv_r, &x2, q);
+
+    let mut partial_sig = &rho * q + BigInt::mod_add(&k2_inv_m, &k2_inv_r_x2, q);
+    let c1 = Paillier::encrypt(ek, RawPlaintext::from(&partial_sig));
+    let c2 = Paillier::mul(
+        ek,
+        RawCiphertext::from(encrypted_share.clone()),
+        RawPlaintext::from(&k2_inv_r),
+    );
+    let c3 = Paillier::add(ek, c2, c1).0.into_owned();
+
+    // curv's scalars wipe themselves when dropped, the big integers derived from them do not
+    for secret in [
+        &mut rho,
+        &mut k2,
+        &mut x2,
+        &mut k2_inv,
+        &mut k2_inv_m,
+        &mut k2_inv_r,
+        &mut k2_inv_r_x2,
+        &mut partial_sig,
+    ] {
+        secret.zeroize();
+    }
+
+    PartialSig { c3 }
+}
+
+// ----- shared ----- //
+
+// fresh ephemeral share with an ECDDH proof that public_share and c share the same discrete log
+fn prove_eph_key<E: Curve>() -> (ECDDHProof<E, Sha256>, Point<E>, Point<E>, Scalar<E>) {
+    let base = Point::<E>::generator();
+    let secret_share = Scalar::<E>::random();
+    let public_share = base * &secret_share;
+    let h = Point::<E>::base_point2();
+    let c = h * &secret_share;
+
+    let w = ECDDHWitness {
+        x: secret_share.clone(),
+    };
+    let delta = ECDDHStatement {
+        g1: base.to_point(),
+        h1: public_share.clone(),
+        g2: h.clone(),
+        h2: c.clone(),
+    };
+    let d_log_proof = ECDDHProof::prove(&w, &delta);
+    (d_log_proof, public_share, c, secret_share)
+}
+
+fn verify_eph_key<E: Curve>(
+    d_log_proof: &ECDDHProof<E, Sha256>,
+    public_share: &Point<E>,
+    c: &Point<E>,
+) -> Result<(), String> {
+    let delta = ECDDHStatement {
+        g1: Point::<E>::generator().to_point(),
+        h1: public_share.clone(),
+        g2: Point::<E>::base_point2().clone(),
+        h2: c.clone(),
+    };
+    d_log_proof
+        .verify(&delta)
+        .map_err(|_| "ECDDH proof of the ephemeral share failed".to_string())
+}
+
+// Chaum-Pedersen proof that h1 = x * g1 and h2 = x * g2. The challenge is SHA-256 over the SEC1-compressed
+// g1, h1, g2, h2, a1, a2 reduced mod q, the same as zkp.DLEQProof on the Go side.
+#[derive(Serialize, Clone, Debug, Deserialize)]
+#[serde(bound = "")]
+pub struct DLEqProof<E: Curve> {
+    pub a1: Point<E>,
+    pub a2: Point<E>,
+    pub z: Scalar<E>,
+}
+
+impl<E: Curve> DLEqProof<E> {
+    pub fn prove(
+        x: &Scalar<E>,
+        g1: &Point<E>,
+        h1: &Point<E>,
+        g2: &Point<E>,
+        h2: &Point<E>,
+    ) -> DLEqProof<E> {
+        let w = Scalar::<E>::random();
+        let a1 = g1 * &w;
+        let a2 = g2 * &w;
+        let e = dleq_challenge(&[g1, h1, g2, h2, &a1, &a2]);
+        let z = &w + &(&e * x);
+        DLEqProof { a1, a2, z }
+    }
+
+    pub fn verify(
+        &self,
+        g1: &Point<E>,
+        h1: &Point<E>,
+        g2: &Point<E>,
+        h2: &Point<E>,
+    ) -> Result<(), String> {
+        let e = dleq_challenge(&[g1, h1, g2, h2, &self.a1, &self.a2]);
+        if g1 * &self.z == &self.a1 + &(h1 * &e) && g2 * &self.z == &self.a2 + &(h2 * &e) {
+            Ok(())
+        } else {
+            Err("DLEQ proof failed".to_string())
+        }
+    }
+}
+
+fn dleq_challenge<E: Curve>(points: &[&Point<E>]) -> Scalar<E> {
+    let mut hasher = Sha256::new();
+    for point in points {
+        hasher.update(point.to_bytes(true).as_ref());
+    }
+    Scalar::<E>::from_bigint(&BigInt::from_bytes(hasher.finalize().as_ref()))
+}
+
+// In adaptor mode the signing nonce is R = k1 * k2 * Y instead of k1 * k2 * G. Party two proves with k2 that
+// r_hat = k2 * R1 and r = k2 * (k1 * Y) share a discrete log, so r_hat = k * G and r = k * Y.
+#[derive(Serialize, Clone, Debug, Deserialize)]
+#[serde(bound = "")]
+pub struct AdaptorNonce<E: Curve> {
+    pub r_hat: Point<E>,
+    pub r: Point<E>,
+    pub proof: DLEqProof<E>,
+}
+
+// ----- rounds exposed through ffi ----- //
+
+#[derive(Serialize, Clone, Debug, Deserialize)]
+#[serde(bound = "")]
+pub struct Round1Result<E: Curve> {
+    pub eph_party_one_first_message: EphKeyGenFirstMsg<E>,
+    pub eph_ec_key_pair_party1: EphEcKeyPair<E>,
+}
+
+pub fn round_1<E: Curve>() -> Round1Result<E> {
+    let (eph_party_one_first_message, eph_ec_key_pair_party1) = EphKeyGenFirstMsg::<E>::create();
 
     return Round1Result {
         eph_party_one_first_message,
@@ -28,61 +356,94 @@ pub fn round_1() -> Round1Result {
 }
 
 #[derive(Debug, Clone, Serialize, Deserialize)]
-pub struct Round2Input {
+#[serde(bound = "")]
+pub struct Round2Input<E: Curve> {
     #[serde(with = "paillier::serialize::bigint")]
     pub paillier_n: BigInt,
     #[serde(with = "paillier::serialize::bigint")]
     pub encrypted_share: BigInt,
-    pub ec_key_pair_party2: party_two::EcKeyPair,
+    pub ec_key_pair_party2: EcKeyPair<E>,
     #[serde(with = "paillier::serialize::bigint")]
     pub message: BigInt,
-    pub eph_party_one_first_message: EphKeyGenFirstMsg,
+    pub eph_party_one_first_message: EphKeyGenFirstMsg<E>,
+    // k1 * Y, set only when producing an adaptor pre-signature
+    #[serde(default)]
+    pub adaptor_point: Option<Point<E>>,
 }
 
 #[derive(Debug, Clone, Serialize, Deserialize)]
-pub struct Round2Result {
-    pub eph_party_two_first_message: party_two::EphKeyGenFirstMsg,
-    pub eph_party_two_second_message: EphKeyGenSecondMsg,
+#[serde(bound = "")]
+pub struct Round2Result<E: Curve> {
+    pub eph_party_two_first_message: PartyTwoEphKeyGenFirstMsg,
+    pub eph_party_two_second_message: EphKeyGenSecondMsg<E>,
     pub partial_sig: PartialSig,
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub adaptor_nonce: Option<AdaptorNonce<E>>,
 }
 
-pub fn round_2(input: Round2Input) -> Round2Result {
-    let party2_private = party_two::Party2Private::set_private_key(&input.ec_key_pair_party2); // init
-
+// round_2 and round_3 return an error when the peer's message does not verify, since a panic cannot unwind across the
+// FFI boundary
+pub fn round_2<E: Curve>(input: Round2Input<E>) -> Result<Round2Result<E>, String> {
     let (eph_party_two_first_message, eph_comm_witness, eph_ec_key_pair_party2) =
-        party_two::EphKeyGenFirstMsg::create_commitments(); // round2-1
+        create_commitments::<E>(); // round2-1
 
-    let eph_party_two_second_message = party_two::EphKeyGenSecondMsg::verify_and_decommit(
-        eph_comm_witness,
-        &input.eph_party_one_first_message,
+    let party_one_first_message = &input.eph_party_one_first_message;
+    verify_eph_key(
+        &party_one_first_message.d_log_proof,
+        &party_one_first_message.public_share,
+        &party_one_first_message.c,
     )
-    .expect("party1 DLog proof failed"); // round2-2
+    .map_err(|err| format!("party1 DLog proof failed: {}", err))?; // round2-2
+    let eph_party_two_second_message = EphKeyGenSecondMsg {
+        comm_witness: eph_comm_witness,
+    };
+
+    let k2 = &eph_ec_key_pair_party2.secret_share;
+    let r1 = &party_one_first_message.public_share;
+    let (nonce_point, adaptor_nonce) = match &input.adaptor_point {
+        None => (r1 * k2, None),
+        Some(r1_y) => {
+            let r_hat = r1 * k2;
+            let r = r1_y * k2;
+            let proof = DLEqProof::prove(k2, r1, &r_hat, r1_y, &r);
+            (r.clone(), Some(AdaptorNonce { r_hat, r, proof }))
+        }
+    };
 
     let ek = EncryptionKey::from(MinimalEncryptionKey {
         n: input.paillier_n,
     });
-    let partial_sig = party_two::PartialSig::compute_add(
+    let partial_sig = compute_partial_sig(
         &ek,
         &input.encrypted_share,
-        &party2_private,
+        &input.ec_key_pair_party2,
         &eph_ec_key_pair_party2,
-        &input.eph_party_one_first_message.public_share,
+        &nonce_point,
         &input.message,
     ); // round2-3
 
-    return Round2Result {
+    return Ok(Round2Result {
         eph_party_two_first_message,
         eph_party_two_second_message,
         partial_sig,
-    };
+        adaptor_nonce,
+    });
 }
 
 #[derive(Debug, Clone, Serialize, Deserialize)]
-pub struct Round3Input {
+#[serde(bound = "")]
+pub struct Round3Input<E: Curve> {
     #[serde(with = "paillier::serialize::bigint")]
     pub plain_sign: BigInt,
-    pub r1_rst: Round1Result,
-    pub r2_rst: Round2Result,
+    pub r1_rst: Round1Result<E>,
+    pub r2_rst: Round2Result<E>,
+}
+
+// the decrypted partial signature together with the signature reveals k1
+impl<E: Curve> Drop for Round3Input<E> {
+    fn drop(&mut self) {
+        self.plain_sign.zeroize();
+    }
 }
 
 #[derive(Debug, Clone, Serialize, Deserialize)]
@@ -90,59 +451,352 @@ pub struct Round3Result {
     pub signature: Signature,
 }
 
-pub fn round_3(input: Round3Input) -> Round3Result {
-    let _eph_party_one_second_message =
-        party_one::EphKeyGenSecondMsg::verify_commitments_and_dlog_proof(
-            &input.r2_rst.eph_party_two_first_message,
-            &input.r2_rst.eph_party_two_second_message,
-        )
-        .expect("failed to verify commitments and DLog proof");
-
-    let sig = party_one::Signature::compute_with_plain_msg(
-        &input.plain_sign,
-        &input.r1_rst.eph_ec_key_pair_party1,
-        &input
-            .r2_rst
-            .eph_party_two_second_message
-            .comm_witness
-            .public_share,
-    );
+pub fn round_3<E: Curve>(input: Round3Input<E>) -> Result<Round3Result, String> {
+    verify_commitments_and_dlog_proof(
+        &input.r2_rst.eph_party_two_first_message,
+        &input.r2_rst.eph_party_two_second_message,
+    )
+    .map_err(|err| format!("failed to verify commitments and DLog proof: {}", err))?;
+
+    let eph_ec_key_pair_party1 = &input.r1_rst.eph_ec_key_pair_party1;
+    let r_hat = &input
+        .r2_rst
+        .eph_party_two_second_message
+        .comm_witness
+        .public_share
+        * &eph_ec_key_pair_party1.secret_share;
+    let sig = match &input.r2_rst.adaptor_nonce {
+        None => compute_signature(&input.plain_sign, eph_ec_key_pair_party1, &r_hat),
+        Some(adaptor_nonce) => {
+            if adaptor_nonce.r_hat != r_hat {
+                return Err("adaptor nonce does not match the ephemeral key exchange".to_string());
+            }
+            compute_signature(&input.plain_sign, eph_ec_key_pair_party1, &adaptor_nonce.r)
+        }
+    };
 
-    return Round3Result { signature: sig };
+    return Ok(Round3Result { signature: sig });
 }
 
-#[test]
-fn test_d_log_proof_party_two_party_one() {
-    let rst1 = round_1();
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use curv::elliptic::curves::{Secp256k1, Secp256r1};
+    use paillier::{Decrypt, KeyGeneration};
 
-    let input2_str = "{\"paillier_n\":\"15453108137667850587026384497369588865052224775570073725993309326495366523991669206594399207496819200050463624967544836174921258210946815255126405995904187130079198265007890408276492485325130874361633623833170726648447821755086459130526921389779073845890531117209524092785802556441822851554867095637818673869236231078603663281248644223479862002646466553476539972565546380447956264804378369256642411505855507008568567051868798770287109216353835717570031675747781261606712855763686428159564736800030834961303392259612397748382955459413473582577117823704304895661889400916964043543607902849223273926561334516746628034783\",\"encrypted_share\":\"224782462767766316063514392915806803306948787831544068973106627706499051452139613639860330722317327231801136772875818625329509582138471570913018611340987335851345926453409529027964142318152523480564343140794564136207674077934544806177748578103733236631142482097993345774944014318734171435579799308278354425452731363685470113467620065957585557703278552337226286241636304322746729479429377673503869788349324069441090748949647067805881135558245256898168503596644535903939834008019863670190068405477143199106354272993395238189346830967717369286699932465234794587745811912814260032513257800001260623967943711074315025288622738571057221532810544685576697488701226489425417565322245556930841349991943548120033411697854106657816395326286833470470590699754973034969769756191564341870048505805606350610158175890189343765725466567143323731777466985177176415400635104213585105711894882617325771852764384254004593570593972516665035267382121098352474159297496430199004169572901446142258301092845625962480565264991329540467223408195134516642080929962584028537381251245980851978631797054206885173854379823630768218449251738815245723084643538580652139558956320113455127612786581302487358347604942320954133124792668132363172076784375922534482460882429\",\"ec_key_pair_party2\":{\"public_share\":{\"curve\":\"secp256k1\",\"point\":[2,238,123,166,171,233,61,177,230,244,73,80,218,189,232,247,6,118,49,191,3,114,46,145,0,143,236,252,236,234,27,178,99]},\"secret_share\":{\"curve\":\"secp256k1\",\"scalar\":[143,203,149,110,22,138,19,21,48,112,240,197,233,13,97,186,27,140,87,111,83,127,48,89,166,106,107,253,143,59,193,0]}},\"message\":\"1234\",\"eph_party_one_first_message\":{\"d_log_proof\":{\"a1\":{\"curve\":\"secp256k1\",\"point\":[3,53,217,97,4,6,87,153,130,83,57,243,224,191,30,222,5,16,153,132,91,2,223,224,55,125,82,239,102,228,134,243,116]},\"a2\":{\"curve\":\"secp256k1\",\"point\":[3,170,79,90,170,129,186,111,62,149,168,2,119,112,202,77,132,57,77,57,222,88,191,110,38,107,169,198,149,15,111,76,207]},\"z\":{\"curve\":\"secp256k1\",\"scalar\":[40,221,85,146,35,105,174,65,212,190,15,242,67,170,131,60,122,183,233,248,142,173,137,64,121,49,244,255,107,87,62,202]}},\"public_share\":{\"curve\":\"secp256k1\",\"point\":[3,211,151,28,250,107,5,240,68,143,198,212,167,167,61,170,37,17,14,1,101,127,185,42,239,198,86,100,57,109,247,174,179]},\"c\":{\"curve\":\"secp256k1\",\"point\":[2,175,41,153,212,173,142,253,56,197,124,116,175,15,106,133,122,101,177,102,166,181,41,118,125,117,34,67,128,167,157,21,239]}}}";
-    let mut input2: Round2Input = serde_json::from_str(input2_str).unwrap();
-    input2.eph_party_one_first_message.d_log_proof =
-        rst1.eph_party_one_first_message.d_log_proof.clone();
-    input2.eph_party_one_first_message.public_share =
-        rst1.eph_party_one_first_message.public_share.clone();
-    input2.eph_party_one_first_message.c = rst1.eph_party_one_first_message.c.clone();
+    fn sign_and_verify<E: Curve>() {
+        let (ek, dk) = Paillier::keypair().keys();
+        let x1 = Scalar::<E>::random();
+        let x2 = Scalar::<E>::random();
+        let pubkey = Point::<E>::generator() * &(&x1 + &x2);
+        let message = BigInt::from(1234);
 
-    let msg = input2.message.clone();
-    let pub_share = input2.ec_key_pair_party2.public_share.clone();
-    let rst2 = round_2(input2);
+        let rst1 = round_1::<E>();
+        let encrypted_share =
+            Paillier::encrypt(&ek, RawPlaintext::from(x1.to_bigint())).0.into_owned();
+        let rst2 = round_2(Round2Input {
+            paillier_n: ek.n.clone(),
+            encrypted_share,
+            ec_key_pair_party2: EcKeyPair {
+                public_share: Point::<E>::generator() * &x2,
+                secret_share: x2,
+            },
+            message: message.clone(),
+            eph_party_one_first_message: rst1.eph_party_one_first_message.clone(),
+            adaptor_point: None,
+        })
+        .unwrap();
 
-    let party1_key_str = "{\"x1\":{\"curve\":\"secp256k1\",\"scalar\":[18,145,53,92,155,177,161,193,151,116,192,33,113,184,47,23,76,102,5,110,75,79,154,76,77,9,28,149,22,235,214,209]},\"paillier_priv\":{\"p\":\"137011065195882922300331368001124313983722327643321832355387496023829485899993048330000017579700347702398965702182351207641733095878161311153387201159340422359915255532041342700874486900841091388351490714037782113844110184190722438431436946754041452387471603524895220159558480675144819323831804616587549636411\",\"q\":\"112787300175899987568204194294559966800216308794713588076591241431546999257357980322596241227313565675472577125532179773886338223615893842734094102260801848172811071446946449235647980815311732428704660910538769529774854227799108753801288525671836232275839107991168264533934073486258720450292271840905837755053\"},\"c_key_randomness\":\"72700639327511104965518183215788693417369684139372843655204273831077899050196979418250116449888337729314601081637387142524647477349366917575113493550047580642932173550950821754921369061449425094934792628192156624456476886854898636533282240253993802283810929414745024109725595874942824203071611125645239075164722057779961512009564778405973532672913311407995891025091185476921004862378022800977918272469643004259540878397000284510353880925131073595282423731088207150616088814003315811649626045390727319979557248704425194548209877025032762392807515163442988899619059048083923052903643887749570758410138238321095016990\"}";
-    let party1_key: Party1Private = serde_json::from_str(party1_key_str).unwrap();
-    let plain_text = Paillier::decrypt(
-        &party1_key.paillier_priv,
-        &RawCiphertext::from(rst2.clone().partial_sig.c3),
-    )
-    .0;
+        let plain_sign = Paillier::decrypt(&dk, RawCiphertext::from(rst2.partial_sig.c3.clone()))
+            .0
+            .into_owned();
+        let rst3 = round_3(Round3Input {
+            plain_sign,
+            r1_rst: rst1,
+            r2_rst: rst2,
+        })
+        .unwrap();
 
-    let input3 = Round3Input {
-        plain_sign: plain_text.into_owned(),
-        r1_rst: rst1,
-        r2_rst: rst2,
-    };
-    let rst3 = round_3(input3);
+        // r == (s^-1 * m * G + s^-1 * r * Q).x
+        let q = Scalar::<E>::group_order();
+        let s_inv = Scalar::<E>::from_bigint(&rst3.signature.s).invert().unwrap();
+        let u1 = Point::<E>::generator() * &(Scalar::<E>::from_bigint(&message) * &s_inv);
+        let u2 = &pubkey * &(Scalar::<E>::from_bigint(&rst3.signature.r) * &s_inv);
+        let rx = BigInt::modulus(&(u1 + u2).x_coord().unwrap(), q);
+        assert_eq!(rx, rst3.signature.r, "invalid signature");
+    }
+
+    fn adaptor_sign_and_adapt<E: Curve>() {
+        let (ek, dk) = Paillier::keypair().keys();
+        let x1 = Scalar::<E>::random();
+        let x2 = Scalar::<E>::random();
+        let pubkey = Point::<E>::generator() * &(&x1 + &x2);
+        let message = BigInt::from(1234);
+        let y = Scalar::<E>::random();
+        let big_y = Point::<E>::generator() * &y;
+
+        let rst1 = round_1::<E>();
+        let r1_y = &big_y * &rst1.eph_ec_key_pair_party1.secret_share;
+        let encrypted_share =
+            Paillier::encrypt(&ek, RawPlaintext::from(x1.to_bigint())).0.into_owned();
+        let rst2 = round_2(Round2Input {
+            paillier_n: ek.n.clone(),
+            encrypted_share,
+            ec_key_pair_party2: EcKeyPair {
+                public_share: Point::<E>::generator() * &x2,
+                secret_share: x2,
+            },
+            message: message.clone(),
+            eph_party_one_first_message: rst1.eph_party_one_first_message.clone(),
+            adaptor_point: Some(r1_y.clone()),
+        })
+        .unwrap();
+        let adaptor_nonce = rst2.adaptor_nonce.clone().expect("missing adaptor nonce");
+        adaptor_nonce
+            .proof
+            .verify(
+                &rst1.eph_party_one_first_message.public_share,
+                &adaptor_nonce.r_hat,
+                &r1_y,
+                &adaptor_nonce.r,
+            )
+            .expect("invalid adaptor nonce proof");
+
+        let plain_sign = Paillier::decrypt(&dk, RawCiphertext::from(rst2.partial_sig.c3.clone()))
+            .0
+            .into_owned();
+        let rst3 = round_3(Round3Input {
+            plain_sign,
+            r1_rst: rst1,
+            r2_rst: rst2,
+        })
+        .unwrap();
+
+        // the pre-signature verifies against R_hat = k * G, the adapted s = s' * y^-1 against R = k * Y
+        let q = Scalar::<E>::group_order();
+        let s_tag = Scalar::<E>::from_bigint(&rst3.signature.s);
+        let s_tag_inv = s_tag.invert().unwrap();
+        let r = Scalar::<E>::from_bigint(&rst3.signature.r);
+        let u1 = Point::<E>::generator() * &(Scalar::<E>::from_bigint(&message) * &s_tag_inv);
+        let u2 = &pubkey * &(&r * &s_tag_inv);
+        assert!(u1 + u2 == adaptor_nonce.r_hat, "invalid pre-signature");
+
+        let s = &s_tag * &y.invert().unwrap();
+        let s_inv = s.invert().unwrap();
+        let u1 = Point::<E>::generator() * &(Scalar::<E>::from_bigint(&message) * &s_inv);
+        let u2 = &pubkey * &(&r * &s_inv);
+        let rx = BigInt::modulus(&(u1 + u2).x_coord().unwrap(), q);
+        assert_eq!(rx, rst3.signature.r, "invalid adapted signature");
+    }
+
+    fn adaptor_nonce_mismatch<E: Curve>() {
+        let (ek, dk) = Paillier::keypair().keys();
+        let x2 = Scalar::<E>::random();
+        let big_y = Point::<E>::generator() * &Scalar::<E>::random();
+
+        let rst1 = round_1::<E>();
+        let r1_y = &big_y * &rst1.eph_ec_key_pair_party1.secret_share;
+        let encrypted_share =
+            Paillier::encrypt(&ek, RawPlaintext::from(BigInt::from(1))).0.into_owned();
+        let mut rst2 = round_2(Round2Input {
+            paillier_n: ek.n.clone(),
+            encrypted_share,
+            ec_key_pair_party2: EcKeyPair {
+                public_share: Point::<E>::generator() * &x2,
+                secret_share: x2,
+            },
+            message: BigInt::from(1234),
+            eph_party_one_first_message: rst1.eph_party_one_first_message.clone(),
+            adaptor_point: Some(r1_y),
+        })
+        .unwrap();
+        let plain_sign = Paillier::decrypt(&dk, RawCiphertext::from(rst2.partial_sig.c3.clone()))
+            .0
+            .into_owned();
+        let nonce = rst2.adaptor_nonce.as_mut().unwrap();
+        nonce.r_hat = &nonce.r_hat + &Point::<E>::generator().to_point();
+
+        let rst3 = round_3(Round3Input {
+            plain_sign,
+            r1_rst: rst1,
+            r2_rst: rst2,
+        });
+        assert!(rst3.is_err(), "a client-chosen r_hat must be an error, not a panic");
+    }
+
+    fn round_2_input<E: Curve>(ek: &EncryptionKey, rst1: &Round1Result<E>) -> Round2Input<E> {
+        let x2 = Scalar::<E>::random();
+        Round2Input {
+            paillier_n: ek.n.clone(),
+            encrypted_share: Paillier::encrypt(ek, RawPlaintext::from(BigInt::from(1)))
+                .0
+                .into_owned(),
+            ec_key_pair_party2: EcKeyPair {
+                public_share: Point::<E>::generator() * &x2,
+                secret_share: x2,
+            },
+            message: BigInt::from(1234),
+            eph_party_one_first_message: rst1.eph_party_one_first_message.clone(),
+            adaptor_point: None,
+        }
+    }
+
+    // a well-formed proof of another ephemeral share is an error of round 2, not a panic
+    fn invalid_dlog_proof<E: Curve>() {
+        let (ek, _) = Paillier::keypair().keys();
+        let rst1 = round_1::<E>();
+        let mut input = round_2_input(&ek, &rst1);
+        input.eph_party_one_first_message.d_log_proof =
+            round_1::<E>().eph_party_one_first_message.d_log_proof;
+        assert!(round_2(input).is_err());
+    }
+
+    // a well-formed opening of another commitment is an error of round 3, not a panic
+    fn invalid_commitment_opening<E: Curve>() {
+        let (ek, dk) = Paillier::keypair().keys();
+        let rst1 = round_1::<E>();
+        let mut rst2 = round_2(round_2_input(&ek, &rst1)).unwrap();
+        let plain_sign = Paillier::decrypt(&dk, RawCiphertext::from(rst2.partial_sig.c3.clone()))
+            .0
+            .into_owned();
+        let (_, other, _) = create_commitments::<E>();
+        rst2.eph_party_two_second_message.comm_witness = other;
+
+        let rst3 = round_3(Round3Input {
+            plain_sign,
+            r1_rst: rst1,
+            r2_rst: rst2,
+        });
+        assert!(rst3.is_err());
+    }
+
+    fn eph_key_proof<E: Curve>() {
+        let (msg, key_pair) = EphKeyGenFirstMsg::<E>::create();
+        assert!(msg.public_share == Point::<E>::generator() * &key_pair.secret_share);
+        assert!(verify_eph_key(&msg.d_log_proof, &msg.public_share, &msg.c).is_ok());
+
+        let g = Point::<E>::generator().to_point();
+        assert!(verify_eph_key(&msg.d_log_proof, &msg.public_share, &(&msg.c + &g)).is_err());
+        assert!(verify_eph_key(&msg.d_log_proof, &(&msg.public_share + &g), &msg.c).is_err());
+        let (other, _) = EphKeyGenFirstMsg::<E>::create();
+        assert!(
+            verify_eph_key(&other.d_log_proof, &msg.public_share, &msg.c).is_err(),
+            "a proof of another share"
+        );
+    }
+
+    fn commitments<E: Curve>() {
+        let (first, witness, key_pair) = create_commitments::<E>();
+        assert!(witness.public_share == key_pair.public_share);
+        let second = EphKeyGenSecondMsg {
+            comm_witness: witness,
+        };
+        assert!(verify_commitments_and_dlog_proof(&first, &second).is_ok());
+
+        let mut reblinded = second.clone();
+        reblinded.comm_witness.pk_commitment_blind_factor = BigInt::from(1);
+        assert!(verify_commitments_and_dlog_proof(&first, &reblinded).is_err());
+        let mut reblinded = second.clone();
+        reblinded.comm_witness.zk_pok_blind_factor = BigInt::from(1);
+        assert!(verify_commitments_and_dlog_proof(&first, &reblinded).is_err());
+
+        // another share with a valid proof of its own does not open the commitments
+        let (_, other, _) = create_commitments::<E>();
+        let mut swapped = second.clone();
+        swapped.comm_witness.public_share = other.public_share.clone();
+        swapped.comm_witness.d_log_proof = other.d_log_proof.clone();
+        swapped.comm_witness.c = other.c.clone();
+        assert!(verify_commitments_and_dlog_proof(&first, &swapped).is_err());
+    }
+
+    fn dleq_proof<E: Curve>() {
+        let x = Scalar::<E>::random();
+        let g1 = Point::<E>::generator().to_point();
+        let g2 = Point::<E>::generator() * &Scalar::<E>::random();
+        let (h1, h2) = (&g1 * &x, &g2 * &x);
+        let proof = DLEqProof::prove(&x, &g1, &h1, &g2, &h2);
+        assert!(proof.verify(&g1, &h1, &g2, &h2).is_ok());
+        assert!(proof.verify(&g1, &h1, &g2, &(&h2 + &g1)).is_err());
+        assert!(proof.verify(&g2, &h2, &g1, &h1).is_err(), "the statement is ordered");
+    }
+
+    #[test]
+    fn test_eph_key_proof_secp256k1() {
+        eph_key_proof::<Secp256k1>();
+    }
+
+    #[test]
+    fn test_eph_key_proof_secp256r1() {
+        eph_key_proof::<Secp256r1>();
+    }
+
+    #[test]
+    fn test_commitments_secp256k1() {
+        commitments::<Secp256k1>();
+    }
+
+    #[test]
+    fn test_commitments_secp256r1() {
+        commitments::<Secp256r1>();
+    }
+
+    #[test]
+    fn test_dleq_proof_secp256k1() {
+        dleq_proof::<Secp256k1>();
+    }
+
+    #[test]
+    fn test_dleq_proof_secp256r1() {
+        dleq_proof::<Secp256r1>();
+    }
+
+    #[test]
+    fn test_adaptor_sign_secp256k1() {
+        adaptor_sign_and_adapt::<Secp256k1>();
+    }
+
+    #[test]
+    fn test_adaptor_sign_secp256r1() {
+        adaptor_sign_and_adapt::<Secp256r1>();
+    }
+
+    #[test]
+    fn test_adaptor_nonce_mismatch_secp256k1() {
+        adaptor_nonce_mismatch::<Secp256k1>();
+    }
+
+    #[test]
+    fn test_adaptor_nonce_mismatch_secp256r1() {
+        adaptor_nonce_mismatch::<Secp256r1>();
+    }
+
+    #[test]
+    fn test_invalid_dlog_proof_secp256k1() {
+        invalid_dlog_proof::<Secp256k1>();
+    }
+
+    #[test]
+    fn test_invalid_dlog_proof_secp256r1() {
+        invalid_dlog_proof::<Secp256r1>();
+    }
+
+    #[test]
+    fn test_invalid_commitment_opening_secp256k1() {
+        invalid_commitment_opening::<Secp256k1>();
+    }
+
+    #[test]
+    fn test_invalid_commitment_opening_secp256r1() {
+        invalid_commitment_opening::<Secp256r1>();
+    }
+
+    #[test]
+    fn test_sign_secp256k1() {
+        sign_and_verify::<Secp256k1>();
+    }
 
-    let party1_pub_share = Point::generator() * party1_key.x1;
-    let pubkey = party_one::compute_add_pubkey(&party1_pub_share, &pub_share);
-    party_one::verify(&rst3.signature, &pubkey, &msg).expect("Invalid signature")
+    #[test]
+    fn test_sign_secp256r1() {
+        sign_and_verify::<Secp256r1>();
+    }
 }
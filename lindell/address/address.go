@@ -0,0 +1,240 @@
+// Package address derives the public key encodings and chain addresses controlled by a two-party secp256k1 key:
+// SEC1 public keys, Ethereum addresses, Bitcoin P2PKH, P2WPKH and P2TR addresses and Cosmos bech32 addresses,
+// for the key itself or for a BIP-32 non-hardened child of it.
+package address
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go-rust/lindell/bip340"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/ckd"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+)
+
+// Network holds the Bitcoin address prefixes of a chain
+type Network struct {
+	Name         string
+	P2PKHVersion byte
+	Bech32HRP    string
+}
+
+var (
+	BitcoinMainnet = Network{Name: "mainnet", P2PKHVersion: 0x00, Bech32HRP: "bc"}
+	BitcoinTestnet = Network{Name: "testnet", P2PKHVersion: 0x6f, Bech32HRP: "tb"}
+)
+
+// NetworkByName returns the Bitcoin network called name, mainnet or testnet
+func NetworkByName(name string) (Network, error) {
+	for _, net := range []Network{BitcoinMainnet, BitcoinTestnet} {
+		if net.Name == name {
+			return net, nil
+		}
+	}
+	return Network{}, fmt.Errorf("unknown network %q", name)
+}
+
+// CosmosHRP is the bech32 prefix of Cosmos Hub accounts
+const CosmosHRP = "cosmos"
+
+// Addresses is every encoding of one public key
+type Addresses struct {
+	SEC1Compressed   string `json:"sec1_compressed"`
+	SEC1Uncompressed string `json:"sec1_uncompressed"`
+	Ethereum         string `json:"ethereum"`
+	P2PKH            string `json:"p2pkh"`
+	P2WPKH           string `json:"p2wpkh"`
+	P2TR             string `json:"p2tr"`
+	Cosmos           string `json:"cosmos"`
+}
+
+// PublicKey returns the joint public key of a key share, checking it is a secp256k1 point
+func PublicKey(key keygen.LocalPartySaveData) (*crypto.ECPoint, error) {
+	pub := key.ECDSAPub
+	if pub == nil {
+		return nil, errors.New("key share has no public key")
+	}
+	// shares loaded from JSON carry no curve
+	pub.SetCurve(tss.S256())
+	if !pub.ValidateBasic() || !pub.IsOnCurve() {
+		return nil, errors.New("public key is not a secp256k1 point")
+	}
+	return pub, nil
+}
+
+// ParsePath parses a BIP-32 path such as m/44/60/0/0. Only non-hardened indices are allowed since the child is derived
+// from the public key alone.
+func ParsePath(path string) ([]uint32, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "m")
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil, nil
+	}
+	parts := strings.Split(path, "/")
+	indices := make([]uint32, 0, len(parts))
+	for _, part := range parts {
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") {
+			return nil, fmt.Errorf("hardened index %q cannot be derived from a public key", part)
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || index >= ckd.HardenedKeyStart {
+			return nil, fmt.Errorf("invalid path index %q", part)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// DeriveChild derives the BIP-32 child of pub along path. The returned delta is the key derivation delta to sign for
+// the child with signing.NewLocalPartyWithKDD.
+func DeriveChild(pub *crypto.ECPoint, chainCode []byte, path []uint32) (*crypto.ECPoint, *big.Int, error) {
+	if len(chainCode) != 32 {
+		return nil, nil, fmt.Errorf("chain code must be 32 bytes, got %d", len(chainCode))
+	}
+	ec := tss.S256()
+	parent := &ckd.ExtendedKey{
+		PublicKey: *pub.ToECDSAPubKey(),
+		ChainCode: chainCode,
+		ParentFP:  []byte{0, 0, 0, 0},
+	}
+	delta, child, err := ckd.DeriveChildKeyFromHierarchy(path, parent, ec.Params().N, ec)
+	if err != nil {
+		return nil, nil, err
+	}
+	childPub, err := crypto.NewECPoint(ec, child.X, child.Y)
+	if err != nil {
+		return nil, nil, err
+	}
+	return childPub, delta, nil
+}
+
+// DerivePath derives the child of pub along a path as ParsePath reads it, under the hex encoded chainCodeHex. An
+// empty path returns pub itself.
+func DerivePath(pub *crypto.ECPoint, path, chainCodeHex string) (*crypto.ECPoint, error) {
+	if path == "" {
+		return pub, nil
+	}
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	chainCode, err := hex.DecodeString(chainCodeHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain code: %v", err)
+	}
+	child, _, err := DeriveChild(pub, chainCode, indices)
+	return child, err
+}
+
+// Derive computes every encoding of pub; Bitcoin addresses use net and the Cosmos address uses cosmosHRP
+func Derive(pub *crypto.ECPoint, net Network, cosmosHRP string) (*Addresses, error) {
+	p2wpkh, err := P2WPKH(pub, net)
+	if err != nil {
+		return nil, err
+	}
+	p2tr, err := P2TR(pub, net)
+	if err != nil {
+		return nil, err
+	}
+	cosmos, err := Cosmos(pub, cosmosHRP)
+	if err != nil {
+		return nil, err
+	}
+	return &Addresses{
+		SEC1Compressed:   hex.EncodeToString(SEC1Compressed(pub)),
+		SEC1Uncompressed: hex.EncodeToString(SEC1Uncompressed(pub)),
+		Ethereum:         Ethereum(pub),
+		P2PKH:            P2PKH(pub, net),
+		P2WPKH:           p2wpkh,
+		P2TR:             p2tr,
+		Cosmos:           cosmos,
+	}, nil
+}
+
+// WriteText writes addrs to w, one labelled line per encoding
+func (addrs *Addresses) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "sec1-compressed:   %s\nsec1-uncompressed: %s\nethereum:          %s\np2pkh:             %s\n"+
+		"p2wpkh:            %s\np2tr:              %s\ncosmos:            %s\n",
+		addrs.SEC1Compressed, addrs.SEC1Uncompressed, addrs.Ethereum, addrs.P2PKH, addrs.P2WPKH, addrs.P2TR, addrs.Cosmos)
+	return err
+}
+
+func SEC1Compressed(pub *crypto.ECPoint) []byte {
+	bz := make([]byte, 33)
+	bz[0] = 2 | byte(pub.Y().Bit(0))
+	pub.X().FillBytes(bz[1:])
+	return bz
+}
+
+func SEC1Uncompressed(pub *crypto.ECPoint) []byte {
+	bz := make([]byte, 65)
+	bz[0] = 4
+	pub.X().FillBytes(bz[1:33])
+	pub.Y().FillBytes(bz[33:])
+	return bz
+}
+
+// Ethereum returns the EIP-55 checksummed address, the last 20 bytes of Keccak-256(X || Y)
+func Ethereum(pub *crypto.ECPoint) string {
+	addr := hex.EncodeToString(keccak256(SEC1Uncompressed(pub)[1:])[12:])
+	checksum := keccak256([]byte(addr))
+	out := []byte(addr)
+	for i, c := range out {
+		if c >= 'a' && checksum[i/2]>>(4*uint(1-i%2))&0xf >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// P2PKH returns the base58check address of the compressed key's HASH160
+func P2PKH(pub *crypto.ECPoint, net Network) string {
+	return base58.CheckEncode(hash160(SEC1Compressed(pub)), net.P2PKHVersion)
+}
+
+// P2WPKH returns the segwit v0 address of the compressed key's HASH160
+func P2WPKH(pub *crypto.ECPoint, net Network) (string, error) {
+	return segwitAddress(net.Bech32HRP, 0, hash160(SEC1Compressed(pub)))
+}
+
+// P2TR returns the BIP-86 key-path-only Taproot address, i.e. the output key tweaked with an empty script tree. The
+// party from bip340/signing.NewLocalPartyWithTaprootTweak with a nil merkle root signs for it.
+func P2TR(pub *crypto.ECPoint, net Network) (string, error) {
+	Q, err := bip340.TaprootOutputKey(pub, nil)
+	if err != nil {
+		return "", err
+	}
+	return segwitAddress(net.Bech32HRP, 1, bip340.XOnly(Q))
+}
+
+// Cosmos returns the bech32 account address, HASH160 of the compressed key under hrp
+func Cosmos(pub *crypto.ECPoint, hrp string) (string, error) {
+	return bech32Encode(hrp, convertBits(hash160(SEC1Compressed(pub))), bech32Const)
+}
+
+// ----- //
+
+func hash160(bz []byte) []byte {
+	sha := sha256.Sum256(bz)
+	h := ripemd160.New()
+	h.Write(sha[:])
+	return h.Sum(nil)
+}
+
+func keccak256(bz []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(bz)
+	return h.Sum(nil)
+}
@@ -0,0 +1,148 @@
+package address
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"go-rust/lindell/bip340"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressesOfGenerator(t *testing.T) {
+	// the key with private key 1
+	G := crypto.ScalarBaseMult(tss.S256(), big.NewInt(1))
+
+	assert.Equal(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", hex.EncodeToString(SEC1Compressed(G)))
+	assert.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", Ethereum(G))
+	assert.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", P2PKH(G, BitcoinMainnet))
+
+	p2wpkh, err := P2WPKH(G, BitcoinMainnet)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", p2wpkh)
+
+	// the Cosmos address is the same HASH160 without a witness version
+	cosmos, err := Cosmos(G, CosmosHRP)
+	assert.NoError(t, err)
+	data, err := bech32.ConvertBits(hash160(SEC1Compressed(G)), 8, 5, true)
+	assert.NoError(t, err)
+	expected, err := bech32.Encode(CosmosHRP, data)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, cosmos)
+}
+
+func TestP2TR(t *testing.T) {
+	// BIP-86 test vector for m/86'/0'/0'/0/0
+	x, _ := new(big.Int).SetString("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", 16)
+	P, err := bip340.LiftX(x)
+	assert.NoError(t, err)
+	addr, err := P2TR(P, BitcoinMainnet)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", addr)
+
+	// the internal key's parity does not matter
+	addr2, err := P2TR(bip340.Negate(P), BitcoinMainnet)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, addr2)
+}
+
+func TestDeriveChild(t *testing.T) {
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+	masterPub, err := master.ECPubKey()
+	assert.NoError(t, err)
+	pub, err := crypto.NewECPoint(tss.S256(), masterPub.X, masterPub.Y)
+	assert.NoError(t, err)
+
+	path, err := ParsePath("m/44/60/0/7")
+	assert.NoError(t, err)
+	expected := master
+	for _, index := range path {
+		expected, err = expected.Child(index)
+		assert.NoError(t, err)
+	}
+	expectedPub, err := expected.ECPubKey()
+	assert.NoError(t, err)
+
+	// the chain code sits at bytes 13..45 of the serialised extended key
+	chainCode := base58.Decode(master.String())[13:45]
+	child, delta, err := DeriveChild(pub, chainCode, path)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, child.X().Cmp(expectedPub.X))
+	assert.Equal(t, 0, child.Y().Cmp(expectedPub.Y))
+
+	// the delta moves the private key to the child's
+	masterPriv, err := master.ECPrivKey()
+	assert.NoError(t, err)
+	childPriv := new(big.Int).Add(masterPriv.D, delta)
+	childPriv.Mod(childPriv, tss.S256().Params().N)
+	assert.True(t, crypto.ScalarBaseMult(tss.S256(), childPriv).Equals(child))
+
+	_, err = ParsePath("m/44'/0")
+	assert.Error(t, err, "hardened indices must be rejected")
+	_, _, err = DeriveChild(pub, chainCode[:31], path)
+	assert.Error(t, err)
+
+	// DerivePath is the same derivation from the flags of the commands
+	byPath, err := DerivePath(pub, "m/44/60/0/7", hex.EncodeToString(chainCode))
+	assert.NoError(t, err)
+	assert.True(t, byPath.Equals(child))
+	same, err := DerivePath(pub, "", "")
+	assert.NoError(t, err)
+	assert.Same(t, pub, same)
+	_, err = DerivePath(pub, "m/0", "not hex")
+	assert.Error(t, err)
+}
+
+func TestNetworkByName(t *testing.T) {
+	for _, net := range []Network{BitcoinMainnet, BitcoinTestnet} {
+		found, err := NetworkByName(net.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, net, found)
+	}
+	_, err := NetworkByName("regtest")
+	assert.Error(t, err)
+}
+
+func TestWriteText(t *testing.T) {
+	addrs, err := Derive(crypto.ScalarBaseMult(tss.S256(), big.NewInt(1)), BitcoinMainnet, CosmosHRP)
+	assert.NoError(t, err)
+	var out strings.Builder
+	assert.NoError(t, addrs.WriteText(&out))
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, 7)
+	assert.Equal(t, "ethereum:          0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", lines[2])
+	assert.Equal(t, "cosmos:            "+addrs.Cosmos, lines[6])
+}
+
+func TestKeyShareAddresses(t *testing.T) {
+	bz, err := os.ReadFile("../../test/_ecdsa_fixtures/keygen_data_0.json")
+	if err != nil || len(bz) == 0 {
+		t.Skipf("keygen fixture is not available, create it with go generate ./lindell/signing/: %v", err)
+	}
+	var key keygen.LocalPartySaveData
+	assert.NoError(t, json.Unmarshal(bz, &key))
+	pub, err := PublicKey(key)
+	assert.NoError(t, err)
+
+	addrs, err := Derive(pub, BitcoinTestnet, CosmosHRP)
+	assert.NoError(t, err)
+	assert.Equal(t, "tb1q", addrs.P2WPKH[:4])
+	assert.Equal(t, "tb1p", addrs.P2TR[:4])
+	assert.Equal(t, hex.EncodeToString(SEC1Compressed(pub)), addrs.SEC1Compressed)
+}
@@ -0,0 +1,91 @@
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+// bech32 (BIP-173) and bech32m (BIP-350) differ only in the constant the checksum is xored with
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Encode encodes 5-bit groups under hrp with the checksum constant of the chosen variant
+func bech32Encode(hrp string, data []byte, constant uint32) (string, error) {
+	if hrp == "" || strings.ToLower(hrp) != hrp {
+		return "", errors.New("human-readable part must be non-empty and lower case")
+	}
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ constant
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		if d >= 32 {
+			return "", errors.New("bech32 data must be 5-bit groups")
+		}
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String(), nil
+}
+
+// convertBits regroups 8-bit bytes into 5-bit groups, padding the last one
+func convertBits(data []byte) []byte {
+	out := make([]byte, 0, (len(data)*8+4)/5)
+	acc, bits := uint32(0), uint(0)
+	for _, b := range data {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out = append(out, byte(acc>>bits)&31)
+		}
+	}
+	if bits > 0 {
+		out = append(out, byte(acc<<(5-bits))&31)
+	}
+	return out
+}
+
+// segwitAddress encodes a witness program, with bech32 for version 0 and bech32m from version 1 on
+func segwitAddress(hrp string, version byte, program []byte) (string, error) {
+	constant := uint32(bech32Const)
+	if version > 0 {
+		constant = bech32mConst
+	}
+	return bech32Encode(hrp, append([]byte{version}, convertBits(program)...), constant)
+}
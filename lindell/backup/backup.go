@@ -0,0 +1,320 @@
+// Package backup makes verifiable encrypted backups of key shares. The owner of a share encrypts it to an escrow
+// Paillier key and proves, with the PDL-with-slack proof the signing protocol already uses, that the ciphertext holds
+// the discrete log of its public share. The counterparty checks the backup against its own view of the key without
+// being able to open it; whoever holds the escrow private key can later recover the share and rebuild the key data.
+//
+// A backup carries the public part of the owner's key data too, which the counterparty confirms against its own, so a
+// recovered share can be restored without anything but the backup and the escrow key.
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"go-rust/lindell/keystore"
+	"go-rust/lindell/zkp"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	// Version of the backup format written by New
+	Version = 1
+
+	// MinEscrowBits is the smallest escrow modulus accepted, the proof's slack needs N well above q^3
+	MinEscrowBits = 2048
+)
+
+// Public is the public part of a key share, the same for every party of the key
+type Public struct {
+	Curve    string            `json:"curve"`
+	ECDSAPub *crypto.ECPoint   `json:"ecdsa_pub"`
+	Ks       []*big.Int        `json:"ks"`
+	BigXj    []*crypto.ECPoint `json:"big_xj"`
+	// the parties' ring-Pedersen parameters
+	NTildej []*big.Int `json:"ntilde_j"`
+	H1j     []*big.Int `json:"h1_j"`
+	H2j     []*big.Int `json:"h2_j"`
+}
+
+// Backup is a key share encrypted to an escrow key
+type Backup struct {
+	Version int    `json:"version"`
+	KeyID   string `json:"key_id"`
+	// ShareID is the owner's share, VerifierID the counterparty's the proof is made for
+	ShareID    *big.Int `json:"share_id"`
+	VerifierID *big.Int `json:"verifier_id"`
+	Public     Public   `json:"public"`
+	EscrowN    *big.Int `json:"escrow_n"`
+	// Ciphertext is Enc_escrow(xi), Proof shows it holds the discrete log of BigXj of the owner
+	Ciphertext *big.Int `json:"ciphertext"`
+	Proof      [][]byte `json:"proof"`
+}
+
+// New encrypts key's share to escrow and proves it to the party with share ID verifier
+func New(key keygen.LocalPartySaveData, escrow *paillier.PublicKey, verifier *big.Int) (*Backup, error) {
+	if key.Xi == nil || key.ShareID == nil || key.ECDSAPub == nil {
+		return nil, errors.New("backup: incomplete key share")
+	}
+	if err := checkEscrow(escrow); err != nil {
+		return nil, err
+	}
+	pub, err := publicOf(key)
+	if err != nil {
+		return nil, err
+	}
+	i, j := indexOf(key.Ks, key.ShareID), indexOf(key.Ks, verifier)
+	if i < 0 || j < 0 || i == j {
+		return nil, errors.New("backup: the verifier is not another party of the key")
+	}
+	ec := key.ECDSAPub.Curve()
+	xi := new(big.Int).Mod(key.Xi, ec.Params().N)
+	if !crypto.ScalarBaseMult(ec, xi).Equals(key.BigXj[i]) {
+		return nil, errors.New("backup: the share does not match its public share")
+	}
+	c, r, err := escrow.EncryptAndReturnRandomness(xi)
+	if err != nil {
+		return nil, err
+	}
+	pf, err := zkp.NewPDLwSlackProof(ec, escrow, c, key.BigXj[i], key.NTildej[j], key.H1j[j], key.H2j[j], xi, r)
+	if err != nil {
+		return nil, err
+	}
+	bzs := pf.Bytes()
+	return &Backup{
+		Version:    Version,
+		KeyID:      keystore.KeyID(key.ECDSAPub),
+		ShareID:    new(big.Int).Set(key.ShareID),
+		VerifierID: new(big.Int).Set(verifier),
+		Public:     pub,
+		EscrowN:    new(big.Int).Set(escrow.N),
+		Ciphertext: c,
+		Proof:      bzs[:],
+	}, nil
+}
+
+// Verify is run by the counterparty with its own key share. It checks that b is made for this party and this key,
+// that it is encrypted to escrow, the escrow key both parties agreed on, that its public part matches this party's
+// view of the key and that the ciphertext holds the owner's share. Without the escrow key a backup could be encrypted
+// to any modulus, one the owner holds the key of included.
+func Verify(verifierKey keygen.LocalPartySaveData, b *Backup, escrow *paillier.PublicKey) error {
+	if b == nil || b.Version != Version {
+		return errors.New("backup: unsupported version")
+	}
+	if b.ShareID == nil || b.VerifierID == nil || b.EscrowN == nil || b.Ciphertext == nil {
+		return errors.New("backup: incomplete backup")
+	}
+	if verifierKey.ShareID == nil || verifierKey.ShareID.Cmp(b.VerifierID) != 0 {
+		return errors.New("backup: made for another party")
+	}
+	if escrow == nil || escrow.N == nil {
+		return errors.New("backup: the escrow key is required")
+	}
+	if escrow.N.Cmp(b.EscrowN) != 0 {
+		return errors.New("backup: encrypted to another escrow key")
+	}
+	pk := &paillier.PublicKey{N: b.EscrowN}
+	if err := checkEscrow(pk); err != nil {
+		return err
+	}
+	mine, err := publicOf(verifierKey)
+	if err != nil {
+		return err
+	}
+	if err = mine.equal(&b.Public); err != nil {
+		return err
+	}
+	if b.KeyID != keystore.KeyID(verifierKey.ECDSAPub) {
+		return errors.New("backup: the key id does not match the key")
+	}
+	i := indexOf(verifierKey.Ks, b.ShareID)
+	if i < 0 || verifierKey.ShareID.Cmp(b.ShareID) == 0 {
+		return errors.New("backup: the owner is not another party of the key")
+	}
+	if !inMultiplicativeGroup(pk.NSquare(), b.Ciphertext) {
+		return errors.New("backup: invalid ciphertext")
+	}
+	ec := verifierKey.ECDSAPub.Curve()
+	pf, err := zkp.PDLwSlackProofFromBytes(ec, b.Proof)
+	if err != nil {
+		return fmt.Errorf("backup: %v", err)
+	}
+	if !pf.Verify(ec, pk, b.Ciphertext, verifierKey.BigXj[i], verifierKey.NTildei, verifierKey.H1i, verifierKey.H2i) {
+		return errors.New("backup: the proof does not verify, the ciphertext may not hold the share")
+	}
+	return nil
+}
+
+// Recover decrypts the share in b with the escrow private key
+func Recover(escrowSK *paillier.PrivateKey, b *Backup) (*big.Int, error) {
+	if escrowSK == nil || b == nil || b.Ciphertext == nil || b.Public.ECDSAPub == nil {
+		return nil, errors.New("backup: incomplete backup")
+	}
+	if escrowSK.N.Cmp(b.EscrowN) != 0 {
+		return nil, errors.New("backup: encrypted to another escrow key")
+	}
+	if !b.Public.complete() {
+		return nil, errors.New("backup: incomplete backup")
+	}
+	i := indexOf(b.Public.Ks, b.ShareID)
+	if i < 0 {
+		return nil, errors.New("backup: the owner is not a party of the key")
+	}
+	m, err := escrowSK.Decrypt(b.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("backup: %v", err)
+	}
+	// the proof only bounds the plaintext to [-q^3, q^3], a negative one decrypts to N - |x|
+	if m.Cmp(new(big.Int).Rsh(escrowSK.N, 1)) > 0 {
+		m.Sub(m, escrowSK.N)
+	}
+	ec := b.Public.ECDSAPub.Curve()
+	xi := m.Mod(m, ec.Params().N)
+	if !crypto.ScalarBaseMult(ec, xi).Equals(b.Public.BigXj[i]) {
+		return nil, errors.New("backup: the decrypted share does not match the public share")
+	}
+	return xi, nil
+}
+
+// Restore rebuilds the owner's key share from b. The owner's Paillier key was lost with the share, paillierSK
+// replaces it; a client that verified the old modulus must forget it before signing with the restored share, see
+// signing.PaillierModulusCache.Forget and signing.DefaultPaillierModulusCache. The owner's ring-Pedersen parameters
+// are kept, as the counterparty still proves against them, but their trapdoor is gone, so the restored share can sign
+// but not take part in a resharing.
+func Restore(escrowSK *paillier.PrivateKey, b *Backup, paillierSK *paillier.PrivateKey) (keygen.LocalPartySaveData, error) {
+	if paillierSK == nil {
+		return keygen.LocalPartySaveData{}, errors.New("backup: a new paillier key is required")
+	}
+	xi, err := Recover(escrowSK, b)
+	if err != nil {
+		return keygen.LocalPartySaveData{}, err
+	}
+	pub := &b.Public
+	n := len(pub.Ks)
+	i := indexOf(pub.Ks, b.ShareID)
+
+	key := keygen.NewLocalPartySaveData(n)
+	key.PaillierSK = paillierSK
+	key.NTildei, key.H1i, key.H2i = pub.NTildej[i], pub.H1j[i], pub.H2j[i]
+	key.Xi, key.ShareID = xi, b.ShareID
+	key.ECDSAPub = pub.ECDSAPub
+	copy(key.Ks, pub.Ks)
+	copy(key.BigXj, pub.BigXj)
+	copy(key.NTildej, pub.NTildej)
+	copy(key.H1j, pub.H1j)
+	copy(key.H2j, pub.H2j)
+	key.PaillierPKs[i] = &paillierSK.PublicKey
+	return key, nil
+}
+
+// Save writes the backup as JSON
+func (b *Backup) Save(path string) error {
+	bz, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bz, '\n'), 0600)
+}
+
+// Load reads a backup written by Save
+func Load(path string) (*Backup, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := new(Backup)
+	if err = json.Unmarshal(bz, b); err != nil {
+		return nil, fmt.Errorf("backup: parsing %s: %v", path, err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("backup: unsupported version %d", b.Version)
+	}
+	return b, nil
+}
+
+// ----- //
+
+func publicOf(key keygen.LocalPartySaveData) (Public, error) {
+	if key.ECDSAPub == nil {
+		return Public{}, errors.New("backup: incomplete key share")
+	}
+	curve, ok := tss.GetCurveName(key.ECDSAPub.Curve())
+	if !ok {
+		return Public{}, errors.New("backup: unsupported curve")
+	}
+	pub := Public{
+		Curve:    string(curve),
+		ECDSAPub: key.ECDSAPub,
+		Ks:       key.Ks,
+		BigXj:    key.BigXj,
+		NTildej:  key.NTildej,
+		H1j:      key.H1j,
+		H2j:      key.H2j,
+	}
+	if !pub.complete() {
+		return Public{}, errors.New("backup: incomplete key share")
+	}
+	return pub, nil
+}
+
+func (p *Public) complete() bool {
+	n := len(p.Ks)
+	if n < 2 || len(p.BigXj) != n || len(p.NTildej) != n || len(p.H1j) != n || len(p.H2j) != n {
+		return false
+	}
+	for j := 0; j < n; j++ {
+		if p.Ks[j] == nil || p.BigXj[j] == nil || p.NTildej[j] == nil || p.H1j[j] == nil || p.H2j[j] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Public) equal(other *Public) error {
+	if p.Curve != other.Curve || other.ECDSAPub == nil || !p.ECDSAPub.Equals(other.ECDSAPub) {
+		return errors.New("backup: made for another key")
+	}
+	if !other.complete() || len(other.Ks) != len(p.Ks) {
+		return errors.New("backup: the parties differ from this party's view of the key")
+	}
+	for j := range p.Ks {
+		if !equalInt(p.Ks[j], other.Ks[j]) || !p.BigXj[j].Equals(other.BigXj[j]) ||
+			!equalInt(p.NTildej[j], other.NTildej[j]) || !equalInt(p.H1j[j], other.H1j[j]) || !equalInt(p.H2j[j], other.H2j[j]) {
+			return fmt.Errorf("backup: party %d differs from this party's view of the key", j)
+		}
+	}
+	return nil
+}
+
+func checkEscrow(escrow *paillier.PublicKey) error {
+	if escrow == nil || escrow.N == nil || escrow.N.BitLen() < MinEscrowBits || escrow.N.Bit(0) == 0 {
+		return fmt.Errorf("backup: the escrow key must be a paillier key of at least %d bits", MinEscrowBits)
+	}
+	return nil
+}
+
+func indexOf(ks []*big.Int, id *big.Int) int {
+	if id == nil {
+		return -1
+	}
+	for j, k := range ks {
+		if k != nil && k.Cmp(id) == 0 {
+			return j
+		}
+	}
+	return -1
+}
+
+func equalInt(a, b *big.Int) bool {
+	return a != nil && b != nil && a.Cmp(b) == 0
+}
+
+func inMultiplicativeGroup(n, v *big.Int) bool {
+	return v.Sign() > 0 && v.Cmp(n) < 0 && new(big.Int).GCD(nil, nil, v, n).Cmp(big.NewInt(1)) == 0
+}
@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadFixture(t *testing.T, i int) keygen.LocalPartySaveData {
+	bz, err := os.ReadFile(fmt.Sprintf("../../test/_ecdsa_fixtures/keygen_data_%d.json", i))
+	if err != nil || len(bz) == 0 {
+		t.Skipf("keygen fixture %d is not available, create it with go generate ./lindell/signing/: %v", i, err)
+	}
+	var key keygen.LocalPartySaveData
+	assert.NoError(t, json.Unmarshal(bz, &key))
+	return key
+}
+
+// fixtures returns the owner, the counterparty and an escrow key, which is the third party's Paillier key
+func fixtures(t *testing.T) (keygen.LocalPartySaveData, keygen.LocalPartySaveData, *paillier.PrivateKey) {
+	return loadFixture(t, 0), loadFixture(t, 1), loadFixture(t, 2).PaillierSK
+}
+
+func TestBackupAndRecover(t *testing.T) {
+	owner, verifier, escrow := fixtures(t)
+	b, err := New(owner, &escrow.PublicKey, verifier.ShareID)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "backup.json")
+	assert.NoError(t, b.Save(path))
+	b, err = Load(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, Verify(verifier, b, &escrow.PublicKey), "the counterparty must accept the backup")
+	assert.Error(t, Verify(verifier, b, nil), "the escrow key is required")
+
+	xi, err := Recover(escrow, b)
+	assert.NoError(t, err)
+	assert.Zero(t, xi.Cmp(owner.Xi), "the escrow must recover the share")
+}
+
+func TestRestore(t *testing.T) {
+	owner, verifier, escrow := fixtures(t)
+	b, err := New(owner, &escrow.PublicKey, verifier.ShareID)
+	assert.NoError(t, err)
+
+	// any fresh key will do, generating one is slow
+	paillierSK := verifier.PaillierSK
+	key, err := Restore(escrow, b, paillierSK)
+	assert.NoError(t, err)
+	assert.Zero(t, key.Xi.Cmp(owner.Xi))
+	assert.Zero(t, key.ShareID.Cmp(owner.ShareID))
+	assert.True(t, key.ECDSAPub.Equals(owner.ECDSAPub))
+	assert.Zero(t, key.NTildei.Cmp(owner.NTildei), "the counterparty proves against the old ring-Pedersen parameters")
+	assert.Equal(t, paillierSK, key.PaillierSK)
+	i, err := key.OriginalIndex()
+	assert.NoError(t, err)
+	assert.True(t, key.BigXj[i].Equals(owner.BigXj[i]))
+	for j := range owner.Ks {
+		assert.Zero(t, key.Ks[j].Cmp(owner.Ks[j]))
+		assert.Zero(t, key.NTildej[j].Cmp(owner.NTildej[j]))
+	}
+
+	_, err = Restore(escrow, b, nil)
+	assert.Error(t, err)
+}
+
+func TestVerifyRejects(t *testing.T) {
+	owner, verifier, escrow := fixtures(t)
+	third := loadFixture(t, 2)
+
+	newBackup := func() *Backup {
+		b, err := New(owner, &escrow.PublicKey, verifier.ShareID)
+		assert.NoError(t, err)
+		return b
+	}
+
+	t.Run("another share", func(t *testing.T) {
+		b := newBackup()
+		c, err := escrow.PublicKey.Encrypt(new(big.Int).Add(owner.Xi, big.NewInt(1)))
+		assert.NoError(t, err)
+		b.Ciphertext = c
+		assert.Error(t, Verify(verifier, b, &escrow.PublicKey))
+		_, err = Recover(escrow, b)
+		assert.Error(t, err, "a share that does not match the public share must not be recovered")
+	})
+	t.Run("another verifier", func(t *testing.T) {
+		b := newBackup()
+		assert.Error(t, Verify(third, b, &escrow.PublicKey), "made for another party")
+		b.VerifierID = third.ShareID
+		assert.Error(t, Verify(third, b, &escrow.PublicKey), "the proof is made against the first verifier's parameters")
+	})
+	t.Run("another escrow key", func(t *testing.T) {
+		assert.Error(t, Verify(verifier, newBackup(), &verifier.PaillierSK.PublicKey))
+		_, err := Recover(verifier.PaillierSK, newBackup())
+		assert.Error(t, err)
+	})
+	t.Run("altered public data", func(t *testing.T) {
+		b := newBackup()
+		b.Public.BigXj = append([]*crypto.ECPoint(nil), b.Public.BigXj...)
+		b.Public.BigXj[2] = b.Public.BigXj[0]
+		assert.Error(t, Verify(verifier, b, &escrow.PublicKey))
+
+		b = newBackup()
+		b.Public.NTildej = append([]*big.Int(nil), b.Public.NTildej...)
+		b.Public.NTildej[0] = verifier.NTildei
+		assert.Error(t, Verify(verifier, b, &escrow.PublicKey), "a restored share must prove against the owner's real parameters")
+	})
+	t.Run("the verifier's own share", func(t *testing.T) {
+		b := newBackup()
+		b.ShareID = verifier.ShareID
+		assert.Error(t, Verify(verifier, b, &escrow.PublicKey))
+	})
+	t.Run("truncated proof", func(t *testing.T) {
+		b := newBackup()
+		b.Proof = b.Proof[:3]
+		assert.Error(t, Verify(verifier, b, &escrow.PublicKey))
+	})
+	t.Run("small escrow key", func(t *testing.T) {
+		_, err := New(owner, &paillier.PublicKey{N: big.NewInt(35)}, verifier.ShareID)
+		assert.Error(t, err)
+	})
+	t.Run("unknown verifier", func(t *testing.T) {
+		_, err := New(owner, &escrow.PublicKey, big.NewInt(7))
+		assert.Error(t, err)
+		_, err = New(owner, &escrow.PublicKey, owner.ShareID)
+		assert.Error(t, err)
+	})
+}
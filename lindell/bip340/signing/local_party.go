@@ -0,0 +1,147 @@
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go-rust/lindell/bip340"
+	"go-rust/lindell/internal/schnorrsign"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	tsssigning "github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	TaskName = "lindell-bip340-signing"
+)
+
+// scheme is BIP-340 on the shared two-party Schnorr rounds, signing with the x-only key q
+type scheme struct {
+	key keygen.LocalPartySaveData
+
+	// Taproot tweak of the output key; tweaked is false for plain BIP-340 signing with the internal key
+	tweaked    bool
+	merkleRoot []byte
+
+	// Prepare(): the x-only key with even y
+	q *crypto.ECPoint
+}
+
+var _ schnorrsign.Scheme = (*scheme)(nil)
+
+// NewLocalParty returns a party for two-party BIP-340 signing of msg with the x-only form of the ECDSA keygen key.
+// The final signature verifies with bip340.Verify against bip340.XOnly(key.ECDSAPub).
+func NewLocalParty(
+	msg []byte,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) tss.Party {
+	s := &scheme{key: keygen.BuildLocalSaveDataSubset(key, params.Parties().IDs())}
+	return schnorrsign.NewLocalParty(msg, params, s, TaskName, out, end)
+}
+
+// NewLocalPartyWithTaprootTweak returns a party that signs for the Taproot output key committing to merkleRoot,
+// i.e. bip340.TaprootOutputKey(key.ECDSAPub, merkleRoot). An empty merkleRoot signs for a key-path-only output.
+func NewLocalPartyWithTaprootTweak(
+	msg []byte,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	merkleRoot []byte,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) tss.Party {
+	s := &scheme{
+		key:        keygen.BuildLocalSaveDataSubset(key, params.Parties().IDs()),
+		tweaked:    true,
+		merkleRoot: merkleRoot,
+	}
+	return schnorrsign.NewLocalParty(msg, params, s, TaskName, out, end)
+}
+
+// Prepare turns the Shamir shares into additive shares d_i of the secret key for the x-only signing key q.
+// The key is negated when its y is odd, and for Taproot the first party adds the tweak before the output key's
+// y parity is fixed the same way.
+func (s *scheme) Prepare(params *tss.Parameters) (*big.Int, []*crypto.ECPoint, error) {
+	if err := bip340.ValidateParameters(params); err != nil {
+		return nil, nil, err
+	}
+	ec := params.EC()
+	modN := common.ModInt(ec.Params().N)
+	i := params.PartyID().Index
+	ks := s.key.Ks
+
+	if params.Threshold()+1 > len(ks) {
+		return nil, nil, fmt.Errorf("t+1=%d is not satisfied by the key count of %d", params.Threshold()+1, len(ks))
+	}
+	di, bigDj := tsssigning.PrepareForSigning(ec, i, len(ks), s.key.Xi, ks, s.key.BigXj)
+
+	negate := func() {
+		di = modN.Sub(big.NewInt(0), di)
+		for j, Dj := range bigDj {
+			bigDj[j] = bip340.Negate(Dj)
+		}
+	}
+
+	q := s.key.ECDSAPub
+	if !bip340.HasEvenY(q) {
+		negate()
+		q = bip340.Negate(q)
+	}
+	if s.tweaked {
+		t, err := bip340.TaprootTweak(s.key.ECDSAPub, s.merkleRoot)
+		if err != nil {
+			return nil, nil, err
+		}
+		tG := crypto.ScalarBaseMult(ec, t)
+		if i == 0 {
+			di = modN.Add(di, t)
+		}
+		if bigDj[0], err = bigDj[0].Add(tG); err != nil {
+			return nil, nil, err
+		}
+		if q, err = q.Add(tG); err != nil {
+			return nil, nil, err
+		}
+		if !bip340.HasEvenY(q) {
+			negate()
+			q = bip340.Negate(q)
+		}
+	}
+
+	sum, err := bigDj[0].Add(bigDj[1])
+	if err != nil || !sum.Equals(q) {
+		return nil, nil, errors.New("key shares do not add up to the signing key")
+	}
+	s.q = q
+	return di, bigDj, nil
+}
+
+// CheckNonce accepts any point on the curve, secp256k1 has a cofactor of 1
+func (s *scheme) CheckNonce(*crypto.ECPoint) error {
+	return nil
+}
+
+// NegateNonce reports whether R has an odd y; the signature commits to x(R) only, so both parties then negate their
+// nonces
+func (s *scheme) NegateNonce(R *crypto.ECPoint) bool {
+	return !bip340.HasEvenY(R)
+}
+
+func (s *scheme) Challenge(R *crypto.ECPoint, m []byte) *big.Int {
+	return bip340.Challenge(R, s.q, m)
+}
+
+// Encode returns R as x(R) and S as the 32-byte big-endian s
+func (s *scheme) Encode(R *crypto.ECPoint, sig *big.Int) ([]byte, []byte) {
+	return bip340.XOnly(R), sig.FillBytes(make([]byte, 32))
+}
+
+func (s *scheme) Verify(m, sig []byte) bool {
+	return bip340.Verify(bip340.XOnly(s.q), m, sig)
+}
@@ -0,0 +1,142 @@
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	"go-rust/lindell/bip340"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestE2EConcurrent(t *testing.T) {
+	msg := bip340.TaggedHash("TapSighash", []byte("spend"))
+	for _, oddY := range []bool{false, true} {
+		pIDs := tss.GenerateTestPartyIDs(2)
+		keys := dealKeys(t, pIDs, oddY)
+
+		sigs := runSigning(t, pIDs, func(i int, params *tss.Parameters, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Party {
+			return NewLocalParty(msg, params, keys[i], out, end)
+		})
+		for _, sig := range sigs {
+			assert.Len(t, sig, bip340.SignatureSize)
+			assert.True(t, bip340.Verify(bip340.XOnly(keys[0].ECDSAPub), msg, sig), "bip340 verify must pass (oddY=%v)", oddY)
+		}
+	}
+}
+
+func TestE2ETaprootTweak(t *testing.T) {
+	msg := bip340.TaggedHash("TapSighash", []byte("spend"))
+	merkleRoot := bip340.TaggedHash("TapBranch", []byte("scripts"))
+	for _, oddY := range []bool{false, true} {
+		for _, root := range [][]byte{nil, merkleRoot} {
+			pIDs := tss.GenerateTestPartyIDs(2)
+			keys := dealKeys(t, pIDs, oddY)
+			Q, err := bip340.TaprootOutputKey(keys[0].ECDSAPub, root)
+			assert.NoError(t, err)
+
+			sigs := runSigning(t, pIDs, func(i int, params *tss.Parameters, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Party {
+				return NewLocalPartyWithTaprootTweak(msg, params, keys[i], root, out, end)
+			})
+			for _, sig := range sigs {
+				assert.True(t, bip340.Verify(bip340.XOnly(Q), msg, sig), "output key must verify (oddY=%v)", oddY)
+				assert.False(t, bip340.Verify(bip340.XOnly(keys[0].ECDSAPub), msg, sig), "internal key must not verify")
+			}
+		}
+	}
+}
+
+func TestRejectsWrongCurve(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := dealKeys(t, pIDs, false)
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	P := NewLocalParty([]byte("m"), params, keys[0], make(chan tss.Message, 1), make(chan *common.SignatureData, 1))
+	assert.Error(t, P.Start())
+}
+
+// dealKeys deals a fresh secp256k1 key to the parties the way keygen would, with the requested parity of y
+func dealKeys(t *testing.T, pIDs tss.SortedPartyIDs, oddY bool) []keygen.LocalPartySaveData {
+	ec := tss.S256()
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	if pub := crypto.ScalarBaseMult(ec, x); bip340.HasEvenY(pub) == oddY {
+		x.Sub(ec.Params().N, x)
+	}
+	ks := pIDs.Keys()
+	_, shares, err := vss.Create(ec, 1, x, ks)
+	assert.NoError(t, err)
+
+	bigXj := make([]*crypto.ECPoint, len(shares))
+	for j, share := range shares {
+		bigXj[j] = crypto.ScalarBaseMult(ec, share.Share)
+	}
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for i := range pIDs {
+		key := keygen.NewLocalPartySaveData(len(pIDs))
+		key.Xi = new(big.Int).Set(shares[i].Share)
+		key.ShareID = ks[i]
+		key.Ks = ks
+		key.BigXj = bigXj
+		key.ECDSAPub = crypto.ScalarBaseMult(ec, x)
+		keys[i] = key
+	}
+	return keys
+}
+
+// runSigning returns the signature output by each party
+func runSigning(t *testing.T, pIDs tss.SortedPartyIDs,
+	newParty func(i int, params *tss.Parameters, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Party,
+) [][]byte {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]tss.Party, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *common.SignatureData, len(pIDs))
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), 1)
+		P := newParty(i, params, outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	sigs := make([][]byte, 0, len(pIDs))
+	for len(sigs) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			t.Fatal(err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				go sharedPartyUpdater(P, msg, errCh)
+			}
+		case data := <-endCh:
+			sigs = append(sigs, data.GetSignature())
+		}
+	}
+	return sigs
+}
+
+func sharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	bz, _, err := msg.WireBytes()
+	if err != nil {
+		errCh <- party.WrapError(err)
+		return
+	}
+	pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+	if err != nil {
+		errCh <- party.WrapError(err)
+		return
+	}
+	if _, err := party.Update(pMsg); err != nil {
+		errCh <- err
+	}
+}
@@ -0,0 +1,73 @@
+// Package eddsa holds what the two-party Ed25519 keygen and signing protocols share: the party configuration checks
+// and the RFC 8032 encodings of points, scalars and the signing challenge.
+package eddsa
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+// both protocols are strictly two-party with additive shares
+const PartyCount = 2
+
+// ValidateParameters checks that params describe a two-party session over Edwards25519
+func ValidateParameters(params *tss.Parameters) error {
+	if name, ok := tss.GetCurveName(params.EC()); !ok || name != tss.Ed25519 {
+		return errors.New("two-party EdDSA requires the Edwards25519 curve")
+	}
+	if params.PartyCount() != PartyCount || len(params.Parties().IDs()) != PartyCount {
+		return fmt.Errorf("exactly %d parties are required, got partyCount=%d and %d peers",
+			PartyCount, params.PartyCount(), len(params.Parties().IDs()))
+	}
+	if params.Threshold()+1 != PartyCount {
+		return fmt.Errorf("threshold must be %d for two parties, got %d", PartyCount-1, params.Threshold())
+	}
+	return nil
+}
+
+// InPrimeOrderSubgroup rejects points with a small-order component, which would let a peer bias the joint key or
+// nonce by a torsion point that the Schnorr proofs do not catch reliably
+func InPrimeOrderSubgroup(P *crypto.ECPoint) bool {
+	if P == nil || !P.IsOnCurve() {
+		return false
+	}
+	lP := P.ScalarMult(P.Curve().Params().N)
+	return lP.X().Sign() == 0 && lP.Y().Cmp(big.NewInt(1)) == 0
+}
+
+// EncodePoint returns the 32-byte compressed encoding of P
+func EncodePoint(P *crypto.ECPoint) []byte {
+	return edwards.NewPublicKey(P.X(), P.Y()).Serialize()
+}
+
+// EncodeScalar returns the 32-byte little-endian encoding of s, which must be reduced mod L
+func EncodeScalar(s *big.Int) []byte {
+	bz := make([]byte, 32)
+	s.FillBytes(bz)
+	reverse(bz)
+	return bz
+}
+
+// Challenge computes k = SHA-512(R || A || M) mod L
+func Challenge(R, A *crypto.ECPoint, msg []byte) *big.Int {
+	h := sha512.New()
+	h.Write(EncodePoint(R))
+	h.Write(EncodePoint(A))
+	h.Write(msg)
+	digest := h.Sum(nil)
+	reverse(digest)
+	k := new(big.Int).SetBytes(digest)
+	return k.Mod(k, R.Curve().Params().N)
+}
+
+func reverse(bz []byte) {
+	for i, j := 0, len(bz)-1; i < j; i, j = i+1, j-1 {
+		bz[i], bz[j] = bz[j], bz[i]
+	}
+}
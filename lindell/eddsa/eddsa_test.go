@@ -0,0 +1,43 @@
+package eddsa
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePointMatchesStdlib(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	// RFC 8032 5.1.5: the secret scalar is the clamped lower half of SHA-512(seed)
+	h := sha512.Sum512(priv.Seed())
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	a := h[:32]
+	reverse(a)
+
+	A := crypto.ScalarBaseMult(tss.Edwards(), new(big.Int).SetBytes(a))
+	assert.Equal(t, []byte(pub), EncodePoint(A))
+}
+
+func TestInPrimeOrderSubgroup(t *testing.T) {
+	ec := tss.Edwards()
+	assert.True(t, InPrimeOrderSubgroup(crypto.ScalarBaseMult(ec, big.NewInt(42))))
+
+	// (0, -1) has order 2
+	minusOne := new(big.Int).Sub(ec.Params().P, big.NewInt(1))
+	torsion, err := crypto.NewECPoint(ec, big.NewInt(0), minusOne)
+	assert.NoError(t, err)
+	assert.False(t, InPrimeOrderSubgroup(torsion))
+
+	mixed, err := crypto.ScalarBaseMult(ec, big.NewInt(42)).Add(torsion)
+	assert.NoError(t, err)
+	assert.False(t, InPrimeOrderSubgroup(mixed))
+}
@@ -0,0 +1,242 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.20.3
+// source: lindell-eddsa-keygen.proto
+
+package keygen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Represents a BROADCAST message sent during Round 1 of the two-party EdDSA keygen protocol.
+type KGRound1Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hash commitment to the sender's public share
+	Commitment []byte `protobuf:"bytes,1,opt,name=commitment,proto3" json:"commitment,omitempty"`
+}
+
+func (x *KGRound1Message) Reset() {
+	*x = KGRound1Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_eddsa_keygen_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KGRound1Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KGRound1Message) ProtoMessage() {}
+
+func (x *KGRound1Message) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_eddsa_keygen_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KGRound1Message.ProtoReflect.Descriptor instead.
+func (*KGRound1Message) Descriptor() ([]byte, []int) {
+	return file_lindell_eddsa_keygen_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KGRound1Message) GetCommitment() []byte {
+	if x != nil {
+		return x.Commitment
+	}
+	return nil
+}
+
+// Represents a BROADCAST message sent during Round 2 of the two-party EdDSA keygen protocol.
+type KGRound2Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeCommitment [][]byte `protobuf:"bytes,1,rep,name=deCommitment,proto3" json:"deCommitment,omitempty"`
+	// Schnorr proof of knowledge of the secret share
+	ProofAlphaX []byte `protobuf:"bytes,2,opt,name=proofAlphaX,proto3" json:"proofAlphaX,omitempty"`
+	ProofAlphaY []byte `protobuf:"bytes,3,opt,name=proofAlphaY,proto3" json:"proofAlphaY,omitempty"`
+	ProofT      []byte `protobuf:"bytes,4,opt,name=proofT,proto3" json:"proofT,omitempty"`
+}
+
+func (x *KGRound2Message) Reset() {
+	*x = KGRound2Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_eddsa_keygen_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KGRound2Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KGRound2Message) ProtoMessage() {}
+
+func (x *KGRound2Message) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_eddsa_keygen_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KGRound2Message.ProtoReflect.Descriptor instead.
+func (*KGRound2Message) Descriptor() ([]byte, []int) {
+	return file_lindell_eddsa_keygen_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *KGRound2Message) GetDeCommitment() [][]byte {
+	if x != nil {
+		return x.DeCommitment
+	}
+	return nil
+}
+
+func (x *KGRound2Message) GetProofAlphaX() []byte {
+	if x != nil {
+		return x.ProofAlphaX
+	}
+	return nil
+}
+
+func (x *KGRound2Message) GetProofAlphaY() []byte {
+	if x != nil {
+		return x.ProofAlphaY
+	}
+	return nil
+}
+
+func (x *KGRound2Message) GetProofT() []byte {
+	if x != nil {
+		return x.ProofT
+	}
+	return nil
+}
+
+var File_lindell_eddsa_keygen_proto protoreflect.FileDescriptor
+
+var file_lindell_eddsa_keygen_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2d, 0x65, 0x64, 0x64, 0x73, 0x61, 0x2d,
+	0x6b, 0x65, 0x79, 0x67, 0x65, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x14, 0x6c, 0x69,
+	0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x65, 0x64, 0x64, 0x73, 0x61, 0x2e, 0x6b, 0x65, 0x79, 0x67,
+	0x65, 0x6e, 0x22, 0x31, 0x0a, 0x0f, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x31, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x91, 0x01, 0x0a, 0x0f, 0x4b, 0x47, 0x52, 0x6f, 0x75, 0x6e,
+	0x64, 0x32, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x43,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52,
+	0x0c, 0x64, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x20, 0x0a,
+	0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x58, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x58, 0x12,
+	0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x59, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x6c, 0x70, 0x68, 0x61,
+	0x59, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x54, 0x42, 0x16, 0x5a, 0x14, 0x6c, 0x69, 0x6e,
+	0x64, 0x65, 0x6c, 0x6c, 0x2f, 0x65, 0x64, 0x64, 0x73, 0x61, 0x2f, 0x6b, 0x65, 0x79, 0x67, 0x65,
+	0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_lindell_eddsa_keygen_proto_rawDescOnce sync.Once
+	file_lindell_eddsa_keygen_proto_rawDescData = file_lindell_eddsa_keygen_proto_rawDesc
+)
+
+func file_lindell_eddsa_keygen_proto_rawDescGZIP() []byte {
+	file_lindell_eddsa_keygen_proto_rawDescOnce.Do(func() {
+		file_lindell_eddsa_keygen_proto_rawDescData = protoimpl.X.CompressGZIP(file_lindell_eddsa_keygen_proto_rawDescData)
+	})
+	return file_lindell_eddsa_keygen_proto_rawDescData
+}
+
+var file_lindell_eddsa_keygen_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_lindell_eddsa_keygen_proto_goTypes = []interface{}{
+	(*KGRound1Message)(nil), // 0: lindell.eddsa.keygen.KGRound1Message
+	(*KGRound2Message)(nil), // 1: lindell.eddsa.keygen.KGRound2Message
+}
+var file_lindell_eddsa_keygen_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_lindell_eddsa_keygen_proto_init() }
+func file_lindell_eddsa_keygen_proto_init() {
+	if File_lindell_eddsa_keygen_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_lindell_eddsa_keygen_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KGRound1Message); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_eddsa_keygen_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KGRound2Message); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_lindell_eddsa_keygen_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_lindell_eddsa_keygen_proto_goTypes,
+		DependencyIndexes: file_lindell_eddsa_keygen_proto_depIdxs,
+		MessageInfos:      file_lindell_eddsa_keygen_proto_msgTypes,
+	}.Build()
+	File_lindell_eddsa_keygen_proto = out.File
+	file_lindell_eddsa_keygen_proto_rawDesc = nil
+	file_lindell_eddsa_keygen_proto_goTypes = nil
+	file_lindell_eddsa_keygen_proto_depIdxs = nil
+}
@@ -0,0 +1,90 @@
+package keygen
+
+import (
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestE2EConcurrent(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), 1)
+		P := NewLocalParty(params, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]LocalPartySaveData, 0, len(pIDs))
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			for _, P := range parties {
+				go sharedPartyUpdater(P, msg, errCh)
+			}
+
+		case save := <-endCh:
+			saves = append(saves, save)
+			if len(saves) == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	// both parties agree on the joint key and it is the sum of their additive shares
+	assert.True(t, saves[0].EDDSAPub.Equals(saves[1].EDDSAPub))
+	sum, err := crypto.ScalarBaseMult(tss.Edwards(), saves[0].Xi).Add(crypto.ScalarBaseMult(tss.Edwards(), saves[1].Xi))
+	assert.NoError(t, err)
+	assert.True(t, sum.Equals(saves[0].EDDSAPub))
+	for _, save := range saves {
+		for j := range pIDs {
+			assert.True(t, save.BigXj[j].Equals(saves[j].BigXj[j]))
+		}
+	}
+}
+
+func TestRejectsNonTwoPartyConfiguration(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	P := NewLocalParty(params, make(chan tss.Message, 1), make(chan LocalPartySaveData, 1))
+	assert.Error(t, P.Start())
+
+	pIDs = tss.GenerateTestPartyIDs(2)
+	params = tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	P = NewLocalParty(params, make(chan tss.Message, 1), make(chan LocalPartySaveData, 1))
+	assert.Error(t, P.Start(), "only Edwards25519 is supported")
+}
+
+func sharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	bz, _, err := msg.WireBytes()
+	if err != nil {
+		errCh <- party.WrapError(err)
+		return
+	}
+	pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+	if err != nil {
+		errCh <- party.WrapError(err)
+		return
+	}
+	if _, err := party.Update(pMsg); err != nil {
+		errCh <- err
+	}
+}
@@ -0,0 +1,69 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	cmt "github.com/bnb-chain/tss-lib/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func newRound1(params *tss.Parameters, save *LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- LocalPartySaveData) tss.Round {
+	return &round1{
+		&base{params, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	round.ok[i] = true
+
+	ec := round.Params().EC()
+	round.temp.xi = common.GetRandomPositiveInt(ec.Params().N)
+	round.temp.bigXi = crypto.ScalarBaseMult(ec, round.temp.xi)
+
+	// commit to our public share first so the peer cannot choose theirs as a function of it
+	cmtDeCmt := cmt.NewHashCommitment(round.temp.bigXi.X(), round.temp.bigXi.Y())
+	round.temp.deCommit = cmtDeCmt.D
+
+	ids := round.Parties().IDs().Keys()
+	round.save.Ks = ids
+	round.save.ShareID = ids[i]
+
+	r1msg := NewKGRound1Message(Pi, cmtDeCmt.C)
+	round.out <- r1msg
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound1Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
@@ -0,0 +1,63 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/bnb-chain/tss-lib/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func (round *round2) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 2
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	round.ok[i] = true
+
+	// keep the peers' commitments before they reveal
+	for j, msg := range round.temp.kgRound1Messages {
+		if j == i {
+			continue
+		}
+		round.temp.kgcs[j] = msg.Content().(*KGRound1Message).UnmarshalCommitment()
+	}
+
+	proof, err := schnorr.NewZKProof(round.temp.xi, round.temp.bigXi)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+
+	r2msg := NewKGRound2Message(Pi, round.temp.deCommit, proof)
+	round.out <- r2msg
+	return nil
+}
+
+func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*KGRound2Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round2) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.kgRound2Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round2) NextRound() tss.Round {
+	round.started = false
+	return &round3{round}
+}
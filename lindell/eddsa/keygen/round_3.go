@@ -0,0 +1,70 @@
+package keygen
+
+import (
+	"errors"
+
+	"go-rust/lindell/eddsa"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	cmt "github.com/bnb-chain/tss-lib/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	i := round.PartyID().Index
+	ec := round.Params().EC()
+
+	round.save.Xi = round.temp.xi
+	round.save.BigXj[i] = round.temp.bigXi
+	pub := round.temp.bigXi
+	for j, Pj := range Ps {
+		round.ok[j] = true
+		if j == i {
+			continue
+		}
+		r2msg := round.temp.kgRound2Messages[j].Content().(*KGRound2Message)
+		cmtDeCmt := cmt.HashCommitDecommit{C: round.temp.kgcs[j], D: r2msg.UnmarshalDeCommitment()}
+		ok, flatXj := cmtDeCmt.DeCommit()
+		if !ok || len(flatXj) != 2 {
+			return round.WrapError(errors.New("de-commitment of the public share failed"), Pj)
+		}
+		Xj, err := crypto.NewECPoint(ec, flatXj[0], flatXj[1])
+		if err != nil || !eddsa.InPrimeOrderSubgroup(Xj) {
+			return round.WrapError(errors.New("public share is not a point of the prime-order subgroup"), Pj)
+		}
+		proof, err := r2msg.UnmarshalZKProof(ec)
+		if err != nil || !proof.Verify(Xj) {
+			return round.WrapError(errors.New("failed to prove knowledge of the secret share"), Pj)
+		}
+		round.save.BigXj[j] = Xj
+		if pub, err = pub.Add(Xj); err != nil {
+			return round.WrapError(err, Pj)
+		}
+	}
+	round.save.EDDSAPub = pub
+
+	round.end <- *round.save
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	return nil // finished!
+}
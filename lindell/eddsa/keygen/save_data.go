@@ -0,0 +1,30 @@
+package keygen
+
+import (
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+// LocalPartySaveData is the output of two-party EdDSA keygen. The shares are additive: the joint secret key is the
+// sum of both parties' Xi, and EDDSAPub the sum of their BigXj.
+type LocalPartySaveData struct {
+	Xi      *big.Int
+	ShareID *big.Int
+
+	// original indexes (ki in signing preparation phase), sorted like the keygen PartyIDs
+	Ks []*big.Int
+
+	// public shares of each party, indexed like Ks
+	BigXj []*crypto.ECPoint
+
+	// the joint public key
+	EDDSAPub *crypto.ECPoint
+}
+
+func NewLocalPartySaveData(partyCount int) LocalPartySaveData {
+	return LocalPartySaveData{
+		Ks:    make([]*big.Int, partyCount),
+		BigXj: make([]*crypto.ECPoint, partyCount),
+	}
+}
@@ -0,0 +1,85 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go-rust/lindell/eddsa"
+	"go-rust/lindell/eddsa/keygen"
+	"go-rust/lindell/internal/schnorrsign"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	TaskName = "lindell-eddsa-signing"
+)
+
+// scheme is Ed25519 on the shared two-party Schnorr rounds: the key shares from keygen are already additive and the
+// challenge is k = H(R || A || M) as in RFC 8032
+type scheme struct {
+	key keygen.LocalPartySaveData
+}
+
+var _ schnorrsign.Scheme = (*scheme)(nil)
+
+// NewLocalParty returns a party for two-party Ed25519 signing of msg with a key produced by lindell/eddsa/keygen.
+// The final signature verifies with crypto/ed25519 against the encoded EDDSAPub.
+func NewLocalParty(
+	msg []byte,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) tss.Party {
+	return schnorrsign.NewLocalParty(msg, params, &scheme{key: key}, TaskName, out, end)
+}
+
+// Prepare checks that the key was generated by exactly the parties in this signing session
+func (s *scheme) Prepare(params *tss.Parameters) (*big.Int, []*crypto.ECPoint, error) {
+	if err := eddsa.ValidateParameters(params); err != nil {
+		return nil, nil, err
+	}
+	ids := params.Parties().IDs()
+	if len(s.key.Ks) != len(ids) || len(s.key.BigXj) != len(ids) {
+		return nil, nil, fmt.Errorf("key was generated for %d parties, signing with %d", len(s.key.Ks), len(ids))
+	}
+	for j, id := range ids {
+		if s.key.Ks[j] == nil || s.key.Ks[j].Cmp(id.KeyInt()) != 0 {
+			return nil, nil, fmt.Errorf("party %s did not take part in generating this key", id)
+		}
+	}
+	if s.key.EDDSAPub == nil {
+		return nil, nil, errors.New("key share is incomplete")
+	}
+	return s.key.Xi, s.key.BigXj, nil
+}
+
+func (s *scheme) CheckNonce(Kj *crypto.ECPoint) error {
+	if !eddsa.InPrimeOrderSubgroup(Kj) {
+		return errors.New("nonce point is not a point of the prime-order subgroup")
+	}
+	return nil
+}
+
+// NegateNonce is always false, Ed25519 encodes the whole of R
+func (s *scheme) NegateNonce(*crypto.ECPoint) bool {
+	return false
+}
+
+func (s *scheme) Challenge(R *crypto.ECPoint, m []byte) *big.Int {
+	return eddsa.Challenge(R, s.key.EDDSAPub, m)
+}
+
+// Encode returns R and S in the RFC 8032 wire format, each the 32-byte encoding
+func (s *scheme) Encode(R *crypto.ECPoint, sig *big.Int) ([]byte, []byte) {
+	return eddsa.EncodePoint(R), eddsa.EncodeScalar(sig)
+}
+
+func (s *scheme) Verify(m, sig []byte) bool {
+	return ed25519.Verify(ed25519.PublicKey(eddsa.EncodePoint(s.key.EDDSAPub)), m, sig)
+}
@@ -0,0 +1,127 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"go-rust/lindell/eddsa"
+	"go-rust/lindell/eddsa/keygen"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestE2EConcurrent(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := runKeygen(t, pIDs)
+
+	// leading zero bytes must survive, Ed25519 signs the message itself
+	msg := []byte{0x00, 0x00, 'h', 'e', 'l', 'l', 'o'}
+	sigs := runSigning(t, pIDs, keys, msg)
+
+	pub := ed25519.PublicKey(eddsa.EncodePoint(keys[0].EDDSAPub))
+	for _, sig := range sigs {
+		assert.Len(t, sig, ed25519.SignatureSize)
+		assert.True(t, ed25519.Verify(pub, msg, sig), "ed25519 verify must pass")
+		assert.Equal(t, sigs[0], sig)
+	}
+}
+
+func TestRejectsForeignKey(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := runKeygen(t, pIDs)
+
+	others := tss.GenerateTestPartyIDs(2, 10)
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(others), others[0], len(others), 1)
+	P := NewLocalParty([]byte("hello"), params, keys[0], make(chan tss.Message, 1), make(chan *common.SignatureData, 1))
+	assert.Error(t, P.Start(), "a key must only be used by the parties that generated it")
+}
+
+func runKeygen(t *testing.T, pIDs tss.SortedPartyIDs) []keygen.LocalPartySaveData {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]tss.Party, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), 1)
+		P := keygen.NewLocalParty(params, outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			t.Fatal(err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				go sharedPartyUpdater(P, msg, errCh)
+			}
+		case save := <-endCh:
+			keys[pIDs.FindByKey(save.ShareID).Index] = save
+			ended++
+		}
+	}
+	return keys
+}
+
+// runSigning returns the signature output by each party
+func runSigning(t *testing.T, pIDs tss.SortedPartyIDs, keys []keygen.LocalPartySaveData, msg []byte) [][]byte {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]tss.Party, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *common.SignatureData, len(pIDs))
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), 1)
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	sigs := make([][]byte, 0, len(pIDs))
+	for len(sigs) < len(pIDs) {
+		select {
+		case err := <-errCh:
+			t.Fatal(err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				go sharedPartyUpdater(P, msg, errCh)
+			}
+		case data := <-endCh:
+			sigs = append(sigs, data.GetSignature())
+		}
+	}
+	return sigs
+}
+
+func sharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	bz, _, err := msg.WireBytes()
+	if err != nil {
+		errCh <- party.WrapError(err)
+		return
+	}
+	pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+	if err != nil {
+		errCh <- party.WrapError(err)
+		return
+	}
+	if _, err := party.Update(pMsg); err != nil {
+		errCh <- err
+	}
+}
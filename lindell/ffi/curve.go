@@ -0,0 +1,118 @@
+package ffi
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go-rust/lindell/zeroize"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// curve names as used by curv on the Rust side
+const (
+	CurveSecp256k1 = "secp256k1"
+	CurveSecp256r1 = "secp256r1"
+)
+
+var supportedCurves = []struct {
+	ec   elliptic.Curve
+	name string
+}{
+	{tss.S256(), CurveSecp256k1},
+	{elliptic.P256(), CurveSecp256r1},
+}
+
+// CurveName returns the name lindellcore knows ec by. The curve is identified by its domain parameters
+// because btcec's secp256k1 does not fill in elliptic.CurveParams.Name.
+func CurveName(ec elliptic.Curve) (string, error) {
+	if ec == nil {
+		return "", errors.New("nil curve")
+	}
+	for _, c := range supportedCurves {
+		if sameCurve(ec.Params(), c.ec.Params()) {
+			return c.name, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported curve %q", ec.Params().Name)
+}
+
+func sameCurve(a, b *elliptic.CurveParams) bool {
+	return a.P.Cmp(b.P) == 0 && a.N.Cmp(b.N) == 0 && a.B.Cmp(b.B) == 0 &&
+		a.Gx.Cmp(b.Gx) == 0 && a.Gy.Cmp(b.Gy) == 0
+}
+
+// NewPoint encodes P the way curv serialises points, SEC1 compressed
+func NewPoint(P *crypto.ECPoint) (Point, error) {
+	name, err := CurveName(P.Curve())
+	if err != nil {
+		return Point{}, err
+	}
+	size := (P.Curve().Params().BitSize + 7) / 8
+	bz := make([]byte, 1+size)
+	bz[0] = 2 | byte(P.Y().Bit(0))
+	P.X().FillBytes(bz[1:])
+	return Point{Curve: name, Point: Bytes2Uint(bz)}, nil
+}
+
+// ECPoint decodes a SEC1 compressed point produced by lindellcore onto ec
+func (p Point) ECPoint(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	name, err := CurveName(ec)
+	if err != nil {
+		return nil, err
+	}
+	if p.Curve != name {
+		return nil, fmt.Errorf("point is on %q, expected %q", p.Curve, name)
+	}
+	params := ec.Params()
+	bz, err := uintBytes(p.Point)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) != 1+(params.BitSize+7)/8 || (bz[0] != 2 && bz[0] != 3) {
+		return nil, errors.New("point is not SEC1 compressed")
+	}
+	x := new(big.Int).SetBytes(bz[1:])
+	if x.Cmp(params.P) >= 0 {
+		return nil, errors.New("x coordinate is not a field element")
+	}
+
+	// y^2 = x^3 + a*x + b, with a = 0 for secp256k1 and a = -3 for the NIST curves
+	y2 := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	if name == CurveSecp256r1 {
+		y2.Sub(y2, new(big.Int).Mul(x, big.NewInt(3)))
+	}
+	y2.Add(y2, params.B).Mod(y2, params.P)
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if y == nil {
+		return nil, errors.New("x coordinate is not on the curve")
+	}
+	if y.Bit(0) != uint(bz[0]&1) {
+		y.Sub(params.P, y)
+	}
+	return crypto.NewECPoint(ec, x, y)
+}
+
+// NewScalar encodes s the way curv serialises scalars, big-endian in as many bytes as ec's order takes. curv rejects
+// the shorter encoding of big.Int.Bytes, which a scalar with leading zero bytes would get.
+func NewScalar(ec elliptic.Curve, s *big.Int) (Scalar, error) {
+	name, err := CurveName(ec)
+	if err != nil {
+		return Scalar{}, err
+	}
+	if s.Sign() < 0 || s.Cmp(ec.Params().N) >= 0 {
+		return Scalar{}, errors.New("scalar is not below the curve order")
+	}
+	bz := make([]byte, (ec.Params().N.BitLen()+7)/8)
+	defer zeroize.Bytes(bz)
+	s.FillBytes(bz)
+	return Scalar{Curve: name, Scalar: Bytes2Uint(bz)}, nil
+}
+
+// BigInt returns the big-endian value of s
+func (s Scalar) BigInt() *big.Int {
+	return new(big.Int).SetBytes(Uint2Byte(s.Scalar))
+}
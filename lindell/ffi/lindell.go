@@ -4,62 +4,60 @@ package ffi
 #cgo CFLAGS: -I./lindellcore
 #cgo LDFLAGS: -L${SRCDIR} -llindellcore
 #include <stdlib.h>
+#include <string.h>
 #include "lindellcore.h"
 */
 import "C"
 import (
 	"encoding/json"
 	"unsafe"
-)
 
-func Round1() Round1Result {
-	rstCstr := C.lindell_round1()
-	rst := C.GoString(rstCstr)
+	"go-rust/lindell/zeroize"
+)
 
+func Round1(input Round1Input) (Round1Result, error) {
 	var round1Rst Round1Result
-	if err := json.Unmarshal([]byte(rst), &round1Rst); err != nil {
-		panic(err)
-	}
-
-	return round1Rst
+	err := call(func(in *C.char) *C.char { return C.lindell_round1(in) }, input, &round1Rst)
+	return round1Rst, err
 }
 
-func Round2(input Round2Input) Round2Result {
-	data, err := json.Marshal(input)
-	if err != nil {
-		panic(err)
-	}
-
-	inputCstr := C.CString(string(data))
-	defer C.free(unsafe.Pointer(inputCstr))
-
-	rstCstr := C.lindell_round2(inputCstr)
-	rst := C.GoString(rstCstr)
-
+func Round2(input Round2Input) (Round2Result, error) {
 	var round2Rst Round2Result
-	if err = json.Unmarshal([]byte(rst), &round2Rst); err != nil {
-		panic(err)
-	}
+	err := call(func(in *C.char) *C.char { return C.lindell_round2(in) }, input, &round2Rst)
+	return round2Rst, err
+}
 
-	return round2Rst
+func Round3(input Round3Input) (Round3Result, error) {
+	var round3Rst Round3Result
+	err := call(func(in *C.char) *C.char { return C.lindell_round3(in) }, input, &round3Rst)
+	return round3Rst, err
 }
 
-func Round3(input Round3Input) Round3Result {
+// call runs a round of lindellcore on the JSON of input and parses its output into result. The JSON documents carry
+// secret shares, so every copy of them this side owns is wiped once parsed and lindellcore wipes its output when it
+// is freed. encoding/json's own scratch buffers are out of reach.
+// A round that lindellcore rejects, e.g. over a peer's message that does not verify, returns its reason as an error.
+func call(round func(*C.char) *C.char, input, result interface{}) error {
 	data, err := json.Marshal(input)
 	if err != nil {
 		panic(err)
 	}
-
-	inputCstr := C.CString(string(data))
-	defer C.free(unsafe.Pointer(inputCstr))
-
-	rstCstr := C.lindell_round3(inputCstr)
-	rst := C.GoString(rstCstr)
-
-	var round3Rst Round3Result
-	if err = json.Unmarshal([]byte(rst), &round3Rst); err != nil {
-		panic(err)
-	}
-
-	return round3Rst
+	defer zeroize.Bytes(data)
+
+	// C.CString would go through a Go string, which cannot be wiped
+	inputCstr := C.malloc(C.size_t(len(data) + 1))
+	inputBytes := unsafe.Slice((*byte)(inputCstr), len(data)+1)
+	copy(inputBytes, data)
+	inputBytes[len(data)] = 0
+	defer func() {
+		zeroize.Bytes(inputBytes)
+		C.free(inputCstr)
+	}()
+
+	rstCstr := round((*C.char)(inputCstr))
+	defer C.lindell_free(rstCstr)
+	rst := C.GoBytes(unsafe.Pointer(rstCstr), C.int(C.strlen(rstCstr)))
+	defer zeroize.Bytes(rst)
+
+	return parseOutput(rst, result)
 }
@@ -3,8 +3,10 @@
 #include <stdint.h>
 #include <stdlib.h>
 
-const char *lindell_round1(void);
+const char *lindell_round1(const char *input);
 
 const char *lindell_round2(const char *input);
 
 const char *lindell_round3(const char *input);
+
+void lindell_free(char *output);
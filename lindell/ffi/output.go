@@ -0,0 +1,26 @@
+package ffi
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// coreError is what lindellcore returns in place of the result of a round that failed
+type coreError struct {
+	Error *string `json:"error"`
+}
+
+// parseOutput parses the output of a lindellcore round into result, or returns the error lindellcore reported
+func parseOutput(rst []byte, result interface{}) error {
+	var failed coreError
+	if err := json.Unmarshal(rst, &failed); err != nil {
+		panic(err)
+	}
+	if failed.Error != nil {
+		return errors.New("lindellcore: " + *failed.Error)
+	}
+	if err := json.Unmarshal(rst, result); err != nil {
+		panic(err)
+	}
+	return nil
+}
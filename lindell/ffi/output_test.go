@@ -0,0 +1,19 @@
+package ffi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOutput(t *testing.T) {
+	var rst Round3Result
+	assert.NoError(t, parseOutput([]byte(`{"signature":{"r":"1","s":"2"}}`), &rst))
+	assert.Equal(t, "1", rst.Sig.R)
+	assert.Equal(t, "2", rst.Sig.S)
+
+	rst = Round3Result{}
+	err := parseOutput([]byte(`{"error":"adaptor nonce does not match the ephemeral key exchange"}`), &rst)
+	assert.EqualError(t, err, "lindellcore: adaptor nonce does not match the ephemeral key exchange")
+	assert.Empty(t, rst.Sig.R, "a failed round has no result")
+}
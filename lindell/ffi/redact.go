@@ -0,0 +1,25 @@
+package ffi
+
+import "fmt"
+
+// redacted is printed in place of a secret, as go-rust/lindell/logging does
+const redacted = "[REDACTED]"
+
+// Scalars are secret shares, nonces and Paillier primes are the private key: none of them is ever printed or logged.
+// JSON is left alone as it is how they reach lindellcore.
+
+// RedactFromLogs marks Scalar as a logging.Secret
+func (Scalar) RedactFromLogs() {}
+
+// Format prints Scalar as [REDACTED] whatever the verb
+func (Scalar) Format(f fmt.State, _ rune) {
+	fmt.Fprint(f, redacted)
+}
+
+// RedactFromLogs marks DecryptionKey as a logging.Secret
+func (DecryptionKey) RedactFromLogs() {}
+
+// Format prints DecryptionKey as [REDACTED] whatever the verb
+func (DecryptionKey) Format(f fmt.State, _ rune) {
+	fmt.Fprint(f, redacted)
+}
@@ -45,6 +45,10 @@ type EphEcKeyPair struct {
 	SecretShare Scalar `json:"secret_share"`
 }
 
+type Round1Input struct {
+	Curve string `json:"curve"`
+}
+
 type Round1Result struct {
 	EphEcKeyPairParty1 EphEcKeyPair `json:"eph_ec_key_pair_party1"`
 
@@ -53,6 +57,8 @@ type Round1Result struct {
 }
 
 type Round2Input struct {
+	Curve string `json:"curve"`
+
 	PaillierN      string `json:"paillier_n"`
 	EncryptedShare string `json:"encrypted_share"`
 
@@ -63,6 +69,9 @@ type Round2Input struct {
 
 	// msg from <- party1
 	EphPartyOneFirstMessage EphKeyGenFirstMsg `json:"eph_party_one_first_message"`
+
+	// k1 * Y, set only when producing an adaptor pre-signature
+	AdaptorPoint *Point `json:"adaptor_point,omitempty"`
 }
 
 type EphKeyGenSecondMsg struct {
@@ -86,13 +95,30 @@ type PartyTwoEphKeyGenFirstMsg struct {
 	ZkPokCommitment string `json:"zk_pok_commitment"`
 }
 
+type DLEqProof struct {
+	A1 Point  `json:"a1"`
+	A2 Point  `json:"a2"`
+	Z  Scalar `json:"z"`
+}
+
+// AdaptorNonce carries R_hat = k * G and R = k * Y together with party2's proof that R_hat = k2 * R1 and
+// R = k2 * (k1 * Y) share a discrete log
+type AdaptorNonce struct {
+	RHat  Point     `json:"r_hat"`
+	R     Point     `json:"r"`
+	Proof DLEqProof `json:"proof"`
+}
+
 type Round2Result struct {
 	EphPartyTwoFirstMessage  PartyTwoEphKeyGenFirstMsg `json:"eph_party_two_first_message"`
 	EphPartyTwoSecondMessage EphKeyGenSecondMsg        `json:"eph_party_two_second_message"`
 	PartialSig               PartialSig                `json:"partial_sig"`
+	AdaptorNonce             *AdaptorNonce             `json:"adaptor_nonce,omitempty"`
 }
 
 type Round3Input struct {
+	Curve string `json:"curve"`
+
 	PlainSig string       `json:"plain_sign"`
 	R1Rst    Round1Result `json:"r1_rst"`
 	R2Rst    Round2Result `json:"r2_rst"`
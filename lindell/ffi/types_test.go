@@ -1,9 +1,16 @@
 package ffi
 
 import (
+	"crypto/elliptic"
 	"encoding/json"
-	"github.com/stretchr/testify/assert"
+	"fmt"
+	"math/big"
 	"testing"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestInterfaceType(t *testing.T) {
@@ -35,3 +42,89 @@ func TestInterfaceType(t *testing.T) {
 	err = json.Unmarshal([]byte(rst3Str), &r3Rst)
 	assert.Nil(t, err, " fail to convert str to Round3Result")
 }
+
+func TestPointRoundTrip(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), elliptic.P256()} {
+		for i := 0; i < 8; i++ {
+			P := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+			fp, err := NewPoint(P)
+			assert.NoError(t, err)
+			P2, err := fp.ECPoint(ec)
+			assert.NoError(t, err)
+			assert.True(t, P.Equals(P2), "decoded point must equal the encoded one")
+		}
+	}
+
+	// a point serialised by lindellcore
+	var fp Point
+	err := json.Unmarshal([]byte(`{"curve":"secp256k1","point":[3,46,2,38,172,180,169,237,145,254,125,231,113,106,203,247,232,226,189,92,156,2,98,210,232,6,153,182,240,150,199,111,27]}`), &fp)
+	assert.NoError(t, err)
+	P, err := fp.ECPoint(tss.S256())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), P.Y().Bit(0))
+
+	_, err = fp.ECPoint(elliptic.P256())
+	assert.Error(t, err, "a secp256k1 point must not decode onto P-256")
+}
+
+func TestFixedSizeEncodings(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), elliptic.P256()} {
+		// both have leading zero bytes, which big.Int.Bytes would drop
+		s := big.NewInt(7)
+		fs, err := NewScalar(ec, s)
+		assert.NoError(t, err)
+		assert.Len(t, fs.Scalar, 32)
+		assert.Zero(t, fs.BigInt().Cmp(s))
+
+		fp, err := NewPoint(crypto.ScalarBaseMult(ec, s))
+		assert.NoError(t, err)
+		assert.Len(t, fp.Point, 33)
+
+		_, err = NewScalar(ec, ec.Params().N)
+		assert.Error(t, err)
+		_, err = NewScalar(ec, big.NewInt(-1))
+		assert.Error(t, err)
+	}
+}
+
+func TestSecretsAreNotPrinted(t *testing.T) {
+	pair := EphEcKeyPair{
+		PublicShare: Point{Curve: CurveSecp256k1, Point: []uint{2, 1}},
+		SecretShare: Scalar{Curve: CurveSecp256k1, Scalar: []uint{37, 253, 164}},
+	}
+	priv := Party1Private{X1: pair.SecretShare, PaillierPriv: DecryptionKey{P: "1000003", Q: "1000033"}, CKeyRandomness: "5"}
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%d", "%x"} {
+		for _, v := range []interface{}{pair, &pair, priv, &priv, pair.SecretShare, priv.PaillierPriv} {
+			out := fmt.Sprintf(verb, v)
+			assert.NotContains(t, out, "253", "%s of %T", verb, v)
+			assert.NotContains(t, out, "1000003", "%s of %T", verb, v)
+		}
+	}
+	assert.Contains(t, fmt.Sprintf("%v", pair), redacted)
+
+	// lindellcore still gets them
+	bz, err := json.Marshal(priv)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bz), `"scalar":[37,253,164]`)
+	assert.Contains(t, string(bz), `"p":"1000003"`)
+}
+
+func TestZeroize(t *testing.T) {
+	secret := func() Scalar { return Scalar{Curve: CurveSecp256k1, Scalar: []uint{37, 253, 164}} }
+	r1 := Round1Result{EphEcKeyPairParty1: EphEcKeyPair{SecretShare: secret()}}
+	// the input of round 3 is a copy of round 1's result, and shares its secret
+	in3 := Round3Input{R1Rst: r1}
+	backing := r1.EphEcKeyPairParty1.SecretShare.Scalar
+	in3.Zeroize()
+	assert.Equal(t, []uint{0, 0, 0}, backing)
+	assert.Nil(t, in3.R1Rst.EphEcKeyPairParty1.SecretShare.Scalar)
+
+	in2 := Round2Input{EcKeyPairParty2: EphEcKeyPair{SecretShare: secret()}}
+	backing = in2.EcKeyPairParty2.SecretShare.Scalar
+	in2.Zeroize()
+	assert.Equal(t, []uint{0, 0, 0}, backing)
+
+	pair := EcKeyPair{SecretShare: secret()}
+	pair.Zeroize()
+	assert.Nil(t, pair.SecretShare.Scalar)
+}
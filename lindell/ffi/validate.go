@@ -0,0 +1,118 @@
+package ffi
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// commitmentBits bounds lindellcore's hash commitments and their blind factors
+const commitmentBits = 256
+
+// Validate checks that s is a scalar of ec: named after it, at most as long as its order and reduced
+func (s Scalar) Validate(ec elliptic.Curve) error {
+	name, err := CurveName(ec)
+	if err != nil {
+		return err
+	}
+	if s.Curve != name {
+		return fmt.Errorf("scalar is on %q, expected %q", s.Curve, name)
+	}
+	bz, err := uintBytes(s.Scalar)
+	if err != nil {
+		return err
+	}
+	order := ec.Params().N
+	if len(bz) == 0 || len(bz) > (order.BitLen()+7)/8 || new(big.Int).SetBytes(bz).Cmp(order) >= 0 {
+		return errors.New("scalar is not reduced modulo the order")
+	}
+	return nil
+}
+
+func (pf ECDDHProof) Validate(ec elliptic.Curve) error {
+	return validateProof(ec, pf.A1, pf.A2, pf.Z)
+}
+
+func (pf DLEqProof) Validate(ec elliptic.Curve) error {
+	return validateProof(ec, pf.A1, pf.A2, pf.Z)
+}
+
+// Validate checks every point and scalar of party one's first message
+func (m EphKeyGenFirstMsg) Validate(ec elliptic.Curve) error {
+	if err := m.DLogProof.Validate(ec); err != nil {
+		return fmt.Errorf("d_log_proof: %v", err)
+	}
+	return validatePoints(ec, m.PublicShare, m.C)
+}
+
+// Validate checks every point, scalar and number of party two's output. Whether the partial signature is a ciphertext
+// depends on the Paillier key, which only party one holds.
+func (r Round2Result) Validate(ec elliptic.Curve) error {
+	first := r.EphPartyTwoFirstMessage
+	witness := r.EphPartyTwoSecondMessage.CommWitness
+	for name, s := range map[string]string{
+		"pk_commitment":              first.PkCommitment,
+		"zk_pok_commitment":          first.ZkPokCommitment,
+		"pk_commitment_blind_factor": witness.PkCommitmentBlindFactor,
+		"zk_pok_blind_factor":        witness.ZkPokBlindFactor,
+	} {
+		if n, err := parseDecimal(s); err != nil || n.BitLen() > commitmentBits {
+			return fmt.Errorf("%s is not a number of at most %d bits", name, commitmentBits)
+		}
+	}
+	if err := witness.DLogProof.Validate(ec); err != nil {
+		return fmt.Errorf("d_log_proof: %v", err)
+	}
+	if err := validatePoints(ec, witness.PublicShare, witness.C); err != nil {
+		return err
+	}
+	if _, err := parseDecimal(r.PartialSig.C3); err != nil {
+		return fmt.Errorf("c3: %v", err)
+	}
+	if nonce := r.AdaptorNonce; nonce != nil {
+		if err := nonce.Proof.Validate(ec); err != nil {
+			return fmt.Errorf("adaptor nonce proof: %v", err)
+		}
+		return validatePoints(ec, nonce.RHat, nonce.R)
+	}
+	return nil
+}
+
+// ----- //
+
+func validateProof(ec elliptic.Curve, a1, a2 Point, z Scalar) error {
+	if err := validatePoints(ec, a1, a2); err != nil {
+		return err
+	}
+	return z.Validate(ec)
+}
+
+func validatePoints(ec elliptic.Curve, points ...Point) error {
+	for _, p := range points {
+		if _, err := p.ECPoint(ec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDecimal parses a non-negative number in the canonical decimal form of lindellcore, without sign or leading
+// zeros
+func parseDecimal(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || n.String() != s {
+		return nil, fmt.Errorf("%q is not a canonical decimal number", s)
+	}
+	return n, nil
+}
+
+// uintBytes is Uint2Byte for input from a peer, which must not hide anything in the high bits
+func uintBytes(data []uint) ([]byte, error) {
+	for _, u := range data {
+		if u > 0xff {
+			return nil, errors.New("byte array holds a value above 255")
+		}
+	}
+	return Uint2Byte(data), nil
+}
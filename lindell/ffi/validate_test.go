@@ -0,0 +1,66 @@
+package ffi
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+// party one's first message and party two's output as lindellcore produces them
+const (
+	firstMsgJSON = `{"d_log_proof":{"a1":{"curve":"secp256k1","point":[3,136,247,183,232,115,188,25,145,204,55,120,250,204,89,120,12,119,194,124,111,65,47,224,251,220,166,107,122,82,84,122,215]},"a2":{"curve":"secp256k1","point":[2,39,4,146,3,67,171,144,59,165,241,26,141,216,97,149,88,133,106,200,122,54,153,87,76,75,175,70,1,222,93,16,144]},"z":{"curve":"secp256k1","scalar":[153,114,59,70,62,131,43,140,189,170,249,74,205,38,159,91,51,112,37,2,20,148,14,203,36,207,234,117,11,20,167,32]}},"public_share":{"curve":"secp256k1","point":[3,46,2,38,172,180,169,237,145,254,125,231,113,106,203,247,232,226,189,92,156,2,98,210,232,6,153,182,240,150,199,111,27]},"c":{"curve":"secp256k1","point":[2,221,29,155,224,53,30,14,174,86,233,148,175,70,81,225,122,22,132,251,190,11,154,191,125,203,5,199,99,12,31,185,54]}}`
+	rst2JSON     = `{"eph_party_two_first_message":{"pk_commitment":"66986376533250027827140837758455093186138254236878683000153115076246179455047","zk_pok_commitment":"38583635392182058497600735620920896690053286875126645934235864970928281739516"},"eph_party_two_second_message":{"comm_witness":{"pk_commitment_blind_factor":"96324591265635270245697217666018851113014093323644433759649116505854915284243","zk_pok_blind_factor":"43537797225161695293523913418531901607310899769913854158667686968069669816015","public_share":{"curve":"secp256k1","point":[3,242,10,189,39,105,28,166,245,176,232,3,19,134,157,18,136,227,16,215,111,101,109,144,56,102,27,162,62,1,90,22,117]},"d_log_proof":{"a1":{"curve":"secp256k1","point":[3,107,184,90,137,204,9,73,172,67,155,55,15,228,84,216,43,24,32,42,93,115,173,147,41,45,222,147,116,246,13,120,91]},"a2":{"curve":"secp256k1","point":[3,100,96,29,212,52,216,48,43,155,94,110,6,184,230,29,88,77,242,78,178,142,243,48,245,77,190,134,108,62,237,178,84]},"z":{"curve":"secp256k1","scalar":[210,143,194,104,243,164,37,213,187,185,167,135,129,196,211,57,78,98,161,144,176,219,27,14,234,109,108,165,124,199,24,8]}},"c":{"curve":"secp256k1","point":[3,201,83,107,54,93,134,126,133,5,53,16,174,133,138,133,247,208,237,74,138,108,140,128,89,174,162,232,171,235,140,127,164]}}},"partial_sig":{"c3":"16052707203871319086327000321898233152450951327132207413797997464361528780214340735220411164838518209513"}}`
+)
+
+func TestValidate(t *testing.T) {
+	var msg1 EphKeyGenFirstMsg
+	assert.NoError(t, json.Unmarshal([]byte(firstMsgJSON), &msg1))
+	assert.NoError(t, msg1.Validate(tss.S256()))
+	assert.Error(t, msg1.Validate(elliptic.P256()), "a secp256k1 message must not validate on P-256")
+	assert.Error(t, EphKeyGenFirstMsg{}.Validate(tss.S256()), "a message without points must not validate")
+
+	var rst2 Round2Result
+	assert.NoError(t, json.Unmarshal([]byte(rst2JSON), &rst2))
+	assert.NoError(t, rst2.Validate(tss.S256()))
+}
+
+func TestValidateRejectsBadValues(t *testing.T) {
+	ec := tss.S256()
+	for name, bz := range map[string]string{
+		"byte above 255":      strings.Replace(firstMsgJSON, `[3,46,2,`, `[259,46,2,`, 1),
+		"point off the curve": strings.Replace(firstMsgJSON, `[3,46,2,38,172,`, `[3,46,2,38,173,`, 1),
+		"x above the field":   strings.Replace(firstMsgJSON, `[3,46,2,38,172,180,169,237,145,254,125,231,113,106,203,247,232,226,189,92,156,2,98,210,232,6,153,182,240,150,199,111,27]`, `[3,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255]`, 1),
+		"uncompressed prefix": strings.Replace(firstMsgJSON, `[3,46,2,`, `[4,46,2,`, 1),
+		"short point":         strings.Replace(firstMsgJSON, `[3,46,2,38,`, `[3,46,2,`, 1),
+		"other curve":         strings.Replace(firstMsgJSON, `"secp256k1"`, `"p256"`, 1),
+		"scalar above order":  strings.Replace(firstMsgJSON, `"scalar":[153,`, `"scalar":[255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,255,`, 1),
+		"long scalar":         strings.Replace(firstMsgJSON, `"scalar":[153,`, `"scalar":[0,153,`, 1),
+		"empty scalar":        strings.Replace(firstMsgJSON, `"scalar":[153,114,59,70,62,131,43,140,189,170,249,74,205,38,159,91,51,112,37,2,20,148,14,203,36,207,234,117,11,20,167,32]`, `"scalar":[]`, 1),
+		"null scalar":         strings.Replace(firstMsgJSON, `"scalar":[153,114,59,70,62,131,43,140,189,170,249,74,205,38,159,91,51,112,37,2,20,148,14,203,36,207,234,117,11,20,167,32]`, `"scalar":null`, 1),
+	} {
+		var msg EphKeyGenFirstMsg
+		if !assert.NoError(t, json.Unmarshal([]byte(bz), &msg), name) {
+			continue
+		}
+		assert.Error(t, msg.Validate(ec), name)
+	}
+
+	for name, bz := range map[string]string{
+		"leading zero":        strings.Replace(rst2JSON, `"pk_commitment":"6`, `"pk_commitment":"06`, 1),
+		"sign":                strings.Replace(rst2JSON, `"pk_commitment":"6`, `"pk_commitment":"+6`, 1),
+		"commitment too long": strings.Replace(rst2JSON, `"pk_commitment":"6`, `"pk_commitment":"9999999999999999999999999999996`, 1),
+		"not a number":        strings.Replace(rst2JSON, `"c3":"1`, `"c3":"x1`, 1),
+		"empty c3":            strings.Replace(rst2JSON, `"c3":"16052707203871319086327000321898233152450951327132207413797997464361528780214340735220411164838518209513"`, `"c3":""`, 1),
+		"bad adaptor nonce":   rst2JSON[:len(rst2JSON)-1] + `,"adaptor_nonce":{"r_hat":{"curve":"","point":null},"r":{"curve":"","point":null},"proof":{"a1":{"curve":"","point":null},"a2":{"curve":"","point":null},"z":{"curve":"","scalar":null}}}}`,
+	} {
+		var rst2 Round2Result
+		if !assert.NoError(t, json.Unmarshal([]byte(bz), &rst2), name) {
+			continue
+		}
+		assert.Error(t, rst2.Validate(ec), name)
+	}
+}
@@ -0,0 +1,35 @@
+package ffi
+
+import "go-rust/lindell/zeroize"
+
+// Zeroize overwrites the scalar in place. Copies of a struct holding a Scalar share its backing array, so they are
+// wiped too.
+func (s *Scalar) Zeroize() {
+	zeroize.Uints(s.Scalar)
+	s.Scalar = nil
+}
+
+// Zeroize overwrites the secret share
+func (k *EcKeyPair) Zeroize() {
+	k.SecretShare.Zeroize()
+}
+
+// Zeroize overwrites the secret share
+func (k *EphEcKeyPair) Zeroize() {
+	k.SecretShare.Zeroize()
+}
+
+// Zeroize overwrites party one's ephemeral secret share
+func (r *Round1Result) Zeroize() {
+	r.EphEcKeyPairParty1.Zeroize()
+}
+
+// Zeroize overwrites party two's secret share
+func (in *Round2Input) Zeroize() {
+	in.EcKeyPairParty2.Zeroize()
+}
+
+// Zeroize overwrites party one's ephemeral secret share. PlainSig is a string and cannot be wiped.
+func (in *Round3Input) Zeroize() {
+	in.R1Rst.Zeroize()
+}
@@ -0,0 +1,68 @@
+package schnorrsign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 4
+	round.started = true
+	round.resetOK()
+
+	i := round.PartyID().Index
+	ec := round.Params().EC()
+	modN := common.ModInt(ec.Params().N)
+
+	s := round.temp.si
+	for j, Pj := range round.Parties().IDs() {
+		round.ok[j] = true
+		if j == i {
+			continue
+		}
+		// check the peer's partial signature on its own, s_j*G = K_j + e*X_j, so a bad share is attributable
+		sj := round.temp.signRound3Messages[j].Content().(*SignRound3Message).UnmarshalS()
+		if sj.Cmp(ec.Params().N) >= 0 {
+			return round.WrapError(errors.New("partial signature is not reduced mod n"), Pj)
+		}
+		expected, err := round.temp.bigKj[j].Add(round.temp.bigXj[j].ScalarMult(round.temp.e))
+		if err != nil || !crypto.ScalarBaseMult(ec, sj).Equals(expected) {
+			return round.WrapError(errors.New("partial signature verification failed"), Pj)
+		}
+		s = modN.Add(s, sj)
+	}
+
+	// save the signature for final output in the scheme's wire format
+	round.data.R, round.data.S = round.scheme.Encode(round.temp.r, s)
+	round.data.Signature = append(append([]byte{}, round.data.R...), round.data.S...)
+	round.data.M = round.temp.m
+
+	if !round.scheme.Verify(round.temp.m, round.data.Signature) {
+		return round.WrapError(fmt.Errorf("signature verification failed"))
+	}
+
+	round.end <- round.data
+
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
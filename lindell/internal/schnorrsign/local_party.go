@@ -0,0 +1,188 @@
+// Package schnorrsign holds the two-party Schnorr signing rounds shared by lindell/eddsa/signing and
+// lindell/bip340/signing: each party commits to a nonce point, reveals it with a proof of knowledge, and sends its
+// partial signature s_i = k_i + e*x_i, which the peer checks on its own before adding it up.
+// The parts that differ between the protocols (the key, curve checks, even-Y handling and the challenge) come from a Scheme.
+package schnorrsign
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	cmt "github.com/bnb-chain/tss-lib/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// Implements Party
+// Implements Stringer
+var _ tss.Party = (*LocalParty)(nil)
+var _ fmt.Stringer = (*LocalParty)(nil)
+
+type (
+	// Scheme is the protocol-specific part of two-party Schnorr signing
+	Scheme interface {
+		// Prepare checks the parameters and the key, and returns this party's additive share x_i of the signing key
+		// and the public shares X_j of all parties in the order of params.Parties()
+		Prepare(params *tss.Parameters) (xi *big.Int, bigXj []*crypto.ECPoint, err error)
+		// CheckNonce rejects a peer's nonce point that is on the curve but not usable by the protocol
+		CheckNonce(Kj *crypto.ECPoint) error
+		// NegateNonce reports whether the joint nonce R must be negated, together with every party's nonce, before
+		// the challenge is computed
+		NegateNonce(R *crypto.ECPoint) bool
+		// Challenge returns e for the joint nonce R and the message m
+		Challenge(R *crypto.ECPoint, m []byte) *big.Int
+		// Encode returns the signature (R, s) in the protocol's wire format
+		Encode(R *crypto.ECPoint, s *big.Int) (rBz, sBz []byte)
+		// Verify checks the encoded signature on m with the signing key
+		Verify(m, sig []byte) bool
+	}
+
+	LocalParty struct {
+		*tss.BaseParty
+		params   *tss.Parameters
+		scheme   Scheme
+		taskName string
+
+		temp localTempData
+		data common.SignatureData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- *common.SignatureData
+	}
+
+	localMessageStore struct {
+		signRound1Messages,
+		signRound2Messages,
+		signRound3Messages []tss.ParsedMessage
+	}
+
+	localTempData struct {
+		localMessageStore
+
+		// Schnorr signatures hash the message itself, so unlike ECDSA signing this is not reduced to a digest
+		m []byte
+
+		// prepare(): x_i is this party's additive share of the signing key, bigXj the public shares
+		xi    *big.Int
+		bigXj []*crypto.ECPoint
+
+		// temp data (thrown away after sign)
+		ki       *big.Int
+		bigKi    *crypto.ECPoint
+		deCommit cmt.HashDeCommitment
+		cjs      []cmt.HashCommitment
+		bigKj    []*crypto.ECPoint
+
+		// round 3
+		r  *crypto.ECPoint
+		e  *big.Int
+		si *big.Int
+	}
+)
+
+// NewLocalParty returns a party for two-party signing of msg under scheme; taskName identifies the protocol in errors.
+func NewLocalParty(
+	msg []byte,
+	params *tss.Parameters,
+	scheme Scheme,
+	taskName string,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) *LocalParty {
+	partyCount := len(params.Parties().IDs())
+	p := &LocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		scheme:    scheme,
+		taskName:  taskName,
+		temp:      localTempData{},
+		data:      common.SignatureData{},
+		out:       out,
+		end:       end,
+	}
+	// msgs init
+	p.temp.signRound1Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.signRound2Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.signRound3Messages = make([]tss.ParsedMessage, partyCount)
+
+	// temp data init
+	p.temp.m = msg
+	p.temp.cjs = make([]cmt.HashCommitment, partyCount)
+	p.temp.bigKj = make([]*crypto.ECPoint, partyCount)
+	return p
+}
+
+func (p *LocalParty) FirstRound() tss.Round {
+	return newRound1(p.params, p.scheme, p.taskName, &p.data, &p.temp, p.out, p.end)
+}
+
+func (p *LocalParty) Start() *tss.Error {
+	return tss.BaseStart(p, p.taskName, func(round tss.Round) *tss.Error {
+		round1, ok := round.(*round1)
+		if !ok {
+			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
+		}
+		if err := round1.prepare(); err != nil {
+			return round.WrapError(err)
+		}
+		return nil
+	})
+}
+
+func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, p.taskName)
+}
+
+func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+	}
+	return true, nil
+}
+
+func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	// ValidateBasic is cheap; double-check the message here in case the public StoreMessage was called externally
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	fromPIdx := msg.GetFrom().Index
+
+	// switch/case is necessary to store any messages beyond current round
+	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	switch msg.Content().(type) {
+	case *SignRound1Message:
+		p.temp.signRound1Messages[fromPIdx] = msg
+	case *SignRound2Message:
+		p.temp.signRound2Messages[fromPIdx] = msg
+	case *SignRound3Message:
+		p.temp.signRound3Messages[fromPIdx] = msg
+	default: // unrecognised message, just ignore!
+		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (p *LocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *LocalParty) String() string {
+	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
+}
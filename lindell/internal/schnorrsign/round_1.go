@@ -0,0 +1,90 @@
+package schnorrsign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	cmt "github.com/bnb-chain/tss-lib/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func newRound1(params *tss.Parameters, scheme Scheme, taskName string, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+	return &round1{
+		&base{params, scheme, taskName, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	round.ok[i] = true
+
+	ec := round.Params().EC()
+	round.temp.ki = common.GetRandomPositiveInt(ec.Params().N)
+	round.temp.bigKi = crypto.ScalarBaseMult(ec, round.temp.ki)
+	round.temp.bigKj[i] = round.temp.bigKi
+
+	// commit to our nonce point so the peer cannot choose theirs as a function of it
+	cmtDeCmt := cmt.NewHashCommitment(round.temp.bigKi.X(), round.temp.bigKi.Y())
+	round.temp.deCommit = cmtDeCmt.D
+
+	r1msg := NewSignRound1Message(Pi, cmtDeCmt.C)
+	round.out <- r1msg
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound1Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
+
+// ----- //
+
+// prepare takes this party's additive key share from the scheme and checks that the public shares add up
+func (round *round1) prepare() error {
+	xi, bigXj, err := round.scheme.Prepare(round.Params())
+	if err != nil {
+		return err
+	}
+	if len(bigXj) != len(round.Parties().IDs()) {
+		return fmt.Errorf("key has %d public shares, signing with %d parties", len(bigXj), len(round.Parties().IDs()))
+	}
+	i := round.PartyID().Index
+	if xi == nil || bigXj[i] == nil {
+		return errors.New("key share is incomplete")
+	}
+	if !crypto.ScalarBaseMult(round.Params().EC(), xi).Equals(bigXj[i]) {
+		return errors.New("key share does not match its public share")
+	}
+	round.temp.xi = xi
+	round.temp.bigXj = bigXj
+	return nil
+}
@@ -0,0 +1,99 @@
+package schnorrsign
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	cmt "github.com/bnb-chain/tss-lib/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	round.ok[i] = true
+	ec := round.Params().EC()
+	modN := common.ModInt(ec.Params().N)
+
+	R := round.temp.bigKi
+	for j, Pj := range round.Parties().IDs() {
+		if j == i {
+			continue
+		}
+		r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
+		cmtDeCmt := cmt.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment()}
+		ok, flatKj := cmtDeCmt.DeCommit()
+		if !ok || len(flatKj) != 2 {
+			return round.WrapError(errors.New("de-commitment of the nonce point failed"), Pj)
+		}
+		Kj, err := crypto.NewECPoint(ec, flatKj[0], flatKj[1])
+		if err != nil {
+			return round.WrapError(errors.New("nonce point is not on the curve"), Pj)
+		}
+		if err = round.scheme.CheckNonce(Kj); err != nil {
+			return round.WrapError(err, Pj)
+		}
+		proof, err := r2msg.UnmarshalZKProof(ec)
+		if err != nil || !proof.Verify(Kj) {
+			return round.WrapError(errors.New("failed to prove knowledge of the nonce"), Pj)
+		}
+		round.temp.bigKj[j] = Kj
+		if R, err = R.Add(Kj); err != nil {
+			return round.WrapError(err, Pj)
+		}
+	}
+
+	// e.g. BIP-340 commits to x(R) only, so both parties negate their nonces when R has an odd y;
+	// -P is computed as (n-1)*P so that this holds on any curve
+	if round.scheme.NegateNonce(R) {
+		minusOne := new(big.Int).Sub(ec.Params().N, big.NewInt(1))
+		R = R.ScalarMult(minusOne)
+		round.temp.ki = modN.Sub(big.NewInt(0), round.temp.ki)
+		for j, Kj := range round.temp.bigKj {
+			round.temp.bigKj[j] = Kj.ScalarMult(minusOne)
+		}
+	}
+	round.temp.r = R
+
+	// s_i = k_i + e*x_i
+	round.temp.e = round.scheme.Challenge(R, round.temp.m)
+	round.temp.si = modN.Add(round.temp.ki, modN.Mul(round.temp.e, round.temp.xi))
+
+	r3msg := NewSignRound3Message(Pi, round.temp.si)
+	round.out <- r3msg
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*SignRound3Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	for j, msg := range round.temp.signRound3Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			return false, nil
+		}
+		round.ok[j] = true
+	}
+	return true, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
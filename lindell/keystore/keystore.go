@@ -0,0 +1,316 @@
+// Package keystore stores two-party key shares encrypted on disk. A share is sealed with AES-256-GCM under a key
+// derived from a passphrase with scrypt or Argon2id. Metadata describing the key stays readable without the
+// passphrase and is authenticated together with the ciphertext, so it cannot be swapped between files.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// Version of the file format written by Encrypt
+	Version = 1
+
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+
+	CipherAES256GCM = "aes-256-gcm"
+
+	// curve names as lindellcore knows them
+	CurveSecp256k1 = "secp256k1"
+	CurveSecp256r1 = "secp256r1"
+
+	keyLen  = 32
+	saltLen = 32
+)
+
+var (
+	// ErrDecrypt is returned for a wrong passphrase or a tampered file
+	ErrDecrypt = errors.New("keystore: could not decrypt the key share, wrong passphrase or corrupted file")
+
+	// Scrypt is the default KDF, about a second and 256 MiB per derivation
+	Scrypt = KDFParams{Name: KDFScrypt, N: 1 << 18, R: 8, P: 1}
+	// ScryptLight is for tests and constrained devices
+	ScryptLight = KDFParams{Name: KDFScrypt, N: 1 << 12, R: 8, P: 1}
+	// Argon2id follows the RFC 9106 second recommended option
+	Argon2id = KDFParams{Name: KDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+)
+
+func init() {
+	// ECPoint records its curve by name, shares on P-256 need it registered to round-trip
+	tss.RegisterCurve(CurveSecp256r1, elliptic.P256())
+}
+
+// Metadata describes the key of a share and is readable without the passphrase
+type Metadata struct {
+	KeyID     string `json:"key_id"`
+	PublicKey string `json:"public_key"` // SEC1 compressed, hex
+	Curve     string `json:"curve"`
+	ShareID   string `json:"share_id"` // hex
+	// Role is the signing role of the share's owner, "server" or "client", if it is pinned
+	Role      string    `json:"role,omitempty"`
+	ChainCode string    `json:"chain_code,omitempty"` // hex, for BIP-32 derivation
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// KDFParams select the passphrase KDF and its cost
+type KDFParams struct {
+	Name string `json:"name"`
+	Salt []byte `json:"salt,omitempty"`
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+	// argon2id; Memory is in KiB
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// File is an encrypted key share as stored on disk
+type File struct {
+	Version    int       `json:"version"`
+	Metadata   Metadata  `json:"metadata"`
+	KDF        KDFParams `json:"kdf"`
+	Cipher     string    `json:"cipher"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// NewMetadata describes key. role and chainCode are optional.
+func NewMetadata(key keygen.LocalPartySaveData, role string, chainCode []byte) (Metadata, error) {
+	if key.ECDSAPub == nil || key.ShareID == nil {
+		return Metadata{}, errors.New("keystore: incomplete key share")
+	}
+	curve, ok := tss.GetCurveName(key.ECDSAPub.Curve())
+	if !ok || (curve != CurveSecp256k1 && curve != CurveSecp256r1) {
+		return Metadata{}, errors.New("keystore: unsupported curve")
+	}
+	pub := compressPoint(key.ECDSAPub)
+	meta := Metadata{
+		KeyID:     KeyID(key.ECDSAPub),
+		PublicKey: hex.EncodeToString(pub),
+		Curve:     string(curve),
+		ShareID:   key.ShareID.Text(16),
+		Role:      role,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if len(chainCode) > 0 {
+		meta.ChainCode = hex.EncodeToString(chainCode)
+	}
+	return meta, nil
+}
+
+// KeyID names a key by its public key, both parties' shares of a key have the same ID
+func KeyID(pub *crypto.ECPoint) string {
+	sum := sha256.Sum256(compressPoint(pub))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Encrypt seals key under passphrase. kdf is one of the presets, its salt is generated.
+func Encrypt(key keygen.LocalPartySaveData, meta Metadata, passphrase []byte, kdf KDFParams) (*File, error) {
+	plain, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(plain)
+	f := &File{Version: Version, Metadata: meta, KDF: kdf, Cipher: CipherAES256GCM}
+	if err = f.seal(plain, passphrase); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Decrypt opens the share
+func (f *File) Decrypt(passphrase []byte) (keygen.LocalPartySaveData, error) {
+	var key keygen.LocalPartySaveData
+	plain, err := f.open(passphrase)
+	if err != nil {
+		return key, err
+	}
+	defer zero(plain)
+	if err = json.Unmarshal(plain, &key); err != nil {
+		return key, fmt.Errorf("keystore: decoding the key share: %v", err)
+	}
+	if KeyID(key.ECDSAPub) != f.Metadata.KeyID || key.ShareID.Text(16) != f.Metadata.ShareID {
+		return key, errors.New("keystore: the key share does not match its metadata")
+	}
+	return key, nil
+}
+
+// Rekey re-encrypts the share under newPassphrase with a fresh salt and nonce. kdf may change the KDF too, its
+// zero value keeps the current one.
+func (f *File) Rekey(oldPassphrase, newPassphrase []byte, kdf KDFParams) error {
+	plain, err := f.open(oldPassphrase)
+	if err != nil {
+		return err
+	}
+	defer zero(plain)
+	next := *f
+	if kdf.Name != "" {
+		next.KDF = kdf
+	}
+	next.Version = Version
+	if err = next.seal(plain, newPassphrase); err != nil {
+		return err
+	}
+	*f = next
+	return nil
+}
+
+// Save writes the file readable by its owner only
+func (f *File) Save(path string) error {
+	bz, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bz, '\n'), 0600)
+}
+
+// Load reads a keystore file without decrypting it
+func Load(path string) (*File, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := new(File)
+	if err = json.Unmarshal(bz, f); err != nil {
+		return nil, fmt.Errorf("keystore: parsing %s: %v", path, err)
+	}
+	if f.Version != Version {
+		return nil, fmt.Errorf("keystore: unsupported version %d", f.Version)
+	}
+	return f, nil
+}
+
+// LoadKeyShare reads and decrypts the key share stored at path
+func LoadKeyShare(path string, passphrase []byte) (keygen.LocalPartySaveData, *Metadata, error) {
+	f, err := Load(path)
+	if err != nil {
+		return keygen.LocalPartySaveData{}, nil, err
+	}
+	key, err := f.Decrypt(passphrase)
+	if err != nil {
+		return keygen.LocalPartySaveData{}, nil, err
+	}
+	return key, &f.Metadata, nil
+}
+
+// ----- //
+
+func (f *File) seal(plain, passphrase []byte) error {
+	f.KDF.Salt = make([]byte, saltLen)
+	if _, err := rand.Read(f.KDF.Salt); err != nil {
+		return err
+	}
+	aead, err := f.aead(passphrase)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	ad, err := f.associatedData()
+	if err != nil {
+		return err
+	}
+	f.Ciphertext = aead.Seal(nil, f.Nonce, plain, ad)
+	return nil
+}
+
+func (f *File) open(passphrase []byte) ([]byte, error) {
+	aead, err := f.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != aead.NonceSize() {
+		return nil, errors.New("keystore: invalid nonce")
+	}
+	ad, err := f.associatedData()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, f.Nonce, f.Ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+func (f *File) aead(passphrase []byte) (cipher.AEAD, error) {
+	if f.Cipher != CipherAES256GCM {
+		return nil, fmt.Errorf("keystore: unsupported cipher %q", f.Cipher)
+	}
+	key, err := f.KDF.derive(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// associatedData authenticates everything in the file but the ciphertext
+func (f *File) associatedData() ([]byte, error) {
+	return json.Marshal(struct {
+		Version  int       `json:"version"`
+		Metadata Metadata  `json:"metadata"`
+		KDF      KDFParams `json:"kdf"`
+		Cipher   string    `json:"cipher"`
+	}{f.Version, f.Metadata, f.KDF, f.Cipher})
+}
+
+func (p KDFParams) derive(passphrase []byte) ([]byte, error) {
+	if len(p.Salt) < 16 {
+		return nil, errors.New("keystore: KDF salt too short")
+	}
+	// the bounds keep a crafted file from making a derivation take unbounded time or memory
+	switch p.Name {
+	case KDFScrypt:
+		if p.N < 2 || p.N > 1<<20 || p.N&(p.N-1) != 0 || p.R < 1 || p.R > 32 || p.P < 1 || p.P > 16 {
+			return nil, errors.New("keystore: scrypt parameters out of range")
+		}
+		return scrypt.Key(passphrase, p.Salt, p.N, p.R, p.P, keyLen)
+	case KDFArgon2id:
+		if p.Time < 1 || p.Time > 16 || p.Memory < 8*1024 || p.Memory > 1<<20 || p.Threads < 1 {
+			return nil, errors.New("keystore: argon2id parameters out of range")
+		}
+		return argon2.IDKey(passphrase, p.Salt, p.Time, p.Memory, p.Threads, keyLen), nil
+	default:
+		return nil, fmt.Errorf("keystore: unsupported KDF %q", p.Name)
+	}
+}
+
+func compressPoint(P *crypto.ECPoint) []byte {
+	size := (P.Curve().Params().BitSize + 7) / 8
+	bz := make([]byte, 1+size)
+	bz[0] = 2 | byte(P.Y().Bit(0))
+	P.X().FillBytes(bz[1:])
+	return bz
+}
+
+func zero(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
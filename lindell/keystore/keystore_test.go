@@ -0,0 +1,123 @@
+package keystore
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/stretchr/testify/assert"
+)
+
+var passphrase = []byte("correct horse battery staple")
+
+func loadFixture(t *testing.T) keygen.LocalPartySaveData {
+	bz, err := os.ReadFile("../../test/_ecdsa_fixtures/keygen_data_0.json")
+	if err != nil || len(bz) == 0 {
+		t.Skipf("keygen fixture is not available, create it with go generate ./lindell/signing/: %v", err)
+	}
+	var key keygen.LocalPartySaveData
+	assert.NoError(t, json.Unmarshal(bz, &key))
+	return key
+}
+
+func encryptFixture(t *testing.T, kdf KDFParams) (keygen.LocalPartySaveData, *File) {
+	key := loadFixture(t)
+	meta, err := NewMetadata(key, "server", []byte{1, 2, 3})
+	assert.NoError(t, err)
+	f, err := Encrypt(key, meta, passphrase, kdf)
+	assert.NoError(t, err)
+	return key, f
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, kdf := range []KDFParams{ScryptLight, {Name: KDFArgon2id, Time: 1, Memory: 8 * 1024, Threads: 1}} {
+		t.Run(kdf.Name, func(t *testing.T) {
+			key, f := encryptFixture(t, kdf)
+			assert.Equal(t, CurveSecp256k1, f.Metadata.Curve)
+			assert.Equal(t, KeyID(key.ECDSAPub), f.Metadata.KeyID)
+			assert.Equal(t, "010203", f.Metadata.ChainCode)
+			assert.NotContains(t, string(f.Ciphertext), key.Xi.String(), "the share must not be stored in the clear")
+
+			got, err := f.Decrypt(passphrase)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, key.Xi.Cmp(got.Xi))
+			assert.Equal(t, 0, key.PaillierSK.LambdaN.Cmp(got.PaillierSK.LambdaN))
+			assert.True(t, key.ECDSAPub.Equals(got.ECDSAPub))
+
+			_, err = f.Decrypt([]byte("wrong"))
+			assert.ErrorIs(t, err, ErrDecrypt)
+		})
+	}
+}
+
+func TestMetadataIsAuthenticated(t *testing.T) {
+	_, f := encryptFixture(t, ScryptLight)
+	f.Metadata.Role = "client"
+	_, err := f.Decrypt(passphrase)
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestRekey(t *testing.T) {
+	key, f := encryptFixture(t, ScryptLight)
+	oldSalt := f.KDF.Salt
+	newPassphrase := []byte("another passphrase")
+
+	assert.ErrorIs(t, f.Rekey([]byte("wrong"), newPassphrase, KDFParams{}), ErrDecrypt)
+	assert.NoError(t, f.Rekey(passphrase, newPassphrase, KDFParams{}))
+	assert.NotEqual(t, oldSalt, f.KDF.Salt)
+
+	_, err := f.Decrypt(passphrase)
+	assert.ErrorIs(t, err, ErrDecrypt)
+	got, err := f.Decrypt(newPassphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, key.Xi.Cmp(got.Xi))
+
+	// and switch KDF on the way
+	assert.NoError(t, f.Rekey(newPassphrase, passphrase, KDFParams{Name: KDFArgon2id, Time: 1, Memory: 8 * 1024, Threads: 1}))
+	assert.Equal(t, KDFArgon2id, f.KDF.Name)
+	_, err = f.Decrypt(passphrase)
+	assert.NoError(t, err)
+}
+
+func TestLoadKeyShare(t *testing.T) {
+	key, f := encryptFixture(t, ScryptLight)
+	path := filepath.Join(t.TempDir(), "share.json")
+	assert.NoError(t, f.Save(path))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	got, meta, err := LoadKeyShare(path, passphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, key.Xi.Cmp(got.Xi))
+	assert.Equal(t, "server", meta.Role)
+
+	_, _, err = LoadKeyShare(path, []byte("wrong"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestP256Share(t *testing.T) {
+	key := loadFixture(t)
+	key.ECDSAPub = crypto.ScalarBaseMult(elliptic.P256(), big.NewInt(42))
+	meta, err := NewMetadata(key, "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, CurveSecp256r1, meta.Curve)
+	f, err := Encrypt(key, meta, passphrase, ScryptLight)
+	assert.NoError(t, err)
+	got, err := f.Decrypt(passphrase)
+	assert.NoError(t, err)
+	assert.True(t, key.ECDSAPub.Equals(got.ECDSAPub))
+}
+
+func TestRejectsExpensiveKDFParams(t *testing.T) {
+	_, f := encryptFixture(t, ScryptLight)
+	f.KDF.N = 1 << 30
+	_, err := f.Decrypt(passphrase)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDecrypt)
+}
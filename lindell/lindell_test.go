@@ -3,6 +3,7 @@ package lindell
 import (
 	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
 	"go-rust/lindell/signing"
 	"math/big"
@@ -10,6 +11,8 @@ import (
 
 	"go-rust/lindell/ffi"
 
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
 	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/tss"
 	"github.com/stretchr/testify/assert"
@@ -64,12 +67,13 @@ func runSigningParallel(b *testing.B) {
 }
 
 func runSigningOnce(b *testing.B, party1Key ffi.Party1Private, input2 ffi.Round2Input) {
-	rst1 := ffi.Round1()
-	assert.NotNil(b, rst1)
+	rst1, err := ffi.Round1(ffi.Round1Input{Curve: ffi.CurveSecp256k1})
+	assert.NoError(b, err)
 
+	input2.Curve = ffi.CurveSecp256k1
 	input2.EphPartyOneFirstMessage = rst1.EphPartyOneFirstMessage
-	rst2 := ffi.Round2(input2)
-	assert.NotNil(b, rst2)
+	rst2, err := ffi.Round2(input2)
+	assert.NoError(b, err)
 
 	decryptionKey := GenerateKeyPair(ffi.Str2BigInt(party1Key.PaillierPriv.P), ffi.Str2BigInt(party1Key.PaillierPriv.Q))
 	partialSig := ffi.Str2BigInt(rst2.PartialSig.C3)
@@ -77,29 +81,31 @@ func runSigningOnce(b *testing.B, party1Key ffi.Party1Private, input2 ffi.Round2
 	assert.Nil(b, err)
 
 	input3 := ffi.Round3Input{
+		Curve:    ffi.CurveSecp256k1,
 		PlainSig: rst.String(),
 		R1Rst:    rst1,
 		R2Rst:    rst2,
 	}
 
-	rst3 := ffi.Round3(input3)
-	assert.NotNil(b, rst3)
+	_, err = ffi.Round3(input3)
+	assert.NoError(b, err)
 }
 
 func TestLindell(t *testing.T) {
 	input2Str := `{"paillier_n":"18504938864613671363746378788418213552070388456218034676818001038458184936646431828921011860546678286036016155455398076588976671874722065601730222937104351023661926293904642803511087718739374055575390090637318337319194804401455013040005192355310267335946784121967016870296379730428911442702128306558293975791075836277282737484270823875646695885534073876631989600236425658371602772931882783622967216340121143516884781017101955510170454269100198856644843158980462849001254186966464168581080750582893051700464173657968449976372835211510098033173769044327897445770307329512052146351218131782657510830138338818191326796721","encrypted_share":"77545905527166824592983718316235116049481986206479807150062250831067956747994431178268042795117780977118057018538624450483745898948512394562879704349609515354288811247683005430614922131334447250651130654873796648520303333492382147403956657228419612275605126253891635667404123279956815836365705520906307089042655734658260269066926061728694622572478379585983198408400601861716534075738939334177386727333582208159052428608603218958280149111183953696500922145275302343036071795657160630050555930722995350336192911771940584737189168715399881937098061455290346729566699038807930124466338014298183882132798630797002874821857118143712981002681682726948575397177213340135730559606190965271160157209083526066584370927916866035924965923871898736349269237528884881419917693582963030991377004065546646257155950759495578637012524485992592704171648638574807923288448266584345401914003286754919138519832207612018986779888667270697000448816645363404655574091018269440476452349420595012158600272505270402359913664897574780989219387871122123606267176672707882218061993724823438907164860690396934014441064793239961475162903146090098476713640521818393142038519344909071646174494854087247822865913067415791415369788828568239178591032356462772638536042953","ec_key_pair_party2":{"public_share":{"curve":"secp256k1","point":[3,205,59,147,32,242,32,125,228,6,61,94,169,199,115,164,73,195,136,6,205,108,117,130,133,26,149,129,191,184,118,174,118]},"secret_share":{"curve":"secp256k1","scalar":[33,28,106,193,29,249,241,247,170,54,78,192,238,160,98,139,33,154,181,16,182,50,93,22,201,136,213,151,169,91,70,120]}},"message":"1234","eph_party_one_first_message":{"d_log_proof":{"a1":{"curve":"secp256k1","point":[2,35,69,3,50,102,192,41,226,185,88,128,194,174,18,188,215,16,41,137,69,10,23,222,151,221,197,229,139,21,55,201,65]},"a2":{"curve":"secp256k1","point":[3,206,123,197,119,225,75,58,15,214,237,177,20,81,217,174,235,141,104,4,168,154,164,247,122,141,30,68,28,60,152,145,12]},"z":{"curve":"secp256k1","scalar":[4,233,43,72,102,84,253,248,222,46,111,48,171,135,251,164,175,232,72,32,201,10,126,92,142,147,56,212,125,60,222,129]}},"public_share":{"curve":"secp256k1","point":[3,213,85,120,188,234,31,218,134,17,179,18,152,183,148,47,18,180,153,37,140,251,28,122,182,174,239,59,10,195,251,214,190]},"c":{"curve":"secp256k1","point":[2,40,209,201,150,191,245,234,131,132,221,249,197,141,127,3,216,114,238,186,198,71,100,66,53,143,86,54,219,150,54,236,120]}}}`
 	party1KeyStr := `{"x1":{"curve":"secp256k1","scalar":[136,135,85,124,0,218,4,228,18,47,14,64,114,100,72,161,87,130,184,251,185,204,35,211,5,78,4,33,132,218,134,18]},"paillier_priv":{"p":"143800107728886147995962278233960735716526997278458191507437524547814604392942640885627076734129026043843024606095619799821315655235150651603673295407161576092410797980409712640320907044691491044966541236992175680272258668515263330282341629152521935986903370617264806301925763457908450480420197217516842639531","q":"128685153000733482686286904235912801010952070937415349241976279137611645850966738635405906354594128102145354530167266436685551603900162320627033304638560547802756451842394957618997744641947482581553926674207807153245127456322738634176167545597063239550433573596493091793290565319617721270790815003853299977491"},"c_key_randomness":"3135610702459994063487917461002958047908990350213660263129962400036615534787348748884402855695991387809972522891310437915708431668696510768197861874285406998265978794723959181312996626882550666092361649933511728595173684531656166107717876409289693735051449425885614526114366966360655494169914478068829341701377162258904250072220783219143208149467352553396385392117856524430690582880625280926447198140971809007731907216976678656386715977112617485502441862693436709391718240339077372849203451627060755045394161023045006023828638548027450254564468565464657397078412055973067269879654515520001002337470157097488953130538"}`
 
-	rst1 := ffi.Round1()
-	assert.NotNil(t, rst1)
+	rst1, err := ffi.Round1(ffi.Round1Input{Curve: ffi.CurveSecp256k1})
+	assert.NoError(t, err)
 
 	var input2 ffi.Round2Input
-	err := json.Unmarshal([]byte(input2Str), &input2)
+	err = json.Unmarshal([]byte(input2Str), &input2)
 	assert.Nil(t, err)
 
+	input2.Curve = ffi.CurveSecp256k1
 	input2.EphPartyOneFirstMessage = rst1.EphPartyOneFirstMessage
-	rst2 := ffi.Round2(input2)
-	assert.NotNil(t, rst2)
+	rst2, err := ffi.Round2(input2)
+	assert.NoError(t, err)
 
 	var party1Key ffi.Party1Private
 	err = json.Unmarshal([]byte(party1KeyStr), &party1Key)
@@ -111,13 +117,14 @@ func TestLindell(t *testing.T) {
 	assert.Nil(t, err)
 
 	input3 := ffi.Round3Input{
+		Curve:    ffi.CurveSecp256k1,
 		PlainSig: rst.String(),
 		R1Rst:    rst1,
 		R2Rst:    rst2,
 	}
 
-	rst3 := ffi.Round3(input3)
-	assert.NotNil(t, rst3)
+	rst3, err := ffi.Round3(input3)
+	assert.NoError(t, err)
 
 	pub_x := `112798640068440206981992607966444350325556905801745747125851303007560154325621`
 	pub_y := `106981805274534110405946749712747093099033643902510644172260461166287121657267`
@@ -148,6 +155,9 @@ func TestLindell(t *testing.T) {
 
 func TestLindellWithTssData(t *testing.T) {
 	keys, signPIDs, err := signing.LoadKeygenTestFixtures(2)
+	if errors.Is(err, signing.ErrKeygenFixturesMissing) {
+		t.Skip(err)
+	}
 	assert.NoError(t, err, "should load keygen fixtures")
 	assert.Equal(t, 2, len(keys))
 	assert.Equal(t, 2, len(signPIDs))
@@ -157,8 +167,8 @@ func TestLindellWithTssData(t *testing.T) {
 	msg := big.NewInt(42)
 
 	// round1
-	rst1 := ffi.Round1()
-	assert.NotNil(t, rst1)
+	rst1, err := ffi.Round1(ffi.Round1Input{Curve: ffi.CurveSecp256k1})
+	assert.NoError(t, err)
 
 	secretShare1 := signing.PrepareForSigning(tss.S256(), signPIDs[0].Index, len(key1.Ks), key1.Xi, key1.Ks)
 	encryptedShare, _, err := key1.PaillierSK.EncryptAndReturnRandomness(secretShare1)
@@ -174,15 +184,16 @@ func TestLindellWithTssData(t *testing.T) {
 	secretShare2 := signing.PrepareForSigning(tss.S256(), signPIDs[1].Index, len(key2.Ks), key2.Xi, key2.Ks)
 
 	input2 := ffi.Round2Input{
+		Curve:          ffi.CurveSecp256k1,
 		PaillierN:      new(big.Int).SetBytes(paillierPubKeyN.Bytes()).String(),
 		EncryptedShare: new(big.Int).SetBytes(encryptedShare.Bytes()).String(),
 		EcKeyPairParty2: ffi.EphEcKeyPair{
 			PublicShare: ffi.Point{
-				Curve: "secp256k1",
+				Curve: ffi.CurveSecp256k1,
 				Point: ffi.Bytes2Uint(pubShare),
 			},
 			SecretShare: ffi.Scalar{
-				Curve:  "secp256k1",
+				Curve:  ffi.CurveSecp256k1,
 				Scalar: ffi.Bytes2Uint(secretShare2.Bytes()),
 			},
 		},
@@ -190,8 +201,8 @@ func TestLindellWithTssData(t *testing.T) {
 		EphPartyOneFirstMessage: rst1.EphPartyOneFirstMessage,
 	}
 
-	rst2 := ffi.Round2(input2)
-	assert.NotNil(t, rst2)
+	rst2, err := ffi.Round2(input2)
+	assert.NoError(t, err)
 
 	// round3
 	partialSig := ffi.Str2BigInt(rst2.PartialSig.C3)
@@ -199,13 +210,14 @@ func TestLindellWithTssData(t *testing.T) {
 	assert.Nil(t, err)
 
 	input3 := ffi.Round3Input{
+		Curve:    ffi.CurveSecp256k1,
 		PlainSig: rst.String(),
 		R1Rst:    rst1,
 		R2Rst:    rst2,
 	}
 
-	rst3 := ffi.Round3(input3)
-	assert.NotNil(t, rst3)
+	rst3, err := ffi.Round3(input3)
+	assert.NoError(t, err)
 
 	// verify
 	pk := ecdsa.PublicKey{
@@ -224,6 +236,58 @@ func TestLindellWithTssData(t *testing.T) {
 	assert.True(t, ok, "signature verification failed")
 }
 
+// A peer's proof or commitment opening that is well formed but does not verify fails the round with an error rather
+// than taking the process down
+func TestRoundsRejectInvalidProofs(t *testing.T) {
+	keys, _, err := signing.LoadKeygenTestFixtures(2)
+	if errors.Is(err, signing.ErrKeygenFixturesMissing) {
+		t.Skip(err)
+	}
+	assert.NoError(t, err, "should load keygen fixtures")
+	ec := tss.S256()
+	sk := keys[0].PaillierSK
+
+	rst1, err := ffi.Round1(ffi.Round1Input{Curve: ffi.CurveSecp256k1})
+	assert.NoError(t, err)
+	other1, err := ffi.Round1(ffi.Round1Input{Curve: ffi.CurveSecp256k1})
+	assert.NoError(t, err)
+
+	encryptedShare, err := sk.Encrypt(common.GetRandomPositiveInt(ec.Params().N))
+	assert.NoError(t, err)
+	x2 := common.GetRandomPositiveInt(ec.Params().N)
+	publicShare, err := ffi.NewPoint(crypto.ScalarBaseMult(ec, x2))
+	assert.NoError(t, err)
+	secretShare, err := ffi.NewScalar(ec, x2)
+	assert.NoError(t, err)
+	input2 := ffi.Round2Input{
+		Curve:                   ffi.CurveSecp256k1,
+		PaillierN:               sk.N.String(),
+		EncryptedShare:          encryptedShare.String(),
+		EcKeyPairParty2:         ffi.EphEcKeyPair{PublicShare: publicShare, SecretShare: secretShare},
+		Message:                 "42",
+		EphPartyOneFirstMessage: rst1.EphPartyOneFirstMessage,
+	}
+
+	// round 2 with the server's proof of another ephemeral share
+	forged2 := input2
+	forged2.EphPartyOneFirstMessage.DLogProof = other1.EphPartyOneFirstMessage.DLogProof
+	_, err = ffi.Round2(forged2)
+	assert.ErrorContains(t, err, "party1 DLog proof failed")
+
+	rst2, err := ffi.Round2(input2)
+	assert.NoError(t, err)
+	other2, err := ffi.Round2(input2)
+	assert.NoError(t, err)
+	plain, err := sk.Decrypt(ffi.Str2BigInt(rst2.PartialSig.C3))
+	assert.NoError(t, err)
+
+	// round 3 with the client's opening of commitments it did not send
+	forged3 := ffi.Round3Input{Curve: ffi.CurveSecp256k1, PlainSig: plain.String(), R1Rst: rst1, R2Rst: rst2}
+	forged3.R2Rst.EphPartyTwoSecondMessage = other2.EphPartyTwoSecondMessage
+	_, err = ffi.Round3(forged3)
+	assert.ErrorContains(t, err, "failed to verify commitments and DLog proof")
+}
+
 func GenerateKeyPair(p, q *big.Int) (privateKey *paillier.PrivateKey) {
 	one := big.NewInt(1)
 
@@ -0,0 +1,109 @@
+// Package logging builds the zap loggers of the protocol packages. Every logger it hands out redacts secrets by
+// type: a field holding a secret, or a value with a secret anywhere inside it, is rendered as "[REDACTED]" whatever
+// the encoder. Secrets are the types implementing Secret, such as lindellcore's scalars, and the private parts of
+// tss-lib's key data, see IsSecret.
+package logging
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field keys of the entries logged by a session
+const (
+	KeySession = "session"
+	KeyParty   = "party"
+	KeyRole    = "role"
+	KeyRound   = "round"
+)
+
+// Config selects the level, format and destination of a logger
+type Config struct {
+	// Level is debug, info, warn or error; empty means warn
+	Level string
+	// Encoding is json or console; empty means json
+	Encoding string
+	// OutputPaths are files or stdout/stderr; empty means stderr
+	OutputPaths []string
+}
+
+var (
+	defaultMtx    sync.RWMutex
+	defaultLogger *zap.Logger
+)
+
+// New builds a redacting logger from cfg
+func New(cfg Config) (*zap.Logger, error) {
+	level := zap.NewAtomicLevelAt(zapcore.WarnLevel)
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("logging: %v", err)
+		}
+	}
+	zc := zap.NewProductionConfig()
+	zc.Level = level
+	zc.Sampling = nil
+	// a failed session is an error of the caller's, not of the code that logs it
+	zc.DisableStacktrace = true
+	if cfg.Encoding != "" {
+		zc.Encoding = cfg.Encoding
+	}
+	if cfg.Encoding == "console" {
+		zc.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	zc.OutputPaths = []string{"stderr"}
+	if len(cfg.OutputPaths) > 0 {
+		zc.OutputPaths = cfg.OutputPaths
+	}
+	logger, err := zc.Build()
+	if err != nil {
+		return nil, fmt.Errorf("logging: %v", err)
+	}
+	return Redacting(logger), nil
+}
+
+// Redacting wraps logger so it never renders a secret. Loggers handed to the protocol packages are wrapped anyway.
+func Redacting(logger *zap.Logger) *zap.Logger {
+	if logger == nil {
+		return nil
+	}
+	if _, ok := logger.Core().(*redactingCore); ok {
+		return logger
+	}
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactingCore{Core: core}
+	}))
+}
+
+// Default is the logger of sessions that were not given one, warnings and errors to stderr unless SetDefault
+// replaced it
+func Default() *zap.Logger {
+	defaultMtx.RLock()
+	logger := defaultLogger
+	defaultMtx.RUnlock()
+	if logger != nil {
+		return logger
+	}
+	defaultMtx.Lock()
+	defer defaultMtx.Unlock()
+	if defaultLogger == nil {
+		var err error
+		if defaultLogger, err = New(Config{}); err != nil {
+			defaultLogger = zap.NewNop()
+		}
+	}
+	return defaultLogger
+}
+
+// SetDefault replaces the default logger; nil silences it
+func SetDefault(logger *zap.Logger) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	defaultMtx.Lock()
+	defer defaultMtx.Unlock()
+	defaultLogger = Redacting(logger)
+}
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-rust/lindell/ffi"
+
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// the digits of every secret below
+const secretDigits = "987654321"
+
+func secret() *big.Int {
+	n, _ := new(big.Int).SetString(secretDigits+secretDigits, 10)
+	return n
+}
+
+func newBufferLogger(encoding string) (*zap.Logger, *bytes.Buffer) {
+	cfg := zap.NewProductionEncoderConfig()
+	enc := zapcore.NewJSONEncoder(cfg)
+	if encoding == "console" {
+		enc = zapcore.NewConsoleEncoder(cfg)
+	}
+	var buf bytes.Buffer
+	return Redacting(zap.New(zapcore.NewCore(enc, zapcore.AddSync(&buf), zapcore.DebugLevel))), &buf
+}
+
+type holder struct {
+	Name  string
+	Pairs []ffi.EcKeyPair
+	ByID  map[string]*ffi.Scalar
+	Any   interface{}
+	Key   *paillier.PrivateKey `json:"paillier_key"`
+}
+
+func TestSecretsAreRedacted(t *testing.T) {
+	scalar := ffi.Scalar{Curve: ffi.CurveSecp256k1, Scalar: []uint{987, 654, 321}}
+	pair := ffi.EcKeyPair{PublicShare: ffi.Point{Curve: ffi.CurveSecp256k1, Point: []uint{2, 7}}, SecretShare: scalar}
+	eph := ffi.EphEcKeyPair{PublicShare: pair.PublicShare, SecretShare: scalar}
+	decryptionKey := ffi.DecryptionKey{P: secretDigits, Q: secretDigits}
+	paillierSK := &paillier.PrivateKey{PublicKey: paillier.PublicKey{N: big.NewInt(77)}, LambdaN: secret(), PhiN: secret()}
+	var save keygen.LocalPartySaveData
+	save.Xi, save.ShareID, save.PaillierSK = secret(), big.NewInt(1), paillierSK
+	save.Ks = []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+	fields := []zap.Field{
+		zap.Any("scalar", scalar),
+		zap.Any("scalar_ptr", &scalar),
+		zap.Reflect("key_pair", pair),
+		zap.Any("eph_key_pair", &eph),
+		zap.Any("decryption_key", decryptionKey),
+		zap.Any("party1_private", ffi.Party1Private{X1: scalar, PaillierPriv: decryptionKey, CKeyRandomness: "5"}),
+		zap.Any("round1", ffi.Round1Result{EphEcKeyPairParty1: eph}),
+		zap.Any("paillier_sk", paillierSK),
+		zap.Any("save_data", save),
+		zap.Any("holder", holder{
+			Name:  "nested",
+			Pairs: []ffi.EcKeyPair{pair, pair},
+			ByID:  map[string]*ffi.Scalar{"a": &scalar, "b": nil},
+			Any:   []interface{}{decryptionKey, save.LocalSecrets},
+			Key:   paillierSK,
+		}),
+	}
+	for _, encoding := range []string{"json", "console"} {
+		logger, buf := newBufferLogger(encoding)
+		logger.With(zap.Any("with", pair)).Info("secrets", fields...)
+		out := buf.String()
+		assert.NotContains(t, out, secretDigits, encoding)
+		assert.NotContains(t, out, "987", encoding)
+		assert.Contains(t, out, Redacted, encoding)
+		// what is public is still logged
+		assert.Contains(t, out, `"public_share":{"curve":"secp256k1","point":[2,7]}`, encoding)
+		assert.Contains(t, out, "nested", encoding)
+	}
+
+	logger, buf := newBufferLogger("json")
+	logger.Info("secrets", fields...)
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, Redacted, entry["scalar"])
+	assert.Equal(t, Redacted, entry["decryption_key"])
+	assert.Equal(t, Redacted, entry["paillier_sk"])
+	assert.Equal(t, Redacted, entry["key_pair"].(map[string]interface{})["secret_share"])
+	assert.Equal(t, Redacted, entry["holder"].(map[string]interface{})["paillier_key"])
+	assert.Equal(t, []interface{}{1.0, 2.0}, entry["save_data"].(map[string]interface{})["Ks"])
+}
+
+func TestPublicValuesAreUntouched(t *testing.T) {
+	point := ffi.Point{Curve: ffi.CurveSecp256k1, Point: []uint{2, 7}}
+	field := zap.Any("point", point)
+	redacted, changed := redactField(field)
+	assert.False(t, changed)
+	assert.Equal(t, field, redacted)
+	assert.False(t, IsSecret(point))
+	assert.True(t, IsSecret(&ffi.Scalar{}))
+	assert.True(t, IsSecret(keygen.LocalPreParams{}))
+	assert.False(t, IsSecret(nil))
+}
+
+func TestLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	logger, err := New(Config{Level: "info", OutputPaths: []string{path}})
+	assert.NoError(t, err)
+	logger.Debug("hidden")
+	logger.Info("shown", zap.Any("scalar", ffi.Scalar{Scalar: []uint{987}}))
+	assert.NoError(t, logger.Sync())
+	bz, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(bz), "\n"))
+	assert.Contains(t, string(bz), `"scalar":"[REDACTED]"`)
+
+	_, err = New(Config{Level: "loud"})
+	assert.Error(t, err)
+}
+
+func TestRedactingIsIdempotent(t *testing.T) {
+	logger, _ := newBufferLogger("json")
+	assert.Same(t, logger, Redacting(logger))
+	assert.Nil(t, Redacting(nil))
+
+	SetDefault(nil)
+	assert.NotNil(t, Default())
+	SetDefault(logger)
+	assert.Same(t, logger, Default())
+}
@@ -0,0 +1,213 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted is logged in place of a secret
+const Redacted = "[REDACTED]"
+
+// maxDepth bounds the walk through nested values
+const maxDepth = 32
+
+// Secret is implemented by types whose values must never be logged. The method does nothing, it only marks the type.
+type Secret interface {
+	RedactFromLogs()
+}
+
+var (
+	secretIface = reflect.TypeOf((*Secret)(nil)).Elem()
+
+	// secrets of tss-lib, which cannot implement Secret
+	foreignSecrets = map[reflect.Type]bool{
+		reflect.TypeOf(paillier.PrivateKey{}):   true,
+		reflect.TypeOf(keygen.LocalPreParams{}): true,
+		reflect.TypeOf(keygen.LocalSecrets{}):   true,
+	}
+
+	// whether a type is or may hold a secret, by type
+	containsCache sync.Map
+)
+
+// IsSecret reports whether v is of a secret type, or a pointer to one
+func IsSecret(v interface{}) bool {
+	return v != nil && isSecretType(reflect.TypeOf(v))
+}
+
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, redactFields(fields))
+}
+
+// ----- //
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	out := fields
+	for i, f := range fields {
+		g, changed := redactField(f)
+		if !changed {
+			continue
+		}
+		if &out[0] == &fields[0] {
+			// never modify the caller's slice
+			out = append([]zapcore.Field(nil), fields...)
+		}
+		out[i] = g
+	}
+	return out
+}
+
+func redactField(f zapcore.Field) (zapcore.Field, bool) {
+	switch f.Type {
+	case zapcore.ReflectType, zapcore.StringerType, zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+	default:
+		return f, false
+	}
+	if f.Interface == nil {
+		return f, false
+	}
+	if IsSecret(f.Interface) {
+		return zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: Redacted}, true
+	}
+	// the others render themselves; a reflected value is walked for secrets inside it
+	if f.Type != zapcore.ReflectType {
+		return f, false
+	}
+	v := reflect.ValueOf(f.Interface)
+	if !containsSecret(v.Type()) {
+		return f, false
+	}
+	f.Interface = redactValue(v, 0)
+	return f, true
+}
+
+func isSecretType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		if t.Implements(secretIface) {
+			return true
+		}
+		t = t.Elem()
+	}
+	return foreignSecrets[t] || t.Implements(secretIface) || reflect.PtrTo(t).Implements(secretIface)
+}
+
+// containsSecret reports whether a value of type t is, or may hold, a secret. Interfaces may hold anything.
+func containsSecret(t reflect.Type) bool {
+	if cached, ok := containsCache.Load(t); ok {
+		return cached.(bool)
+	}
+	// a recursive type is resolved by its other fields
+	containsCache.Store(t, false)
+	var contains bool
+	switch {
+	case isSecretType(t):
+		contains = true
+	default:
+		switch t.Kind() {
+		case reflect.Interface:
+			contains = true
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			contains = containsSecret(t.Elem())
+		case reflect.Map:
+			contains = containsSecret(t.Key()) || containsSecret(t.Elem())
+		case reflect.Struct:
+			for i := 0; i < t.NumField() && !contains; i++ {
+				if f := t.Field(i); f.IsExported() {
+					contains = containsSecret(f.Type)
+				}
+			}
+		}
+	}
+	containsCache.Store(t, contains)
+	return contains
+}
+
+// redactValue rebuilds v for a reflecting encoder with every secret in it replaced by Redacted. Structs become maps of
+// their exported fields, named as encoding/json names them.
+func redactValue(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxDepth {
+		return "[...]"
+	}
+	t := v.Type()
+	if isSecretType(t) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil
+		}
+		return Redacted
+	}
+	if !containsSecret(t) {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i), depth+1)
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(redactValue(iter.Key(), depth+1))] = redactValue(iter.Value(), depth+1)
+		}
+		return out
+	case reflect.Struct:
+		out := make(map[string]interface{}, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := f.Name
+			if tag, ok := f.Tag.Lookup("json"); ok {
+				tagName := strings.Split(tag, ",")[0]
+				if tagName == "-" {
+					continue
+				}
+				if tagName != "" {
+					name = tagName
+				}
+			}
+			out[name] = redactValue(v.Field(i), depth+1)
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
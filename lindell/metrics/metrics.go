@@ -0,0 +1,52 @@
+// Package metrics instruments the signing protocol. The signing package reports through the Metrics interface;
+// Prometheus is an implementation that renders the Prometheus text exposition format without any client library.
+package metrics
+
+import "time"
+
+// Calls timed by ObserveCall
+const (
+	CallFFIRound1       = "ffi_round1"
+	CallFFIRound2       = "ffi_round2"
+	CallFFIRound3       = "ffi_round3"
+	CallPaillierEncrypt = "paillier_encrypt"
+	CallPaillierDecrypt = "paillier_decrypt"
+)
+
+// Reasons a session fails, as passed to SessionFailed
+const (
+	// ReasonPeer is a protocol error blamed on the peer, e.g. a proof that does not verify
+	ReasonPeer = "peer"
+	// ReasonLocal is a protocol error with no culprit, e.g. invalid input or a failed local computation
+	ReasonLocal = "local"
+	// ReasonTimeout and ReasonCanceled are sessions given up by their driver
+	ReasonTimeout  = "timeout"
+	ReasonCanceled = "canceled"
+	// ReasonTransport is a session whose connection to the peer failed
+	ReasonTransport = "transport"
+)
+
+// Metrics receives the measurements of signing sessions. Implementations must be safe for concurrent use.
+// role is "server" or "client".
+type Metrics interface {
+	// ObserveRound records the time a round spent computing in its Start
+	ObserveRound(role string, round int, d time.Duration)
+	// ObserveCall records the duration of a cgo call or a Paillier operation, named by one of the Call constants
+	ObserveCall(call string, d time.Duration)
+
+	SessionStarted(role string)
+	SessionSucceeded(role string)
+	// SessionFailed is reported once per failed session; round is the round it failed in, 0 if it never started
+	SessionFailed(role string, round int, reason string)
+}
+
+// Nop discards all measurements
+type Nop struct{}
+
+var _ Metrics = Nop{}
+
+func (Nop) ObserveRound(string, int, time.Duration) {}
+func (Nop) ObserveCall(string, time.Duration)       {}
+func (Nop) SessionStarted(string)                   {}
+func (Nop) SessionSucceeded(string)                 {}
+func (Nop) SessionFailed(string, int, string)       {}
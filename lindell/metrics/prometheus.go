@@ -0,0 +1,245 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType of the text exposition format written by Prometheus
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	// RoundBuckets are the histogram buckets of round durations, in seconds
+	RoundBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// CallBuckets are the histogram buckets of call durations, in seconds
+	CallBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+)
+
+// Prometheus keeps the measurements in memory and renders them in the Prometheus text exposition format. It serves
+// them over HTTP for a scraper, e.g. http.Handle("/metrics", p).
+type Prometheus struct {
+	mtx  sync.Mutex
+	vecs []*vec
+
+	roundDuration, callDuration *vec
+	started, succeeded, failed  *vec
+	inFlight                    *vec
+}
+
+var _ Metrics = (*Prometheus)(nil)
+var _ http.Handler = (*Prometheus)(nil)
+
+type kind string
+
+const (
+	kindCounter   kind = "counter"
+	kindGauge     kind = "gauge"
+	kindHistogram kind = "histogram"
+)
+
+type vec struct {
+	name, help string
+	kind       kind
+	labels     []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+type series struct {
+	values []string
+	value  float64
+	// histograms
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func NewPrometheus() *Prometheus {
+	p := new(Prometheus)
+	p.roundDuration = p.newVec("lindell_signing_round_duration_seconds", "Time a signing round spent computing.",
+		kindHistogram, RoundBuckets, "role", "round")
+	p.callDuration = p.newVec("lindell_signing_call_duration_seconds", "Duration of cgo calls and Paillier operations.",
+		kindHistogram, CallBuckets, "call")
+	p.started = p.newVec("lindell_signing_sessions_started_total", "Signing sessions started.",
+		kindCounter, nil, "role")
+	p.succeeded = p.newVec("lindell_signing_sessions_succeeded_total", "Signing sessions that completed.",
+		kindCounter, nil, "role")
+	p.failed = p.newVec("lindell_signing_sessions_failed_total", "Signing sessions that failed, by round and reason.",
+		kindCounter, nil, "role", "round", "reason")
+	p.inFlight = p.newVec("lindell_signing_sessions_in_flight", "Signing sessions started and not yet finished.",
+		kindGauge, nil, "role")
+	return p
+}
+
+func (p *Prometheus) ObserveRound(role string, round int, d time.Duration) {
+	p.observe(p.roundDuration, d.Seconds(), role, strconv.Itoa(round))
+}
+
+func (p *Prometheus) ObserveCall(call string, d time.Duration) {
+	p.observe(p.callDuration, d.Seconds(), call)
+}
+
+func (p *Prometheus) SessionStarted(role string) {
+	p.add(p.started, 1, role)
+	p.add(p.inFlight, 1, role)
+}
+
+func (p *Prometheus) SessionSucceeded(role string) {
+	p.add(p.succeeded, 1, role)
+	p.add(p.inFlight, -1, role)
+}
+
+func (p *Prometheus) SessionFailed(role string, round int, reason string) {
+	p.add(p.failed, 1, role, strconv.Itoa(round), reason)
+	p.add(p.inFlight, -1, role)
+}
+
+// WriteTo renders all metrics in the text exposition format
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	p.mtx.Lock()
+	for _, v := range p.vecs {
+		v.write(cw)
+	}
+	p.mtx.Unlock()
+	if err := bw.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	p.WriteTo(w)
+}
+
+// ----- //
+
+func (p *Prometheus) newVec(name, help string, k kind, buckets []float64, labels ...string) *vec {
+	v := &vec{name: name, help: help, kind: k, labels: labels, buckets: buckets, series: make(map[string]*series)}
+	p.vecs = append(p.vecs, v)
+	return v
+}
+
+func (p *Prometheus) add(v *vec, delta float64, values ...string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	v.get(values).value += delta
+}
+
+func (p *Prometheus) observe(v *vec, x float64, values ...string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	s := v.get(values)
+	for i, le := range v.buckets {
+		if x <= le {
+			s.counts[i]++
+		}
+	}
+	s.sum += x
+	s.count++
+}
+
+func (v *vec) get(values []string) *series {
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		if v.kind == kindHistogram {
+			s.counts = make([]uint64, len(v.buckets))
+		}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *vec) write(w *countingWriter) {
+	if len(v.series) == 0 {
+		return
+	}
+	w.print("# HELP ", v.name, " ", v.help, "\n")
+	w.print("# TYPE ", v.name, " ", string(v.kind), "\n")
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := v.series[key]
+		if v.kind != kindHistogram {
+			w.print(v.name, v.labelSet(s.values, "", ""), " ", formatFloat(s.value), "\n")
+			continue
+		}
+		// bucket counts are cumulative by definition of le
+		for i, le := range v.buckets {
+			w.print(v.name, "_bucket", v.labelSet(s.values, "le", formatFloat(le)), " ",
+				strconv.FormatUint(s.counts[i], 10), "\n")
+		}
+		w.print(v.name, "_bucket", v.labelSet(s.values, "le", "+Inf"), " ", strconv.FormatUint(s.count, 10), "\n")
+		w.print(v.name, "_sum", v.labelSet(s.values, "", ""), " ", formatFloat(s.sum), "\n")
+		w.print(v.name, "_count", v.labelSet(s.values, "", ""), " ", strconv.FormatUint(s.count, 10), "\n")
+	}
+}
+
+func (v *vec) labelSet(values []string, extraName, extraValue string) string {
+	var b strings.Builder
+	pairs := 0
+	pair := func(name, value string) {
+		if pairs > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(value))
+		b.WriteByte('"')
+		pairs++
+	}
+	for i, name := range v.labels {
+		pair(name, values[i])
+	}
+	if extraName != "" {
+		pair(extraName, extraValue)
+	}
+	if pairs == 0 {
+		return ""
+	}
+	return "{" + b.String() + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) print(parts ...string) {
+	for _, s := range parts {
+		if w.err != nil {
+			return
+		}
+		n, err := io.WriteString(w.w, s)
+		w.n += int64(n)
+		w.err = err
+	}
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, p *Prometheus) string {
+	var b strings.Builder
+	n, err := p.WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(b.Len()), n)
+	return b.String()
+}
+
+func TestPrometheusExposition(t *testing.T) {
+	p := NewPrometheus()
+	assert.Empty(t, render(t, p), "nothing is rendered before the first measurement")
+
+	p.SessionStarted("server")
+	p.SessionStarted("server")
+	p.SessionStarted("client")
+	p.SessionSucceeded("server")
+	p.SessionFailed("client", 2, ReasonPeer)
+	p.ObserveRound("server", 1, 3*time.Millisecond)
+	p.ObserveRound("server", 1, 2*time.Second)
+	p.ObserveCall(CallPaillierDecrypt, 300*time.Microsecond)
+
+	out := render(t, p)
+	for _, line := range []string{
+		"# HELP lindell_signing_sessions_started_total Signing sessions started.",
+		"# TYPE lindell_signing_sessions_started_total counter",
+		`lindell_signing_sessions_started_total{role="client"} 1`,
+		`lindell_signing_sessions_started_total{role="server"} 2`,
+		`lindell_signing_sessions_succeeded_total{role="server"} 1`,
+		`lindell_signing_sessions_failed_total{role="client",round="2",reason="peer"} 1`,
+		"# TYPE lindell_signing_sessions_in_flight gauge",
+		`lindell_signing_sessions_in_flight{role="client"} 0`,
+		`lindell_signing_sessions_in_flight{role="server"} 1`,
+		"# TYPE lindell_signing_round_duration_seconds histogram",
+		`lindell_signing_round_duration_seconds_bucket{role="server",round="1",le="0.001"} 0`,
+		`lindell_signing_round_duration_seconds_bucket{role="server",round="1",le="0.005"} 1`,
+		`lindell_signing_round_duration_seconds_bucket{role="server",round="1",le="2.5"} 2`,
+		`lindell_signing_round_duration_seconds_bucket{role="server",round="1",le="+Inf"} 2`,
+		`lindell_signing_round_duration_seconds_sum{role="server",round="1"} 2.003`,
+		`lindell_signing_round_duration_seconds_count{role="server",round="1"} 2`,
+		`lindell_signing_call_duration_seconds_bucket{call="paillier_decrypt",le="0.0005"} 1`,
+		`lindell_signing_call_duration_seconds_count{call="paillier_decrypt"} 1`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+	assert.NotContains(t, out, "client\",round=\"1\"", "no series for label values never observed")
+}
+
+func TestPrometheusEscapesLabels(t *testing.T) {
+	p := NewPrometheus()
+	p.ObserveCall("a\"b\\c\nd", time.Millisecond)
+	assert.Contains(t, render(t, p), `call="a\"b\\c\nd"`)
+}
+
+func TestPrometheusServeHTTP(t *testing.T) {
+	p := NewPrometheus()
+	p.SessionStarted("server")
+	srv := httptest.NewServer(p)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `lindell_signing_sessions_started_total{role="server"} 1`)
+
+	resp, err = http.Post(srv.URL, "text/plain", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
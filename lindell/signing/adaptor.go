@@ -0,0 +1,126 @@
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"go-rust/lindell/zkp"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+// PreSignature is the server's output of an adaptor signing session under the adaptor point Y = y·G. The nonce is
+// RPoint = k·Y rather than k·G, so (R, SHat) only becomes a valid ECDSA signature once SHat is adapted with y, and
+// the published signature in turn reveals y to whoever holds the pre-signature.
+type PreSignature struct {
+	M *big.Int
+	// R is x(RPoint) mod q, the r of the adapted signature
+	R    *big.Int
+	SHat *big.Int
+
+	// RHat = k·G and RPoint = k·Y for the joint nonce k = k1·k2
+	RHat, RPoint *crypto.ECPoint
+	// R1 = k1·G and R1Y = k1·Y are the server's nonce shares
+	R1, R1Y *crypto.ECPoint
+
+	// R1Proof shows log_G(R1) = log_Y(R1Y), RProof shows log_R1(RHat) = log_R1Y(RPoint)
+	R1Proof, RProof *zkp.DLEQProof
+}
+
+// VerifyPreSignature checks that pre is a pre-signature of m under pub that adapts with the discrete log of
+// adaptorPoint: both nonce proofs hold, r = x(RPoint) mod q and SHat^-1·(m·G + r·pub) = RHat.
+func VerifyPreSignature(pub *crypto.ECPoint, m *big.Int, adaptorPoint *crypto.ECPoint, pre *PreSignature) error {
+	if pub == nil || m == nil || adaptorPoint == nil || !pre.ValidateBasic() {
+		return errors.New("pre-signature verification received nil value(s)")
+	}
+	ec := pub.Curve()
+	q := ec.Params().N
+	if pre.M.Cmp(m) != 0 {
+		return errors.New("pre-signature is over a different message")
+	}
+	if pre.R.Sign() <= 0 || pre.R.Cmp(q) >= 0 || pre.SHat.Sign() <= 0 || pre.SHat.Cmp(q) >= 0 {
+		return errors.New("pre-signature values are out of range")
+	}
+	G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+	if !pre.R1Proof.Verify(G, pre.R1, adaptorPoint, pre.R1Y) {
+		return errors.New("failed to verify the server's adaptor nonce share")
+	}
+	if !pre.RProof.Verify(pre.R1, pre.RHat, pre.R1Y, pre.RPoint) {
+		return errors.New("failed to verify the adaptor nonce")
+	}
+	if new(big.Int).Mod(pre.RPoint.X(), q).Cmp(pre.R) != 0 {
+		return errors.New("r does not match the adaptor nonce")
+	}
+
+	modQ := common.ModInt(q)
+	sInv := modQ.ModInverse(pre.SHat)
+	u1 := crypto.ScalarBaseMult(ec, modQ.Mul(m, sInv))
+	u2 := pub.ScalarMult(modQ.Mul(pre.R, sInv))
+	RHat, err := u1.Add(u2)
+	if err != nil || !RHat.Equals(pre.RHat) {
+		return errors.New("pre-signature verification failed")
+	}
+	return nil
+}
+
+// AdaptPreSignature completes pre into an ECDSA signature with the adaptor secret y: s = SHat·y^-1, normalised to the
+// lower half of the order like the signatures of a plain session.
+func AdaptPreSignature(pre *PreSignature, y *big.Int) (*common.SignatureData, error) {
+	if !pre.ValidateBasic() || y == nil {
+		return nil, errors.New("pre-signature adaption received nil value(s)")
+	}
+	ec := pre.RPoint.Curve()
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	yInv := modQ.ModInverse(y)
+	if yInv == nil || y.Sign() == 0 {
+		return nil, errors.New("adaptor secret is not invertible")
+	}
+	s := modQ.Mul(pre.SHat, yInv)
+
+	// the nonce of the adapted signature is RPoint
+	s, recid := lowS(q, pre.RPoint, s)
+
+	bitSizeInBytes := ec.Params().BitSize / 8
+	data := &common.SignatureData{
+		R:                 padToLengthBytesInPlace(pre.R.Bytes(), bitSizeInBytes),
+		S:                 padToLengthBytesInPlace(s.Bytes(), bitSizeInBytes),
+		SignatureRecovery: []byte{byte(recid)},
+		M:                 pre.M.Bytes(),
+	}
+	data.Signature = append(append([]byte{}, data.R...), data.S...)
+	return data, nil
+}
+
+// ExtractAdaptorSecret recovers y = SHat·s^-1 from a published signature that was adapted from pre. Low-s
+// normalisation may have negated s, so both candidates are checked against adaptorPoint.
+func ExtractAdaptorSecret(pre *PreSignature, adaptorPoint *crypto.ECPoint, sig *common.SignatureData) (*big.Int, error) {
+	if !pre.ValidateBasic() || adaptorPoint == nil || sig == nil {
+		return nil, errors.New("adaptor secret extraction received nil value(s)")
+	}
+	ec := adaptorPoint.Curve()
+	q := ec.Params().N
+	if new(big.Int).SetBytes(sig.GetR()).Cmp(pre.R) != 0 {
+		return nil, errors.New("signature was not adapted from the pre-signature")
+	}
+	s := new(big.Int).SetBytes(sig.GetS())
+	if s.Sign() == 0 || s.Cmp(q) >= 0 {
+		return nil, errors.New("signature s is out of range")
+	}
+
+	modQ := common.ModInt(q)
+	y := modQ.Mul(pre.SHat, modQ.ModInverse(s))
+	for _, candidate := range []*big.Int{y, modQ.Sub(big.NewInt(0), y)} {
+		if crypto.ScalarBaseMult(ec, candidate).Equals(adaptorPoint) {
+			return candidate, nil
+		}
+	}
+	return nil, errors.New("signature does not reveal the discrete log of the adaptor point")
+}
+
+func (pre *PreSignature) ValidateBasic() bool {
+	return pre != nil && pre.M != nil && pre.R != nil && pre.SHat != nil &&
+		pre.RHat != nil && pre.RPoint != nil && pre.R1 != nil && pre.R1Y != nil &&
+		pre.R1Proof != nil && pre.RProof != nil
+}
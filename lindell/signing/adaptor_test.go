@@ -0,0 +1,162 @@
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/zkp"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPreSignature runs the adaptor nonce exchange and the signing equation in the clear
+func newTestPreSignature(t *testing.T, ec elliptic.Curve, x, y, m *big.Int) *PreSignature {
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+	Y := crypto.ScalarBaseMult(ec, y)
+
+	k1, k2 := common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q)
+	R1, R1Y := G.ScalarMult(k1), Y.ScalarMult(k1)
+	RHat, RPoint := R1.ScalarMult(k2), R1Y.ScalarMult(k2)
+	R1Proof, err := zkp.NewDLEQProof(k1, G, R1, Y, R1Y)
+	assert.NoError(t, err)
+	RProof, err := zkp.NewDLEQProof(k2, R1, RHat, R1Y, RPoint)
+	assert.NoError(t, err)
+
+	r := new(big.Int).Mod(RPoint.X(), q)
+	k := modQ.Mul(k1, k2)
+	sHat := modQ.Mul(modQ.ModInverse(k), modQ.Add(m, modQ.Mul(r, x)))
+	return &PreSignature{
+		M: m, R: r, SHat: sHat,
+		RHat: RHat, RPoint: RPoint, R1: R1, R1Y: R1Y,
+		R1Proof: R1Proof, RProof: RProof,
+	}
+}
+
+func TestAdaptorSignature(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), elliptic.P256()} {
+		q := ec.Params().N
+		x, y := common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q)
+		P, Y := crypto.ScalarBaseMult(ec, x), crypto.ScalarBaseMult(ec, y)
+		m := big.NewInt(42)
+
+		pre := newTestPreSignature(t, ec, x, y, m)
+		assert.NoError(t, VerifyPreSignature(P, m, Y, pre))
+
+		// a pre-signature is not a signature
+		pk := ecdsa.PublicKey{Curve: ec, X: P.X(), Y: P.Y()}
+		assert.False(t, ecdsa.Verify(&pk, m.Bytes(), pre.R, pre.SHat), "pre-signature must not verify as a signature")
+
+		sig, err := AdaptPreSignature(pre, y)
+		assert.NoError(t, err)
+		s := new(big.Int).SetBytes(sig.GetS())
+		assert.True(t, ecdsa.Verify(&pk, m.Bytes(), new(big.Int).SetBytes(sig.GetR()), s), "adapted signature must verify")
+		assert.True(t, s.Cmp(new(big.Int).Rsh(q, 1)) <= 0, "adapted s must be low")
+
+		extracted, err := ExtractAdaptorSecret(pre, Y, sig)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, extracted.Cmp(y), "extracted secret must be y")
+	}
+}
+
+func TestAdaptorSignatureRejected(t *testing.T) {
+	ec := tss.S256()
+	q := ec.Params().N
+	x, y := common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q)
+	P, Y := crypto.ScalarBaseMult(ec, x), crypto.ScalarBaseMult(ec, y)
+	m := big.NewInt(42)
+	pre := newTestPreSignature(t, ec, x, y, m)
+
+	otherY := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+	assert.Error(t, VerifyPreSignature(P, m, otherY, pre), "pre-signature must be bound to its adaptor point")
+	assert.Error(t, VerifyPreSignature(P, big.NewInt(43), Y, pre), "pre-signature must be bound to its message")
+	otherP := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+	assert.Error(t, VerifyPreSignature(otherP, m, Y, pre), "pre-signature must be bound to its key")
+
+	// a nonce that is not k·Y breaks the proof
+	forged := *pre
+	forged.RPoint = crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+	forged.R = new(big.Int).Mod(forged.RPoint.X(), q)
+	assert.Error(t, VerifyPreSignature(P, m, Y, &forged))
+
+	// a signature from an unrelated nonce reveals nothing
+	other := newTestPreSignature(t, ec, x, y, m)
+	sig, err := AdaptPreSignature(other, y)
+	assert.NoError(t, err)
+	_, err = ExtractAdaptorSecret(pre, Y, sig)
+	assert.Error(t, err)
+}
+
+// adaptorNonceRound returns the server's round 3 with the ephemeral key k1 and the client's message over k2, whose
+// adaptor nonce is honest
+func adaptorNonceRound(t *testing.T) (*round3, ffi.Round2Result) {
+	ec := tss.S256()
+	q := ec.Params().N
+	G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+	Y := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+
+	k1, k2 := common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q)
+	R1, R1Y, R2 := G.ScalarMult(k1), Y.ScalarMult(k1), G.ScalarMult(k2)
+	RHat, RPoint := R1.ScalarMult(k2), R1Y.ScalarMult(k2)
+	proof, err := zkp.NewDLEQProof(k2, R1, RHat, R1Y, RPoint)
+	assert.NoError(t, err)
+
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params, err := NewLindellSignParametersWithRole(ec, tss.NewPeerContext(pIDs), pIDs[0], 2, 1, RoleServer)
+	assert.NoError(t, err)
+	secretShare, err := ffi.NewScalar(ec, k1)
+	assert.NoError(t, err)
+	temp := &localTempData{round1Rst: &ffi.Round1Result{EphEcKeyPairParty1: ffi.EphEcKeyPair{SecretShare: secretShare}}}
+	temp.r1, temp.r1Y = R1, R1Y
+	round := &round3{&round2{&round1{&base{LindellSignParameters: params, temp: temp}}}}
+
+	var msg2 ffi.Round2Result
+	msg2.EphPartyTwoSecondMessage.CommWitness.PublicShare = testPoint(t, R2)
+	msg2.AdaptorNonce = &ffi.AdaptorNonce{
+		RHat: testPoint(t, RHat),
+		R:    testPoint(t, RPoint),
+		Proof: ffi.DLEqProof{
+			A1: testPoint(t, proof.A1),
+			A2: testPoint(t, proof.A2),
+		},
+	}
+	msg2.AdaptorNonce.Proof.Z, err = ffi.NewScalar(ec, proof.Z)
+	assert.NoError(t, err)
+	return round, msg2
+}
+
+func testPoint(t *testing.T, P *crypto.ECPoint) ffi.Point {
+	p, err := ffi.NewPoint(P)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestAdaptorNonceCheckedBeforeLindellcore(t *testing.T) {
+	round, msg2 := adaptorNonceRound(t)
+	nonce, err := round.verifyAdaptorNonce(msg2)
+	assert.NoError(t, err)
+	assert.NotNil(t, nonce)
+
+	// an r_hat the client chose freely would make lindellcore fail, so it is caught here
+	G := crypto.ScalarBaseMult(tss.S256(), big.NewInt(1))
+	RHat, err := msg2.AdaptorNonce.RHat.ECPoint(tss.S256())
+	assert.NoError(t, err)
+	RHat, err = RHat.Add(G)
+	assert.NoError(t, err)
+	forged := msg2
+	forged.AdaptorNonce = &ffi.AdaptorNonce{RHat: testPoint(t, RHat), R: msg2.AdaptorNonce.R, Proof: msg2.AdaptorNonce.Proof}
+	_, err = round.verifyAdaptorNonce(forged)
+	assert.EqualError(t, err, "adaptor nonce does not match the ephemeral key exchange")
+
+	// an r_hat that matches with a proof over another R does not verify
+	forged.AdaptorNonce = &ffi.AdaptorNonce{RHat: msg2.AdaptorNonce.RHat, R: testPoint(t, G), Proof: msg2.AdaptorNonce.Proof}
+	_, err = round.verifyAdaptorNonce(forged)
+	assert.EqualError(t, err, "failed to verify the adaptor nonce")
+}
@@ -0,0 +1,192 @@
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// The fuzz targets feed peer input to everything that parses it before lindellcore is called; none of them may panic.
+// Run one with e.g. go test -run '^$' -fuzz FuzzUpdateFromBytes ./lindell/signing/
+
+// fuzzRound1Seeds returns valid round 1 messages when the keygen fixtures are available
+func fuzzRound1Seeds(f *testing.F) []*SignRound1Message {
+	var seeds []*SignRound1Message
+	for _, adaptor := range []bool{false, true} {
+		if m, _, err := testRound1Message(adaptor); err == nil {
+			seeds = append(seeds, m)
+		}
+	}
+	if len(seeds) == 0 {
+		f.Log("keygen fixtures are not available, fuzzing from minimal seeds")
+	}
+	return seeds
+}
+
+func fuzzRound2Seed() *SignRound2Message {
+	_, rst2 := testPayloads()
+	return &SignRound2Message{Rst: newRound2ResultPayload(rst2), TraceContext: map[string]string{"mockpfx-ids-traceid": "1"}}
+}
+
+// newFuzzParty returns an unstarted party: messages are validated and stored, but no round runs
+func newFuzzParty(t *testing.T, role Role) *LocalParty {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params, err := NewLindellSignParametersWithRole(tss.S256(), tss.NewPeerContext(pIDs), pIDs[int(role)-1], 2, 1, role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.SetLogger(zap.NewNop())
+	key := keygen.NewLocalPartySaveData(2)
+	key.Ks = []*big.Int{pIDs[0].KeyInt(), pIDs[1].KeyInt()}
+	return NewLocalParty(big.NewInt(42), params, key, nil, nil).(*LocalParty)
+}
+
+func FuzzUpdateFromBytes(f *testing.F) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	var seeds []tss.ParsedMessage
+	for _, m := range fuzzRound1Seeds(f) {
+		meta := tss.MessageRouting{From: pIDs[0], IsBroadcast: true}
+		seeds = append(seeds, tss.NewMessage(meta, m, tss.NewMessageWrapper(meta, m)))
+	}
+	_, rst2 := testPayloads()
+	seeds = append(seeds, NewSignRound2Message(pIDs[1], rst2, nil))
+	for _, msg := range seeds {
+		wire, _, err := msg.WireBytes()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(wire, uint8(0), true)
+		f.Add(wire, uint8(1), false)
+	}
+	f.Add([]byte{}, uint8(0), true)
+
+	f.Fuzz(func(t *testing.T, wire []byte, receiver uint8, fromPeer bool) {
+		p := newFuzzParty(t, Role(receiver%2+1))
+		from := p.PartyID()
+		if fromPeer {
+			from = p.params.Parties().IDs()[1-from.Index]
+		}
+		ok, err := p.UpdateFromBytes(wire, from, true)
+		if ok && err != nil {
+			t.Fatalf("accepted a message with an error: %v", err)
+		}
+	})
+}
+
+func FuzzSignRound1Message(f *testing.F) {
+	for _, m := range fuzzRound1Seeds(f) {
+		bz, err := proto.Marshal(m)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(bz)
+	}
+	f.Add([]byte{})
+
+	ec := tss.S256()
+	f.Fuzz(func(t *testing.T, bz []byte) {
+		m := new(SignRound1Message)
+		if proto.Unmarshal(bz, m) != nil {
+			return
+		}
+		if m.Validate(ec) != nil {
+			return
+		}
+		// a valid message decodes in every way round 2 decodes it
+		if _, err := m.UnmarshalFirstMsg(ec); err != nil {
+			t.Fatalf("first message of a valid message: %v", err)
+		}
+		if _, err := m.UnmarshalPDLwSlackProof(ec); err != nil {
+			t.Fatalf("pdl proof of a valid message: %v", err)
+		}
+		if m.HasPaillierProof() {
+			if _, err := m.UnmarshalPaillierProof(); err != nil {
+				t.Fatalf("paillier proof of a valid message: %v", err)
+			}
+		}
+		if m.HasAdaptorShare() {
+			if _, err := m.UnmarshalAdaptorShare(ec); err != nil {
+				t.Fatalf("adaptor share of a valid message: %v", err)
+			}
+			if _, err := m.UnmarshalAdaptorProof(ec); err != nil {
+				t.Fatalf("adaptor proof of a valid message: %v", err)
+			}
+		}
+	})
+}
+
+func FuzzSignRound2Message(f *testing.F) {
+	bz, err := proto.Marshal(fuzzRound2Seed())
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(bz)
+	f.Add([]byte{})
+
+	ec := tss.S256()
+	N := new(big.Int).Lsh(big.NewInt(1), 2047)
+	N.Add(N, big.NewInt(1))
+	f.Fuzz(func(t *testing.T, bz []byte) {
+		m := new(SignRound2Message)
+		if proto.Unmarshal(bz, m) != nil {
+			return
+		}
+		_ = m.Validate(ec, nil)
+		_ = m.Validate(ec, N)
+	})
+}
+
+func FuzzUnmarshalFirstMsg(f *testing.F) {
+	msg1, _ := testPayloads()
+	bz, err := proto.Marshal(newFirstMsgPayload(msg1))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(bz)
+	f.Add([]byte{})
+
+	ec := tss.S256()
+	f.Fuzz(func(t *testing.T, bz []byte) {
+		m := &SignRound1Message{FirstMsg: new(EphKeyGenFirstMsg)}
+		if proto.Unmarshal(bz, m.FirstMsg) != nil {
+			return
+		}
+		msg1, err := m.UnmarshalFirstMsg(ec)
+		if err != nil {
+			return
+		}
+		// lindellcore is handed exactly what was validated
+		if _, err := msg1.PublicShare.ECPoint(ec); err != nil {
+			t.Fatalf("valid first message with a bad public share: %v", err)
+		}
+	})
+}
+
+func FuzzUnmarshalRst(f *testing.F) {
+	_, rst2 := testPayloads()
+	bz, err := proto.Marshal(newRound2ResultPayload(rst2))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(bz)
+	f.Add([]byte{})
+
+	ec := tss.S256()
+	f.Fuzz(func(t *testing.T, bz []byte) {
+		m := &SignRound2Message{Rst: new(Round2Result)}
+		if proto.Unmarshal(bz, m.Rst) != nil {
+			return
+		}
+		rst2, err := m.UnmarshalRst(ec)
+		if err != nil {
+			return
+		}
+		if _, ok := new(big.Int).SetString(rst2.PartialSig.C3, 10); !ok {
+			t.Fatalf("valid round 2 result with a partial signature of %q", rst2.PartialSig.C3)
+		}
+	})
+}
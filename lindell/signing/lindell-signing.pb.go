@@ -26,9 +26,18 @@ type SignRound1Message struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	N        []byte `protobuf:"bytes,1,opt,name=N,proto3" json:"N,omitempty"`
-	Share    []byte `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
-	FirstMsg []byte `protobuf:"bytes,3,opt,name=firstMsg,proto3" json:"firstMsg,omitempty"`
+	N     []byte `protobuf:"bytes,1,opt,name=N,proto3" json:"N,omitempty"`
+	Share []byte `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	// PDL-with-slack proof that `share` encrypts the discrete log of the server's public share
+	PdlProof [][]byte `protobuf:"bytes,4,rep,name=pdlProof,proto3" json:"pdlProof,omitempty"`
+	// proof that `N` is a well-formed Paillier modulus; verified once per key by the client
+	PaillierProof [][]byte `protobuf:"bytes,5,rep,name=paillierProof,proto3" json:"paillierProof,omitempty"`
+	// adaptor mode only: k1*Y as X, Y and the DLEQ proof that it shares its discrete log with the ephemeral public share
+	AdaptorShare [][]byte `protobuf:"bytes,6,rep,name=adaptorShare,proto3" json:"adaptorShare,omitempty"`
+	AdaptorProof [][]byte `protobuf:"bytes,7,rep,name=adaptorProof,proto3" json:"adaptorProof,omitempty"`
+	// OpenTracing span context of the server's session, so the client's spans join the server's trace
+	TraceContext map[string]string  `protobuf:"bytes,8,rep,name=traceContext,proto3" json:"traceContext,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	FirstMsg     *EphKeyGenFirstMsg `protobuf:"bytes,9,opt,name=firstMsg,proto3" json:"firstMsg,omitempty"`
 }
 
 func (x *SignRound1Message) Reset() {
@@ -77,7 +86,42 @@ func (x *SignRound1Message) GetShare() []byte {
 	return nil
 }
 
-func (x *SignRound1Message) GetFirstMsg() []byte {
+func (x *SignRound1Message) GetPdlProof() [][]byte {
+	if x != nil {
+		return x.PdlProof
+	}
+	return nil
+}
+
+func (x *SignRound1Message) GetPaillierProof() [][]byte {
+	if x != nil {
+		return x.PaillierProof
+	}
+	return nil
+}
+
+func (x *SignRound1Message) GetAdaptorShare() [][]byte {
+	if x != nil {
+		return x.AdaptorShare
+	}
+	return nil
+}
+
+func (x *SignRound1Message) GetAdaptorProof() [][]byte {
+	if x != nil {
+		return x.AdaptorProof
+	}
+	return nil
+}
+
+func (x *SignRound1Message) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
+func (x *SignRound1Message) GetFirstMsg() *EphKeyGenFirstMsg {
 	if x != nil {
 		return x.FirstMsg
 	}
@@ -90,7 +134,9 @@ type SignRound2Message struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Rst []byte `protobuf:"bytes,1,opt,name=rst,proto3" json:"rst,omitempty"`
+	// OpenTracing span context of the client's session
+	TraceContext map[string]string `protobuf:"bytes,2,rep,name=traceContext,proto3" json:"traceContext,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Rst          *Round2Result     `protobuf:"bytes,3,opt,name=rst,proto3" json:"rst,omitempty"`
 }
 
 func (x *SignRound2Message) Reset() {
@@ -125,28 +171,574 @@ func (*SignRound2Message) Descriptor() ([]byte, []int) {
 	return file_lindell_signing_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *SignRound2Message) GetRst() []byte {
+func (x *SignRound2Message) GetTraceContext() map[string]string {
+	if x != nil {
+		return x.TraceContext
+	}
+	return nil
+}
+
+func (x *SignRound2Message) GetRst() *Round2Result {
 	if x != nil {
 		return x.Rst
 	}
 	return nil
 }
 
+// Proof of knowledge of a discrete log, or of equal discrete logs, as lindellcore makes it
+type ECDDHProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	A1 []byte `protobuf:"bytes,1,opt,name=a1,proto3" json:"a1,omitempty"`
+	A2 []byte `protobuf:"bytes,2,opt,name=a2,proto3" json:"a2,omitempty"`
+	Z  []byte `protobuf:"bytes,3,opt,name=z,proto3" json:"z,omitempty"`
+}
+
+func (x *ECDDHProof) Reset() {
+	*x = ECDDHProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_signing_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ECDDHProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ECDDHProof) ProtoMessage() {}
+
+func (x *ECDDHProof) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_signing_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ECDDHProof.ProtoReflect.Descriptor instead.
+func (*ECDDHProof) Descriptor() ([]byte, []int) {
+	return file_lindell_signing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ECDDHProof) GetA1() []byte {
+	if x != nil {
+		return x.A1
+	}
+	return nil
+}
+
+func (x *ECDDHProof) GetA2() []byte {
+	if x != nil {
+		return x.A2
+	}
+	return nil
+}
+
+func (x *ECDDHProof) GetZ() []byte {
+	if x != nil {
+		return x.Z
+	}
+	return nil
+}
+
+// The server's ephemeral public share with its proof
+type EphKeyGenFirstMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DLogProof   *ECDDHProof `protobuf:"bytes,1,opt,name=dLogProof,proto3" json:"dLogProof,omitempty"`
+	PublicShare []byte      `protobuf:"bytes,2,opt,name=publicShare,proto3" json:"publicShare,omitempty"`
+	C           []byte      `protobuf:"bytes,3,opt,name=c,proto3" json:"c,omitempty"`
+}
+
+func (x *EphKeyGenFirstMsg) Reset() {
+	*x = EphKeyGenFirstMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_signing_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EphKeyGenFirstMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EphKeyGenFirstMsg) ProtoMessage() {}
+
+func (x *EphKeyGenFirstMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_signing_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EphKeyGenFirstMsg.ProtoReflect.Descriptor instead.
+func (*EphKeyGenFirstMsg) Descriptor() ([]byte, []int) {
+	return file_lindell_signing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EphKeyGenFirstMsg) GetDLogProof() *ECDDHProof {
+	if x != nil {
+		return x.DLogProof
+	}
+	return nil
+}
+
+func (x *EphKeyGenFirstMsg) GetPublicShare() []byte {
+	if x != nil {
+		return x.PublicShare
+	}
+	return nil
+}
+
+func (x *EphKeyGenFirstMsg) GetC() []byte {
+	if x != nil {
+		return x.C
+	}
+	return nil
+}
+
+// The client's commitments to its ephemeral public share and its proof
+type PartyTwoEphKeyGenFirstMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PkCommitment    []byte `protobuf:"bytes,1,opt,name=pkCommitment,proto3" json:"pkCommitment,omitempty"`
+	ZkPokCommitment []byte `protobuf:"bytes,2,opt,name=zkPokCommitment,proto3" json:"zkPokCommitment,omitempty"`
+}
+
+func (x *PartyTwoEphKeyGenFirstMsg) Reset() {
+	*x = PartyTwoEphKeyGenFirstMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_signing_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PartyTwoEphKeyGenFirstMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyTwoEphKeyGenFirstMsg) ProtoMessage() {}
+
+func (x *PartyTwoEphKeyGenFirstMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_signing_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyTwoEphKeyGenFirstMsg.ProtoReflect.Descriptor instead.
+func (*PartyTwoEphKeyGenFirstMsg) Descriptor() ([]byte, []int) {
+	return file_lindell_signing_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PartyTwoEphKeyGenFirstMsg) GetPkCommitment() []byte {
+	if x != nil {
+		return x.PkCommitment
+	}
+	return nil
+}
+
+func (x *PartyTwoEphKeyGenFirstMsg) GetZkPokCommitment() []byte {
+	if x != nil {
+		return x.ZkPokCommitment
+	}
+	return nil
+}
+
+// Opens the client's commitments
+type EphCommWitness struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PkCommitmentBlindFactor []byte      `protobuf:"bytes,1,opt,name=pkCommitmentBlindFactor,proto3" json:"pkCommitmentBlindFactor,omitempty"`
+	ZkPokBlindFactor        []byte      `protobuf:"bytes,2,opt,name=zkPokBlindFactor,proto3" json:"zkPokBlindFactor,omitempty"`
+	PublicShare             []byte      `protobuf:"bytes,3,opt,name=publicShare,proto3" json:"publicShare,omitempty"`
+	DLogProof               *ECDDHProof `protobuf:"bytes,4,opt,name=dLogProof,proto3" json:"dLogProof,omitempty"`
+	C                       []byte      `protobuf:"bytes,5,opt,name=c,proto3" json:"c,omitempty"`
+}
+
+func (x *EphCommWitness) Reset() {
+	*x = EphCommWitness{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_signing_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EphCommWitness) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EphCommWitness) ProtoMessage() {}
+
+func (x *EphCommWitness) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_signing_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EphCommWitness.ProtoReflect.Descriptor instead.
+func (*EphCommWitness) Descriptor() ([]byte, []int) {
+	return file_lindell_signing_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EphCommWitness) GetPkCommitmentBlindFactor() []byte {
+	if x != nil {
+		return x.PkCommitmentBlindFactor
+	}
+	return nil
+}
+
+func (x *EphCommWitness) GetZkPokBlindFactor() []byte {
+	if x != nil {
+		return x.ZkPokBlindFactor
+	}
+	return nil
+}
+
+func (x *EphCommWitness) GetPublicShare() []byte {
+	if x != nil {
+		return x.PublicShare
+	}
+	return nil
+}
+
+func (x *EphCommWitness) GetDLogProof() *ECDDHProof {
+	if x != nil {
+		return x.DLogProof
+	}
+	return nil
+}
+
+func (x *EphCommWitness) GetC() []byte {
+	if x != nil {
+		return x.C
+	}
+	return nil
+}
+
+// The Paillier ciphertext of the client's half of the signature
+type PartialSig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	C3 []byte `protobuf:"bytes,1,opt,name=c3,proto3" json:"c3,omitempty"`
+}
+
+func (x *PartialSig) Reset() {
+	*x = PartialSig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_signing_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PartialSig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartialSig) ProtoMessage() {}
+
+func (x *PartialSig) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_signing_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartialSig.ProtoReflect.Descriptor instead.
+func (*PartialSig) Descriptor() ([]byte, []int) {
+	return file_lindell_signing_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PartialSig) GetC3() []byte {
+	if x != nil {
+		return x.C3
+	}
+	return nil
+}
+
+// Adaptor mode only: R_hat = k*G and R = k*Y with the proof that they share a discrete log
+type AdaptorNonce struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RHat  []byte      `protobuf:"bytes,1,opt,name=rHat,proto3" json:"rHat,omitempty"`
+	R     []byte      `protobuf:"bytes,2,opt,name=r,proto3" json:"r,omitempty"`
+	Proof *ECDDHProof `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *AdaptorNonce) Reset() {
+	*x = AdaptorNonce{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_signing_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdaptorNonce) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdaptorNonce) ProtoMessage() {}
+
+func (x *AdaptorNonce) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_signing_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdaptorNonce.ProtoReflect.Descriptor instead.
+func (*AdaptorNonce) Descriptor() ([]byte, []int) {
+	return file_lindell_signing_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AdaptorNonce) GetRHat() []byte {
+	if x != nil {
+		return x.RHat
+	}
+	return nil
+}
+
+func (x *AdaptorNonce) GetR() []byte {
+	if x != nil {
+		return x.R
+	}
+	return nil
+}
+
+func (x *AdaptorNonce) GetProof() *ECDDHProof {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type Round2Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FirstMsg     *PartyTwoEphKeyGenFirstMsg `protobuf:"bytes,1,opt,name=firstMsg,proto3" json:"firstMsg,omitempty"`
+	CommWitness  *EphCommWitness            `protobuf:"bytes,2,opt,name=commWitness,proto3" json:"commWitness,omitempty"`
+	PartialSig   *PartialSig                `protobuf:"bytes,3,opt,name=partialSig,proto3" json:"partialSig,omitempty"`
+	AdaptorNonce *AdaptorNonce              `protobuf:"bytes,4,opt,name=adaptorNonce,proto3" json:"adaptorNonce,omitempty"`
+}
+
+func (x *Round2Result) Reset() {
+	*x = Round2Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_signing_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Round2Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Round2Result) ProtoMessage() {}
+
+func (x *Round2Result) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_signing_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Round2Result.ProtoReflect.Descriptor instead.
+func (*Round2Result) Descriptor() ([]byte, []int) {
+	return file_lindell_signing_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Round2Result) GetFirstMsg() *PartyTwoEphKeyGenFirstMsg {
+	if x != nil {
+		return x.FirstMsg
+	}
+	return nil
+}
+
+func (x *Round2Result) GetCommWitness() *EphCommWitness {
+	if x != nil {
+		return x.CommWitness
+	}
+	return nil
+}
+
+func (x *Round2Result) GetPartialSig() *PartialSig {
+	if x != nil {
+		return x.PartialSig
+	}
+	return nil
+}
+
+func (x *Round2Result) GetAdaptorNonce() *AdaptorNonce {
+	if x != nil {
+		return x.AdaptorNonce
+	}
+	return nil
+}
+
 var File_lindell_signing_proto protoreflect.FileDescriptor
 
 var file_lindell_signing_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e,
 	0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c,
-	0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x53, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e,
-	0x52, 0x6f, 0x75, 0x6e, 0x64, 0x31, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0c, 0x0a,
-	0x01, 0x4e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x4e, 0x12, 0x14, 0x0a, 0x05, 0x73,
-	0x68, 0x61, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72,
-	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x08, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x22, 0x25, 0x0a,
-	0x11, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x03, 0x72, 0x73, 0x74, 0x42, 0x11, 0x5a, 0x0f, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2f,
-	0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0xa2, 0x03, 0x0a, 0x11, 0x53, 0x69, 0x67,
+	0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x31, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0c,
+	0x0a, 0x01, 0x4e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x4e, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x68, 0x61,
+	0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x64, 0x6c, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x64, 0x6c, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x24,
+	0x0a, 0x0d, 0x70, 0x61, 0x69, 0x6c, 0x6c, 0x69, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x61, 0x69, 0x6c, 0x6c, 0x69, 0x65, 0x72, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x61, 0x64, 0x61, 0x70,
+	0x74, 0x6f, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x64, 0x61, 0x70,
+	0x74, 0x6f, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c,
+	0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x58, 0x0a, 0x0c,
+	0x74, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x31, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x3e, 0x0a, 0x08, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4d,
+	0x73, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65,
+	0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x45, 0x70, 0x68, 0x4b, 0x65,
+	0x79, 0x47, 0x65, 0x6e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x52, 0x08, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x1a, 0x3f, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x63, 0x65, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x22, 0xe5, 0x01,
+	0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x58, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6c, 0x69, 0x6e, 0x64,
+	0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x53, 0x69, 0x67, 0x6e,
+	0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x72,
+	0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0c, 0x74, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x2f, 0x0a,
+	0x03, 0x72, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6c, 0x69, 0x6e,
+	0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x52, 0x6f, 0x75,
+	0x6e, 0x64, 0x32, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x03, 0x72, 0x73, 0x74, 0x1a, 0x3f,
+	0x0a, 0x11, 0x54, 0x72, 0x61, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x4a,
+	0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x3a, 0x0a, 0x0a, 0x45, 0x43, 0x44, 0x44, 0x48, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x12, 0x0e, 0x0a, 0x02, 0x61, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x02, 0x61, 0x31, 0x12, 0x0e, 0x0a, 0x02, 0x61, 0x32, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x02, 0x61, 0x32, 0x12, 0x0c, 0x0a, 0x01, 0x7a, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01,
+	0x7a, 0x22, 0x7e, 0x0a, 0x11, 0x45, 0x70, 0x68, 0x4b, 0x65, 0x79, 0x47, 0x65, 0x6e, 0x46, 0x69,
+	0x72, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x39, 0x0a, 0x09, 0x64, 0x4c, 0x6f, 0x67, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x6e, 0x64,
+	0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x45, 0x43, 0x44, 0x44,
+	0x48, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x09, 0x64, 0x4c, 0x6f, 0x67, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x53, 0x68, 0x61, 0x72, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01,
+	0x63, 0x22, 0x69, 0x0a, 0x19, 0x50, 0x61, 0x72, 0x74, 0x79, 0x54, 0x77, 0x6f, 0x45, 0x70, 0x68,
+	0x4b, 0x65, 0x79, 0x47, 0x65, 0x6e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x22,
+	0x0a, 0x0c, 0x70, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x70, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x7a, 0x6b, 0x50, 0x6f, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x7a, 0x6b, 0x50,
+	0x6f, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xe1, 0x01, 0x0a,
+	0x0e, 0x45, 0x70, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12,
+	0x38, 0x0a, 0x17, 0x70, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x42,
+	0x6c, 0x69, 0x6e, 0x64, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x17, 0x70, 0x6b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x6c,
+	0x69, 0x6e, 0x64, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x10, 0x7a, 0x6b, 0x50,
+	0x6f, 0x6b, 0x42, 0x6c, 0x69, 0x6e, 0x64, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x10, 0x7a, 0x6b, 0x50, 0x6f, 0x6b, 0x42, 0x6c, 0x69, 0x6e, 0x64, 0x46,
+	0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x39, 0x0a, 0x09, 0x64, 0x4c, 0x6f, 0x67, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x6e,
+	0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x45, 0x43, 0x44,
+	0x44, 0x48, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x09, 0x64, 0x4c, 0x6f, 0x67, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x0c, 0x0a, 0x01, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x63,
+	0x22, 0x1c, 0x0a, 0x0a, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x69, 0x67, 0x12, 0x0e,
+	0x0a, 0x02, 0x63, 0x33, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x63, 0x33, 0x22, 0x63,
+	0x0a, 0x0c, 0x41, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x72, 0x48, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x72, 0x48,
+	0x61, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x72,
+	0x12, 0x31, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e,
+	0x67, 0x2e, 0x45, 0x43, 0x44, 0x44, 0x48, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x05, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x22, 0x99, 0x02, 0x0a, 0x0c, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x46, 0x0a, 0x08, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4d, 0x73, 0x67,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c,
+	0x2e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x79, 0x54, 0x77,
+	0x6f, 0x45, 0x70, 0x68, 0x4b, 0x65, 0x79, 0x47, 0x65, 0x6e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4d,
+	0x73, 0x67, 0x52, 0x08, 0x66, 0x69, 0x72, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x41, 0x0a, 0x0b,
+	0x63, 0x6f, 0x6d, 0x6d, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67, 0x6e,
+	0x69, 0x6e, 0x67, 0x2e, 0x45, 0x70, 0x68, 0x43, 0x6f, 0x6d, 0x6d, 0x57, 0x69, 0x74, 0x6e, 0x65,
+	0x73, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12,
+	0x3b, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x69, 0x67, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69,
+	0x67, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x69, 0x67,
+	0x52, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x69, 0x67, 0x12, 0x41, 0x0a, 0x0c,
+	0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x73, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x41, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x4e, 0x6f, 0x6e, 0x63,
+	0x65, 0x52, 0x0c, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x42,
+	0x11, 0x5a, 0x0f, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x69,
+	0x6e, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -161,17 +753,37 @@ func file_lindell_signing_proto_rawDescGZIP() []byte {
 	return file_lindell_signing_proto_rawDescData
 }
 
-var file_lindell_signing_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_lindell_signing_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_lindell_signing_proto_goTypes = []interface{}{
-	(*SignRound1Message)(nil), // 0: lindell.signing.SignRound1Message
-	(*SignRound2Message)(nil), // 1: lindell.signing.SignRound2Message
+	(*SignRound1Message)(nil),         // 0: lindell.signing.SignRound1Message
+	(*SignRound2Message)(nil),         // 1: lindell.signing.SignRound2Message
+	(*ECDDHProof)(nil),                // 2: lindell.signing.ECDDHProof
+	(*EphKeyGenFirstMsg)(nil),         // 3: lindell.signing.EphKeyGenFirstMsg
+	(*PartyTwoEphKeyGenFirstMsg)(nil), // 4: lindell.signing.PartyTwoEphKeyGenFirstMsg
+	(*EphCommWitness)(nil),            // 5: lindell.signing.EphCommWitness
+	(*PartialSig)(nil),                // 6: lindell.signing.PartialSig
+	(*AdaptorNonce)(nil),              // 7: lindell.signing.AdaptorNonce
+	(*Round2Result)(nil),              // 8: lindell.signing.Round2Result
+	nil,                               // 9: lindell.signing.SignRound1Message.TraceContextEntry
+	nil,                               // 10: lindell.signing.SignRound2Message.TraceContextEntry
 }
 var file_lindell_signing_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	9,  // 0: lindell.signing.SignRound1Message.traceContext:type_name -> lindell.signing.SignRound1Message.TraceContextEntry
+	3,  // 1: lindell.signing.SignRound1Message.firstMsg:type_name -> lindell.signing.EphKeyGenFirstMsg
+	10, // 2: lindell.signing.SignRound2Message.traceContext:type_name -> lindell.signing.SignRound2Message.TraceContextEntry
+	8,  // 3: lindell.signing.SignRound2Message.rst:type_name -> lindell.signing.Round2Result
+	2,  // 4: lindell.signing.EphKeyGenFirstMsg.dLogProof:type_name -> lindell.signing.ECDDHProof
+	2,  // 5: lindell.signing.EphCommWitness.dLogProof:type_name -> lindell.signing.ECDDHProof
+	2,  // 6: lindell.signing.AdaptorNonce.proof:type_name -> lindell.signing.ECDDHProof
+	4,  // 7: lindell.signing.Round2Result.firstMsg:type_name -> lindell.signing.PartyTwoEphKeyGenFirstMsg
+	5,  // 8: lindell.signing.Round2Result.commWitness:type_name -> lindell.signing.EphCommWitness
+	6,  // 9: lindell.signing.Round2Result.partialSig:type_name -> lindell.signing.PartialSig
+	7,  // 10: lindell.signing.Round2Result.adaptorNonce:type_name -> lindell.signing.AdaptorNonce
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_lindell_signing_proto_init() }
@@ -204,6 +816,90 @@ func file_lindell_signing_proto_init() {
 				return nil
 			}
 		}
+		file_lindell_signing_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ECDDHProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_signing_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EphKeyGenFirstMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_signing_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PartyTwoEphKeyGenFirstMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_signing_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EphCommWitness); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_signing_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PartialSig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_signing_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdaptorNonce); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_signing_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Round2Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -211,7 +907,7 @@ func file_lindell_signing_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_lindell_signing_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -4,14 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"go-rust/lindell/ffi"
+	"go-rust/lindell/logging"
+	"go-rust/lindell/zkp"
 
 	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/tss"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 )
 
+// ErrEquivocation is the cause of the error of a session in which a peer sent two different messages for one round
+var ErrEquivocation = errors.New("equivocation")
+
 // Implements Party
 // Implements Stringer
 var _ tss.Party = (*LocalParty)(nil)
@@ -27,8 +36,12 @@ type (
 		data common.SignatureData
 
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- common.SignatureData
+		out    chan<- tss.Message
+		end    chan<- common.SignatureData
+		preEnd chan<- *PreSignature
+
+		// held by Start and Update, so the session's secrets are never wiped under a running round
+		sessionMtx sync.Mutex
 	}
 
 	localMessageStore struct {
@@ -43,12 +56,25 @@ type (
 
 		// temp data (thrown away after sign) / round 1
 		secretShare *big.Int
-		publicShare *big.Int
+		// xi with the key derivation delta added, if any
+		derivedXi *big.Int
 
 		keyDerivationDelta *big.Int
 
 		//round1Result
 		round1Rst *ffi.Round1Result
+
+		// adaptor mode: Y and the server's nonce share k1·Y with its proof
+		adaptorPoint *crypto.ECPoint
+		r1, r1Y      *crypto.ECPoint
+		r1Proof      *zkp.DLEQProof
+
+		metrics *sessionMetrics
+		tracing *sessionTracing
+		log     *zap.Logger
+
+		// set once the session succeeded, failed or was aborted; its secrets are then wiped
+		ended int32
 	}
 )
 
@@ -87,32 +113,119 @@ func NewLocalPartyWithKDD(
 	// temp data init
 	p.temp.m = msg
 	p.temp.keyDerivationDelta = keyDerivationDelta
+	p.temp.metrics = newSessionMetrics(params)
+	p.temp.tracing = newSessionTracing(params)
+	p.temp.log = newSessionLogger(params)
 
 	return p
 }
 
+// NewLocalPartyWithAdaptor returns a party that produces an adaptor pre-signature under adaptorPoint Y = y·G instead
+// of a signature. The server outputs the verified pre-signature on end, the client an empty one.
+func NewLocalPartyWithAdaptor(
+	msg *big.Int,
+	params *LindellSignParameters,
+	key keygen.LocalPartySaveData,
+	adaptorPoint *crypto.ECPoint,
+	out chan<- tss.Message,
+	end chan<- *PreSignature,
+) tss.Party {
+	p := NewLocalPartyWithKDD(msg, params, key, nil, out, nil).(*LocalParty)
+	p.temp.adaptorPoint = adaptorPoint
+	p.preEnd = end
+	return p
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, &p.data, &p.temp, p.out, p.end)
+	return newRound1(p.params, &p.keys, &p.data, &p.temp, p.out, p.end, p.preEnd)
 }
 
 func (p *LocalParty) Start() *tss.Error {
-	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
+	defer p.lockSession()()
+	if p.temp.hasEnded() {
+		return p.WrapError(errors.New("the session has ended"))
+	}
+	p.temp.metrics.start()
+	p.temp.tracing.start()
+	p.temp.log.Debug("session started", p.round())
+	err := tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
 		round1, ok := round.(*round1)
 		if !ok {
 			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
 		}
+		defer p.temp.tracing.span("prepare")()
 		if err := round1.prepare(); err != nil {
 			return round.WrapError(err)
 		}
 		return nil
 	})
+	if err == nil {
+		err = p.catchUp()
+	}
+	return p.failed(err)
+}
+
+// catchUp updates the rounds with the messages that arrived before Start and were only stored. The client receives
+// nothing after the server's round 1 message, so without it a client that started late would wait forever.
+func (p *LocalParty) catchUp() *tss.Error {
+	for _, msgs := range [][]tss.ParsedMessage{p.temp.signRound1Messages, p.temp.signRound2Messages} {
+		for _, msg := range msgs {
+			if p.temp.hasEnded() {
+				return nil
+			}
+			if msg == nil {
+				continue
+			}
+			if _, err := tss.BaseUpdate(p, msg, TaskName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
-	return tss.BaseUpdate(p, msg, TaskName)
+	defer p.lockSession()()
+	if p.temp.hasEnded() {
+		p.temp.log.Debug("message after the end of the session ignored", p.round(), zap.String("type", msg.Type()))
+		return false, nil
+	}
+	ok, err = tss.BaseUpdate(p, msg, TaskName)
+	return ok, p.failed(err)
+}
+
+// Abort is called by the session's driver when it gives the session up, e.g. on a timeout or a lost connection
+func (p *LocalParty) Abort(err error) {
+	p.temp.log.Warn("session aborted", p.round(), zap.Error(err))
+	p.temp.metrics.aborted(err)
+	p.temp.tracing.finish(err)
+	p.temp.end()
+	// a running round must not see its secrets vanish, it wipes them itself when it returns
+	if p.sessionMtx.TryLock() {
+		p.temp.wipe()
+		p.sessionMtx.Unlock()
+	}
+}
+
+// failed reports err returned by Start or Update, if any, and returns it unchanged
+func (p *LocalParty) failed(err *tss.Error) *tss.Error {
+	if err != nil {
+		logFailure(p.temp.log, err)
+		p.temp.tracing.finish(err)
+		p.temp.end()
+	}
+	return p.temp.metrics.failed(err)
+}
+
+// round is the log field of the round the session is in
+func (p *LocalParty) round() zap.Field {
+	return zap.Int(logging.KeyRound, p.temp.metrics.currentRound())
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	if len(wireBytes) > MaxWireBytes {
+		return false, p.WrapError(fmt.Errorf("message is longer than %d bytes", MaxWireBytes), from)
+	}
 	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
@@ -129,30 +242,91 @@ func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
 			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
 	}
+	if err := p.validateContent(msg); err != nil {
+		return false, p.WrapError(err, msg.GetFrom())
+	}
 	return true, nil
 }
 
+// validateContent checks a message beyond ValidateBasic, so nothing malformed is stored or reaches lindellcore
+func (p *LocalParty) validateContent(msg tss.ParsedMessage) error {
+	ec := p.params.EC()
+	switch content := msg.Content().(type) {
+	case *SignRound1Message:
+		return content.Validate(ec)
+	case *SignRound2Message:
+		var N *big.Int
+		if p.params.IsServer() && p.keys.PaillierSK != nil {
+			N = p.keys.PaillierSK.N
+		}
+		return content.Validate(ec, N)
+	}
+	return nil
+}
+
 func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	// ValidateBasic is cheap; double-check the message here in case the public StoreMessage was called externally
 	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
 	fromPIdx := msg.GetFrom().Index
+	if fromPIdx == p.PartyID().Index {
+		return false, p.WrapError(errors.New("received a message that claims to come from this party"))
+	}
 
 	// switch/case is necessary to store any messages beyond current round
-	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	// replays are caught by comparing with the stored message, spoofing protection is left to the caller
+	var store []tss.ParsedMessage
 	switch msg.Content().(type) {
 	case *SignRound1Message:
-		p.temp.signRound1Messages[fromPIdx] = msg
+		// only the server sends round 1, so a peer's round 1 message means both sides believe they are the server
+		if p.params.IsServer() {
+			return false, p.WrapError(errors.New("received a round 1 message from a peer that also acts as the server"), msg.GetFrom())
+		}
+		store = p.temp.signRound1Messages
 	case *SignRound2Message:
-		p.temp.signRound2Messages[fromPIdx] = msg
-	default: // unrecognised message, just ignore!
-		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		if !p.params.IsServer() {
+			return false, p.WrapError(errors.New("received a round 2 message from a peer that also acts as the client"), msg.GetFrom())
+		}
+		store = p.temp.signRound2Messages
+	default: // unrecognised message, just ignore! its content is not logged, it may be anything
+		p.temp.log.Warn("unrecognised message ignored", p.round(),
+			zap.String("type", msg.Type()), zap.String("from", msg.GetFrom().Id))
 		return false, nil
 	}
+	if stored := store[fromPIdx]; stored != nil {
+		if !sameMessage(stored, msg) {
+			return false, p.WrapError(fmt.Errorf("%w: %s sent two different %s", ErrEquivocation, msg.GetFrom(), msg.Type()),
+				msg.GetFrom())
+		}
+		// the stored message stays; reporting it as stored lets the round look at its store again, which is idempotent
+		p.temp.log.Debug("duplicate message ignored", p.round(),
+			zap.String("type", msg.Type()), zap.String("from", msg.GetFrom().Id))
+		return true, nil
+	}
+	store[fromPIdx] = msg
+	p.temp.log.Debug("message stored", p.round(), zap.String("type", msg.Type()), zap.String("from", msg.GetFrom().Id))
 	return true, nil
 }
 
+// sameMessage reports whether b repeats a, i.e. has the same protocol fields and was sent the same way. The trace
+// context is metadata a relay may rewrite, a copy that differs only there is no equivocation
+func sameMessage(a, b tss.ParsedMessage) bool {
+	return a == b || a.IsBroadcast() == b.IsBroadcast() && proto.Equal(protocolFields(a), protocolFields(b))
+}
+
+// protocolFields returns a copy of msg's content without its trace context
+func protocolFields(msg tss.ParsedMessage) proto.Message {
+	content := proto.Clone(msg.Content())
+	switch m := content.(type) {
+	case *SignRound1Message:
+		m.TraceContext = nil
+	case *SignRound2Message:
+		m.TraceContext = nil
+	}
+	return content
+}
+
 func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
@@ -8,16 +8,30 @@ package signing
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
 	"fmt"
 	"math/big"
 	"runtime"
+	"strings"
 	"sync/atomic"
 	"testing"
 
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/metrics"
+	"go-rust/lindell/signing/sigenc"
+
 	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/ipfs/go-log"
+	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func setUp(level string) {
@@ -26,11 +40,14 @@ func setUp(level string) {
 	}
 }
 
+// skipWithoutFixtures skips a test or benchmark when the keygen fixtures have not been generated
+func skipWithoutFixtures(tb testing.TB, err error) {
+	if errors.Is(err, ErrKeygenFixturesMissing) {
+		tb.Skip(err)
+	}
+}
+
 func SharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
-	// do not send a message from this party back to itself
-	//if party.PartyID() == msg.GetFrom() {
-	//	return
-	//}
 	bz, _, err := msg.WireBytes()
 	if err != nil {
 		errCh <- party.WrapError(err)
@@ -53,10 +70,40 @@ func TestE2EConcurrent(t *testing.T) {
 
 	// PHASE: load keygen fixtures
 	keys, signPIDs, err := LoadKeygenTestFixtures(threshold + 1)
+	skipWithoutFixtures(t, err)
 	assert.NoError(t, err, "should load keygen fixtures")
 	assert.Equal(t, threshold+1, len(keys))
 	assert.Equal(t, threshold+1, len(signPIDs))
 
+	runE2E(t, tss.S256(), keys, signPIDs, threshold)
+}
+
+func TestE2EConcurrentP256(t *testing.T) {
+	setUp("info")
+	threshold := 1
+
+	keys, signPIDs, err := LoadKeygenTestFixtures(threshold + 1)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+	keys, err = RedealKeygenTestFixtures(elliptic.P256(), keys, threshold)
+	assert.NoError(t, err, "should redeal the fixtures onto P-256")
+
+	runE2E(t, elliptic.P256(), keys, signPIDs, threshold)
+}
+
+func TestE2EConcurrent2of2(t *testing.T) {
+	setUp("info")
+	threshold := 1
+
+	keys, signPIDs, err := LoadKeygenTestFixturesFromDir(TestFixtureDir2of2, threshold+1)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Len(t, keys[0].Ks, 2, "a key of two parties only")
+
+	runE2E(t, tss.S256(), keys, signPIDs, threshold)
+}
+
+func runE2E(t *testing.T, ec elliptic.Curve, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, threshold int) {
 	// PHASE: signing
 	// use a shuffled selection of the list of parties for this test
 	p2pCtx := tss.NewPeerContext(signPIDs)
@@ -65,16 +112,18 @@ func TestE2EConcurrent(t *testing.T) {
 	errCh := make(chan *tss.Error, len(signPIDs))
 	outCh := make(chan tss.Message, len(signPIDs))
 	endCh := make(chan common.SignatureData, len(signPIDs))
+	prom := metrics.NewPrometheus()
+	tracer := mocktracer.New()
+	logCore, logs := observer.New(zapcore.DebugLevel)
 
-	isServer := false
 	// init the parties
 	for i := 0; i < len(signPIDs); i++ {
-		if i == 0 {
-			isServer = true
-		} else {
-			isServer = false
-		}
-		params := NewLindellSignParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold, isServer)
+		params, err := NewLindellSignParameters(ec, p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		assert.NoError(t, err, "should build signing params")
+		params.SetMetrics(prom)
+		params.SetTracer(tracer)
+		params.SetLogger(zap.New(logCore))
+		params.SetSessionID("e2e")
 
 		P := NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh).(*LocalParty)
 		parties = append(parties, P)
@@ -99,9 +148,9 @@ signing:
 			dest := msg.GetTo()
 			if dest == nil {
 				for _, P := range parties {
-					//if P.PartyID().Index == msg.GetFrom().Index {
-					//	continue
-					//}
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
 					go SharedPartyUpdater(P, msg, errCh)
 				}
 			} else {
@@ -119,20 +168,206 @@ signing:
 				// BEGIN ECDSA verify
 				pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
 				pk := ecdsa.PublicKey{
-					Curve: tss.EC(),
+					Curve: ec,
 					X:     pkX,
 					Y:     pkY,
 				}
 
 				ok := ecdsa.Verify(&pk, big.NewInt(42).Bytes(), big.NewInt(0).SetBytes(msg.GetR()), big.NewInt(0).SetBytes(msg.GetS()))
 				assert.True(t, ok, "ecdsa verify must pass")
+
+				// the recovery id must recover the signing key
+				if name, _ := ffi.CurveName(ec); name == ffi.CurveSecp256k1 {
+					compact, err := sigenc.BitcoinCompactRecoverable(&msg, true)
+					assert.NoError(t, err)
+					recovered, _, err := btcec.RecoverCompact(btcec.S256(), compact, big.NewInt(42).Bytes())
+					assert.NoError(t, err)
+					assert.True(t, recovered.X.Cmp(pkX) == 0 && recovered.Y.Cmp(pkY) == 0, "recovered key must match")
+				}
 				t.Log("ECDSA signing test done.")
 				// END ECDSA verify
 			}
 
 			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				var exposition strings.Builder
+				prom.WriteTo(&exposition)
+				for _, line := range []string{
+					`lindell_signing_sessions_succeeded_total{role="server"} 1`,
+					`lindell_signing_sessions_succeeded_total{role="client"} 1`,
+					`lindell_signing_call_duration_seconds_count{call="ffi_round3"} 1`,
+					`lindell_signing_call_duration_seconds_count{call="paillier_decrypt"} 1`,
+				} {
+					assert.Contains(t, exposition.String(), line)
+				}
+				// both halves of the signature are one trace
+				traces := make(map[int]bool)
+				for _, sp := range tracer.FinishedSpans() {
+					traces[sp.SpanContext.TraceID] = true
+				}
+				assert.Len(t, traces, 1)
+				// and every log entry says which session, party, role and round it is about
+				assertSessionFields(t, logs.AllUntimed(), "e2e")
+				assert.Equal(t, 2, logs.FilterMessage("session succeeded").Len())
 				break signing
 			}
 		}
 	}
 }
+
+func TestE2EAdaptor(t *testing.T) {
+	setUp("info")
+	threshold := 1
+	ec := tss.S256()
+
+	keys, signPIDs, err := LoadKeygenTestFixtures(threshold + 1)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	y := common.GetRandomPositiveInt(ec.Params().N)
+	Y := crypto.ScalarBaseMult(ec, y)
+	m := big.NewInt(42)
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *PreSignature, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params, err := NewLindellSignParameters(ec, p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		assert.NoError(t, err, "should build signing params")
+
+		P := NewLocalPartyWithAdaptor(m, params, keys[i], Y, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int
+	for ended < len(signPIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				go SharedPartyUpdater(P, msg, errCh)
+			}
+
+		case pre := <-endCh:
+			ended++
+			if pre.SHat == nil {
+				continue
+			}
+			assert.NoError(t, VerifyPreSignature(keys[0].ECDSAPub, m, Y, pre))
+
+			sig, err := AdaptPreSignature(pre, y)
+			assert.NoError(t, err)
+			pk := ecdsa.PublicKey{Curve: ec, X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+			assert.True(t, ecdsa.Verify(&pk, m.Bytes(), new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())),
+				"adapted signature must verify")
+
+			extracted, err := ExtractAdaptorSecret(pre, Y, sig)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, extracted.Cmp(y), "the published signature must reveal y")
+		}
+	}
+}
+
+// recoverSigner recovers the public key of a secp256k1 sig over m from its recovery id
+func recoverSigner(t *testing.T, sig *common.SignatureData, m *big.Int) *btcec.PublicKey {
+	compact, err := sigenc.BitcoinCompactRecoverable(sig, true)
+	assert.NoError(t, err)
+	recovered, _, err := btcec.RecoverCompact(btcec.S256(), compact, m.Bytes())
+	assert.NoError(t, err)
+	return recovered
+}
+
+func TestLowSRecoveryID(t *testing.T) {
+	ec := tss.S256()
+	q := ec.Params().N
+	modQ := common.ModInt(q)
+	x := common.GetRandomPositiveInt(q)
+	pub := crypto.ScalarBaseMult(ec, x)
+
+	negated := 0
+	for i := 0; i < 64; i++ {
+		m := common.GetRandomPositiveInt(q)
+		k := common.GetRandomPositiveInt(q)
+		R := crypto.ScalarBaseMult(ec, k)
+		r := new(big.Int).Mod(R.X(), q)
+		// s as lindellcore computes it, not normalised
+		s := modQ.Mul(modQ.ModInverse(k), modQ.Add(m, modQ.Mul(r, x)))
+		low, recid := lowS(q, R, s)
+		if low.Cmp(s) != 0 {
+			negated++
+		}
+
+		sig := &common.SignatureData{R: r.Bytes(), S: low.Bytes(), SignatureRecovery: []byte{byte(recid)}}
+		recovered := recoverSigner(t, sig, m)
+		if recovered != nil {
+			assert.True(t, recovered.X.Cmp(pub.X()) == 0 && recovered.Y.Cmp(pub.Y()) == 0,
+				"signature %d must recover the signing key", i)
+		}
+	}
+	assert.True(t, negated > 0 && negated < 64, "both halves of the order must be covered, %d negated", negated)
+}
+
+func TestE2ERecoveryIDs(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := LoadKeygenTestFixtures(2)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	pkX, pkY := keys[0].ECDSAPub.X(), keys[0].ECDSAPub.Y()
+
+	// about half of the signatures have their s negated, each one must still recover the key
+	for n := 0; n < 16; n++ {
+		m := common.GetRandomPositiveInt(tss.S256().Params().N)
+		errCh := make(chan *tss.Error, 2)
+		outCh := make(chan tss.Message, 2)
+		endCh := make(chan common.SignatureData, 2)
+		parties := make([]*LocalParty, len(signPIDs))
+		for i := range signPIDs {
+			params, err := NewLindellSignParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), 1)
+			assert.NoError(t, err, "should build signing params")
+			params.SetLogger(zap.NewNop())
+			parties[i] = NewLocalParty(m, params, keys[i], outCh, endCh).(*LocalParty)
+		}
+		for _, P := range parties {
+			if err := P.Start(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for ended := 0; ended < len(parties); {
+			select {
+			case err := <-errCh:
+				t.Fatal(err)
+			case msg := <-outCh:
+				for _, P := range parties {
+					if P.PartyID().Index != msg.GetFrom().Index {
+						SharedPartyUpdater(P, msg, errCh)
+					}
+				}
+			case <-endCh:
+				ended++
+			}
+		}
+		for _, P := range parties {
+			if !P.params.IsServer() {
+				continue
+			}
+			// what the server output on endCh
+			recovered := recoverSigner(t, &P.data, m)
+			assert.True(t, recovered != nil && recovered.X.Cmp(pkX) == 0 && recovered.Y.Cmp(pkY) == 0,
+				"signature %d must recover the signing key", n)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package signing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"go-rust/lindell/logging"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"go.uber.org/zap"
+)
+
+// newSessionLogger returns the parameters' logger with the fields of the session. The round is added per entry.
+func newSessionLogger(params *LindellSignParameters) *zap.Logger {
+	sessionID := params.SessionID()
+	if sessionID == "" {
+		var bz [8]byte
+		rand.Read(bz[:])
+		sessionID = hex.EncodeToString(bz[:])
+	}
+	return params.Logger().With(
+		zap.String(logging.KeySession, sessionID),
+		zap.String(logging.KeyParty, params.PartyID().Id),
+		zap.String(logging.KeyRole, params.Role().String()),
+	)
+}
+
+// logFailure logs err returned by Start or Update; a peer's fault is a warning, a local one an error
+func logFailure(logger *zap.Logger, err *tss.Error) {
+	fields := []zap.Field{zap.Int(logging.KeyRound, err.Round()), zap.Error(err.Cause())}
+	if culprits := err.Culprits(); len(culprits) > 0 {
+		ids := make([]string, len(culprits))
+		for i, c := range culprits {
+			ids[i] = c.Id
+		}
+		logger.Warn("session failed", append(fields, zap.Strings("culprits", ids))...)
+		return
+	}
+	logger.Error("session failed", fields...)
+}
@@ -0,0 +1,85 @@
+package signing
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"go-rust/lindell/logging"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/resharing"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// assertSessionFields checks the fields every entry of a session carries
+func assertSessionFields(t *testing.T, entries []observer.LoggedEntry, sessionID string) {
+	for _, e := range entries {
+		fields := e.ContextMap()
+		if sessionID != "" {
+			assert.Equal(t, sessionID, fields[logging.KeySession], e.Message)
+		} else {
+			assert.NotEmpty(t, fields[logging.KeySession], e.Message)
+		}
+		for _, key := range []string{logging.KeyParty, logging.KeyRole, logging.KeyRound} {
+			assert.Contains(t, fields, key, "%q has no %s", e.Message, key)
+		}
+	}
+}
+
+func TestUnrecognisedMessageIsLoggedWithoutItsContent(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params, err := NewLindellSignParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], 2, 1)
+	assert.NoError(t, err)
+	core, logs := observer.New(zapcore.DebugLevel)
+	params.SetLogger(zap.New(core))
+	params.SetSessionID("session-1")
+
+	key := keygen.NewLocalPartySaveData(2)
+	key.Ks = []*big.Int{pIDs[0].KeyInt(), pIDs[1].KeyInt()}
+	party := NewLocalParty(big.NewInt(42), params, key, nil, nil).(*LocalParty)
+
+	content := &resharing.DGRound3Message1{Share: []byte("a secret share")}
+	routing := tss.MessageRouting{From: pIDs[1], To: []*tss.PartyID{pIDs[0]}}
+	msg := tss.NewMessage(routing, content, tss.NewMessageWrapper(routing, content))
+	ok, tssErr := party.StoreMessage(msg)
+	assert.False(t, ok)
+	assert.Nil(t, tssErr)
+	party.Abort(context.DeadlineExceeded)
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 2)
+	assertSessionFields(t, entries, "session-1")
+	ignored := entries[0]
+	assert.Equal(t, zapcore.WarnLevel, ignored.Level)
+	assert.Equal(t, "unrecognised message ignored", ignored.Message)
+	assert.Equal(t, pIDs[1].Id, ignored.ContextMap()["from"])
+	assert.Equal(t, "server", ignored.ContextMap()[logging.KeyRole])
+	assert.Equal(t, int64(0), ignored.ContextMap()[logging.KeyRound])
+	for _, e := range entries {
+		for _, v := range e.ContextMap() {
+			if s, ok := v.(string); ok {
+				assert.False(t, strings.Contains(s, "secret"), "the message's content was logged")
+			}
+		}
+	}
+	assert.Equal(t, "session aborted", entries[1].Message)
+}
+
+func TestLoggerDefaultsAndRedacts(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params, err := NewLindellSignParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], 2, 1)
+	assert.NoError(t, err)
+	assert.Same(t, logging.Default(), params.Logger())
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	params.SetLogger(zap.New(core))
+	assert.NotEqual(t, core, params.Logger().Core(), "a logger set on the parameters is always redacting")
+	params.Logger().Info("key", zap.Any("paillier", &keygen.LocalPreParams{}))
+	assert.Equal(t, logging.Redacted, logs.All()[0].ContextMap()["paillier"])
+}
@@ -7,9 +7,17 @@
 package signing
 
 import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
 	"math/big"
 
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/zkp"
+
 	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
@@ -24,12 +32,29 @@ var (
 	}
 )
 
+const (
+	// MaxWireBytes bounds a signing message on the wire, far above the largest honest one
+	MaxWireBytes = 64 << 10
+
+	maxPaillierModulusBits = 4096
+	// the partial signature and the largest part of a PDL proof live modulo N²
+	maxCiphertextBytes     = 2 * maxPaillierModulusBits / 8
+	maxProofPartBytes      = maxCiphertextBytes
+	maxTraceContextEntries = 16
+	maxTraceContextBytes   = 256
+)
+
 // ----- //
 
 func NewSignRound1Message(
 	from *tss.PartyID,
 	N, Share *big.Int,
-	firstMsg []byte,
+	firstMsg ffi.EphKeyGenFirstMsg,
+	pdlProof *zkp.PDLwSlackProof,
+	paillierProof paillier.Proof,
+	adaptorShare *crypto.ECPoint,
+	adaptorProof *zkp.DLEQProof,
+	traceContext map[string]string,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From: from,
@@ -38,10 +63,19 @@ func NewSignRound1Message(
 	}
 	nBz := N.Bytes()
 	sBz := Share.Bytes()
+	pdlBzs := pdlProof.Bytes()
 	content := &SignRound1Message{
-		N:        nBz,
-		Share:    sBz,
-		FirstMsg: firstMsg,
+		N:             nBz,
+		Share:         sBz,
+		FirstMsg:      newFirstMsgPayload(firstMsg),
+		PdlProof:      pdlBzs[:],
+		PaillierProof: common.BigIntsToBytes(paillierProof[:]),
+		TraceContext:  traceContext,
+	}
+	if adaptorShare != nil {
+		adaptorBzs := adaptorProof.Bytes()
+		content.AdaptorShare = [][]byte{adaptorShare.X().Bytes(), adaptorShare.Y().Bytes()}
+		content.AdaptorProof = adaptorBzs[:]
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -49,7 +83,65 @@ func NewSignRound1Message(
 
 func (m *SignRound1Message) ValidateBasic() bool {
 	return m != nil &&
-		common.NonEmptyBytes(m.GetN()) && common.NonEmptyBytes(m.GetShare()) && common.NonEmptyBytes(m.GetFirstMsg())
+		common.NonEmptyBytes(m.GetN()) && common.NonEmptyBytes(m.GetShare()) && m.GetFirstMsg() != nil &&
+		common.NonEmptyMultiBytes(m.GetPdlProof(), zkp.PDLwSlackProofBytesParts) &&
+		// the modulus proof may be omitted once the client has verified it for this key
+		(len(m.GetPaillierProof()) == 0 || common.NonEmptyMultiBytes(m.GetPaillierProof(), zkp.PaillierModulusProofBytesParts)) &&
+		// the adaptor nonce share is only present when signing in adaptor mode
+		((len(m.GetAdaptorShare()) == 0 && len(m.GetAdaptorProof()) == 0) ||
+			(common.NonEmptyMultiBytes(m.GetAdaptorShare(), 2) && common.NonEmptyMultiBytes(m.GetAdaptorProof(), zkp.DLEQProofBytesParts)))
+}
+
+// Validate checks everything ValidateBasic cannot without the curve: that there are no unknown fields, the sizes of
+// all parts, the Paillier modulus and ciphertext and that every point is on ec. The proofs themselves are verified by
+// round 2.
+func (m *SignRound1Message) Validate(ec elliptic.Curve) error {
+	if !m.ValidateBasic() {
+		return errors.New("round 1 message failed ValidateBasic")
+	}
+	if err := checkUnknownFields(m.ProtoReflect()); err != nil {
+		return err
+	}
+	N := m.UnmarshalN()
+	if m.N[0] == 0 || N.BitLen() < zkp.MinPaillierModulusBitLen || N.BitLen() > maxPaillierModulusBits || N.Bit(0) == 0 {
+		return fmt.Errorf("paillier modulus must be odd and of %d to %d bits", zkp.MinPaillierModulusBitLen, maxPaillierModulusBits)
+	}
+	if m.Share[0] == 0 || !isPaillierCiphertext(m.UnmarshalShare(), N) {
+		return errors.New("encrypted share is not a ciphertext under the paillier key")
+	}
+	if _, err := m.UnmarshalFirstMsg(ec); err != nil {
+		return err
+	}
+	if err := checkPartSizes("pdl proof", m.GetPdlProof(), maxProofPartBytes); err != nil {
+		return err
+	}
+	if err := checkCoordinates(ec, m.GetPdlProof()[1:3]); err != nil {
+		return fmt.Errorf("pdl proof: %v", err)
+	}
+	if _, err := m.UnmarshalPDLwSlackProof(ec); err != nil {
+		return err
+	}
+	if err := checkPartSizes("paillier proof", m.GetPaillierProof(), len(m.N)); err != nil {
+		return err
+	}
+	if m.HasAdaptorShare() {
+		if err := checkCoordinates(ec, m.GetAdaptorShare()); err != nil {
+			return fmt.Errorf("adaptor share: %v", err)
+		}
+		if _, err := m.UnmarshalAdaptorShare(ec); err != nil {
+			return err
+		}
+		if err := checkCoordinates(ec, m.GetAdaptorProof()[:4]); err != nil {
+			return fmt.Errorf("adaptor proof: %v", err)
+		}
+		if z := m.GetAdaptorProof()[4]; len(z) > len(ec.Params().N.Bytes()) || new(big.Int).SetBytes(z).Cmp(ec.Params().N) >= 0 {
+			return errors.New("adaptor proof: response is not reduced modulo the order")
+		}
+		if _, err := m.UnmarshalAdaptorProof(ec); err != nil {
+			return err
+		}
+	}
+	return checkTraceContext(m.GetTraceContext())
 }
 
 func (m *SignRound1Message) UnmarshalN() *big.Int {
@@ -60,25 +152,150 @@ func (m *SignRound1Message) UnmarshalShare() *big.Int {
 	return new(big.Int).SetBytes(m.GetShare())
 }
 
+func (m *SignRound1Message) HasPaillierProof() bool {
+	return len(m.GetPaillierProof()) != 0
+}
+
+func (m *SignRound1Message) UnmarshalPaillierProof() (paillier.Proof, error) {
+	return zkp.PaillierModulusProofFromBytes(m.GetPaillierProof())
+}
+
+func (m *SignRound1Message) HasAdaptorShare() bool {
+	return len(m.GetAdaptorShare()) != 0
+}
+
+func (m *SignRound1Message) UnmarshalAdaptorShare(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	bzs := m.GetAdaptorShare()
+	if !common.NonEmptyMultiBytes(bzs, 2) {
+		return nil, errors.New("expected 2 byte parts to construct the adaptor nonce share")
+	}
+	return crypto.NewECPoint(ec, new(big.Int).SetBytes(bzs[0]), new(big.Int).SetBytes(bzs[1]))
+}
+
+func (m *SignRound1Message) UnmarshalAdaptorProof(ec elliptic.Curve) (*zkp.DLEQProof, error) {
+	return zkp.DLEQProofFromBytes(ec, m.GetAdaptorProof())
+}
+
+// UnmarshalFirstMsg returns the server's first message for lindellcore after validating it
+func (m *SignRound1Message) UnmarshalFirstMsg(ec elliptic.Curve) (ffi.EphKeyGenFirstMsg, error) {
+	curve, err := ffi.CurveName(ec)
+	if err != nil {
+		return ffi.EphKeyGenFirstMsg{}, err
+	}
+	if m.GetFirstMsg() == nil {
+		return ffi.EphKeyGenFirstMsg{}, errors.New("first message is missing")
+	}
+	msg1 := m.GetFirstMsg().toFFI(curve)
+	if err := msg1.Validate(ec); err != nil {
+		return msg1, fmt.Errorf("first message: %v", err)
+	}
+	return msg1, nil
+}
+
+func (m *SignRound1Message) UnmarshalPDLwSlackProof(ec elliptic.Curve) (*zkp.PDLwSlackProof, error) {
+	return zkp.PDLwSlackProofFromBytes(ec, m.GetPdlProof())
+}
+
 // ----- //
 
 func NewSignRound2Message(
 	from *tss.PartyID,
-	rst2 []byte,
+	rst2 ffi.Round2Result,
+	traceContext map[string]string,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
 		IsBroadcast: true,
 	}
 	content := &SignRound2Message{
-		Rst: rst2,
+		Rst:          newRound2ResultPayload(rst2),
+		TraceContext: traceContext,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
 
 func (m *SignRound2Message) ValidateBasic() bool {
-	return m != nil && common.NonEmptyBytes(m.Rst)
+	return m != nil && m.GetRst() != nil
+}
+
+// Validate checks the client's output like UnmarshalRst does. Given the server's Paillier modulus N, it also checks
+// that the partial signature is a ciphertext under it; the client, which never decrypts it, passes nil.
+func (m *SignRound2Message) Validate(ec elliptic.Curve, N *big.Int) error {
+	if !m.ValidateBasic() {
+		return errors.New("round 2 message failed ValidateBasic")
+	}
+	if err := checkUnknownFields(m.ProtoReflect()); err != nil {
+		return err
+	}
+	rst2, err := m.UnmarshalRst(ec)
+	if err != nil {
+		return err
+	}
+	if N != nil && !isPaillierCiphertext(ffi.Str2BigInt(rst2.PartialSig.C3), N) {
+		return errors.New("partial signature is not a ciphertext under the paillier key")
+	}
+	return checkTraceContext(m.GetTraceContext())
+}
+
+// UnmarshalRst returns the client's output for lindellcore after validating it
+func (m *SignRound2Message) UnmarshalRst(ec elliptic.Curve) (ffi.Round2Result, error) {
+	curve, err := ffi.CurveName(ec)
+	if err != nil {
+		return ffi.Round2Result{}, err
+	}
+	if m.GetRst() == nil {
+		return ffi.Round2Result{}, errors.New("round 2 result is missing")
+	}
+	if err := m.GetRst().checkNumbers(maxCiphertextBytes); err != nil {
+		return ffi.Round2Result{}, fmt.Errorf("round 2 result: %v", err)
+	}
+	rst2 := m.GetRst().toFFI(curve)
+	if err := rst2.Validate(ec); err != nil {
+		return rst2, fmt.Errorf("round 2 result: %v", err)
+	}
+	return rst2, nil
+}
+
+// ----- //
+
+// isPaillierCiphertext reports whether c is in Z*_{N²}
+func isPaillierCiphertext(c, N *big.Int) bool {
+	NSq := new(big.Int).Mul(N, N)
+	return c.Sign() > 0 && c.Cmp(NSq) < 0 && new(big.Int).GCD(nil, nil, c, N).Cmp(big.NewInt(1)) == 0
+}
+
+func checkPartSizes(name string, bzs [][]byte, max int) error {
+	for i, bz := range bzs {
+		if len(bz) > max {
+			return fmt.Errorf("%s part %d is longer than %d bytes", name, i, max)
+		}
+	}
+	return nil
+}
+
+// checkCoordinates checks that pairs of affine coordinates are field elements of ec, which crypto.NewECPoint does not
+// check on every curve
+func checkCoordinates(ec elliptic.Curve, bzs [][]byte) error {
+	P := ec.Params().P
+	for _, bz := range bzs {
+		if len(bz) > len(P.Bytes()) || new(big.Int).SetBytes(bz).Cmp(P) >= 0 {
+			return errors.New("coordinate is not a field element")
+		}
+	}
+	return nil
+}
+
+func checkTraceContext(carrier map[string]string) error {
+	if len(carrier) > maxTraceContextEntries {
+		return fmt.Errorf("trace context has more than %d entries", maxTraceContextEntries)
+	}
+	for k, v := range carrier {
+		if len(k) > maxTraceContextBytes || len(v) > maxTraceContextBytes {
+			return fmt.Errorf("trace context entries are limited to %d bytes", maxTraceContextBytes)
+		}
+	}
+	return nil
 }
 
 // ----- //
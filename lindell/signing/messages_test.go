@@ -0,0 +1,285 @@
+package signing
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/zkp"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	testFirstMsg = `{"d_log_proof":{"a1":{"curve":"secp256k1","point":[3,136,247,183,232,115,188,25,145,204,55,120,250,204,89,120,12,119,194,124,111,65,47,224,251,220,166,107,122,82,84,122,215]},"a2":{"curve":"secp256k1","point":[2,39,4,146,3,67,171,144,59,165,241,26,141,216,97,149,88,133,106,200,122,54,153,87,76,75,175,70,1,222,93,16,144]},"z":{"curve":"secp256k1","scalar":[153,114,59,70,62,131,43,140,189,170,249,74,205,38,159,91,51,112,37,2,20,148,14,203,36,207,234,117,11,20,167,32]}},"public_share":{"curve":"secp256k1","point":[3,46,2,38,172,180,169,237,145,254,125,231,113,106,203,247,232,226,189,92,156,2,98,210,232,6,153,182,240,150,199,111,27]},"c":{"curve":"secp256k1","point":[2,221,29,155,224,53,30,14,174,86,233,148,175,70,81,225,122,22,132,251,190,11,154,191,125,203,5,199,99,12,31,185,54]}}`
+	testRst      = `{"eph_party_two_first_message":{"pk_commitment":"66986376533250027827140837758455093186138254236878683000153115076246179455047","zk_pok_commitment":"38583635392182058497600735620920896690053286875126645934235864970928281739516"},"eph_party_two_second_message":{"comm_witness":{"pk_commitment_blind_factor":"96324591265635270245697217666018851113014093323644433759649116505854915284243","zk_pok_blind_factor":"43537797225161695293523913418531901607310899769913854158667686968069669816015","public_share":{"curve":"secp256k1","point":[3,242,10,189,39,105,28,166,245,176,232,3,19,134,157,18,136,227,16,215,111,101,109,144,56,102,27,162,62,1,90,22,117]},"d_log_proof":{"a1":{"curve":"secp256k1","point":[3,107,184,90,137,204,9,73,172,67,155,55,15,228,84,216,43,24,32,42,93,115,173,147,41,45,222,147,116,246,13,120,91]},"a2":{"curve":"secp256k1","point":[3,100,96,29,212,52,216,48,43,155,94,110,6,184,230,29,88,77,242,78,178,142,243,48,245,77,190,134,108,62,237,178,84]},"z":{"curve":"secp256k1","scalar":[210,143,194,104,243,164,37,213,187,185,167,135,129,196,211,57,78,98,161,144,176,219,27,14,234,109,108,165,124,199,24,8]}},"c":{"curve":"secp256k1","point":[3,201,83,107,54,93,134,126,133,5,53,16,174,133,138,133,247,208,237,74,138,108,140,128,89,174,162,232,171,235,140,127,164]}}},"partial_sig":{"c3":"16052707203871319086327000321898233152450951327132207413797997464361528780214340735220411164838518209513"}}`
+)
+
+// testPayloads returns testFirstMsg and testRst decoded
+func testPayloads() (ffi.EphKeyGenFirstMsg, ffi.Round2Result) {
+	var msg1 ffi.EphKeyGenFirstMsg
+	var rst2 ffi.Round2Result
+	if err := json.Unmarshal([]byte(testFirstMsg), &msg1); err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(testRst), &rst2); err != nil {
+		panic(err)
+	}
+	return msg1, rst2
+}
+
+// withUnknownField appends a field no version of the schema has to m
+func withUnknownField(m proto.Message) {
+	r := m.ProtoReflect()
+	r.SetUnknown(protowire.AppendBytes(protowire.AppendTag(r.GetUnknown(), 99, protowire.BytesType), []byte("k1")))
+}
+
+// testRound1Message builds the server's round 1 message over the keygen fixtures, with proofs that are well formed
+// though not bound to testFirstMsg
+func testRound1Message(adaptor bool) (*SignRound1Message, *big.Int, error) {
+	ec := tss.S256()
+	keys, pIDs, err := LoadKeygenTestFixtures(2)
+	if err != nil {
+		return nil, nil, err
+	}
+	server, client := keys[0], keys[1]
+	pk := &server.PaillierSK.PublicKey
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	c, r, err := pk.EncryptAndReturnRandomness(x)
+	if err != nil {
+		return nil, nil, err
+	}
+	pdl, err := zkp.NewPDLwSlackProof(ec, pk, c, crypto.ScalarBaseMult(ec, x), client.NTildei, client.H1i, client.H2i, x, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	modulusProof, err := zkp.NewPaillierModulusProof(server.PaillierSK, server.Ks[0], server.ECDSAPub)
+	if err != nil {
+		return nil, nil, err
+	}
+	var share *crypto.ECPoint
+	var shareProof *zkp.DLEQProof
+	if adaptor {
+		G := crypto.ScalarBaseMult(ec, big.NewInt(1))
+		Y := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+		k := common.GetRandomPositiveInt(ec.Params().N)
+		share = Y.ScalarMult(k)
+		if shareProof, err = zkp.NewDLEQProof(k, G, crypto.ScalarBaseMult(ec, k), Y, share); err != nil {
+			return nil, nil, err
+		}
+	}
+	msg1, _ := testPayloads()
+	msg := NewSignRound1Message(pIDs[0], pk.N, c, msg1, pdl, modulusProof, share, shareProof,
+		map[string]string{"mockpfx-ids-traceid": "1"})
+	return msg.Content().(*SignRound1Message), pk.N, nil
+}
+
+func TestSignRound1MessageValidate(t *testing.T) {
+	ec := tss.S256()
+	valid, N, err := testRound1Message(true)
+	skipWithoutFixtures(t, err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, valid.Validate(ec))
+	withoutAdaptor, _, err := testRound1Message(false)
+	assert.NoError(t, err)
+	assert.NoError(t, withoutAdaptor.Validate(ec))
+
+	NSq := new(big.Int).Mul(N, N)
+	P := ec.Params().P
+	for name, mutate := range map[string]func(m *SignRound1Message){
+		"even modulus":         func(m *SignRound1Message) { m.N = new(big.Int).Add(N, big.NewInt(1)).Bytes() },
+		"short modulus":        func(m *SignRound1Message) { m.N = m.N[1:] },
+		"long modulus":         func(m *SignRound1Message) { m.N = append(append([]byte{1}, m.N...), m.N...) },
+		"padded modulus":       func(m *SignRound1Message) { m.N = append([]byte{0}, m.N...) },
+		"share above N²":       func(m *SignRound1Message) { m.Share = new(big.Int).Add(NSq, big.NewInt(1)).Bytes() },
+		"share sharing N":      func(m *SignRound1Message) { m.Share = N.Bytes() },
+		"zero share":           func(m *SignRound1Message) { m.Share = []byte{0} },
+		"padded share":         func(m *SignRound1Message) { m.Share = append([]byte{0}, m.Share...) },
+		"unknown field":        func(m *SignRound1Message) { withUnknownField(m) },
+		"unknown nested field": func(m *SignRound1Message) { withUnknownField(m.FirstMsg.DLogProof) },
+		"missing first msg":    func(m *SignRound1Message) { m.FirstMsg = nil },
+		"missing proof":        func(m *SignRound1Message) { m.FirstMsg.DLogProof = nil },
+		"short public share":   func(m *SignRound1Message) { m.FirstMsg.PublicShare = m.FirstMsg.PublicShare[1:] },
+		"scalar above order":   func(m *SignRound1Message) { m.FirstMsg.DLogProof.Z = P.Bytes() },
+		"pdl part too long":    func(m *SignRound1Message) { m.PdlProof[3] = make([]byte, maxProofPartBytes+1) },
+		"pdl point off field": func(m *SignRound1Message) {
+			m.PdlProof[1] = new(big.Int).Add(P, new(big.Int).SetBytes(m.PdlProof[1])).Bytes()
+		},
+		"pdl point off curve": func(m *SignRound1Message) { m.PdlProof[2] = big.NewInt(1).Bytes() },
+		"missing pdl part":    func(m *SignRound1Message) { m.PdlProof = m.PdlProof[:zkp.PDLwSlackProofBytesParts-1] },
+		"paillier proof part": func(m *SignRound1Message) { m.PaillierProof[0] = append(m.PaillierProof[0], m.N...) },
+		"adaptor share":       func(m *SignRound1Message) { m.AdaptorShare[1] = big.NewInt(2).Bytes() },
+		"adaptor proof point": func(m *SignRound1Message) { m.AdaptorProof[0] = P.Bytes() },
+		"adaptor proof response": func(m *SignRound1Message) {
+			m.AdaptorProof[4] = ec.Params().N.Bytes()
+		},
+		"half an adaptor": func(m *SignRound1Message) { m.AdaptorProof = nil },
+		"trace context": func(m *SignRound1Message) {
+			m.TraceContext = map[string]string{"k": strings.Repeat("v", maxTraceContextBytes+1)}
+		},
+	} {
+		m := proto.Clone(valid).(*SignRound1Message)
+		mutate(m)
+		assert.Error(t, m.Validate(ec), name)
+	}
+}
+
+func TestSignRound2MessageValidate(t *testing.T) {
+	ec := tss.S256()
+	pIDs := tss.GenerateTestPartyIDs(2)
+	_, rst2 := testPayloads()
+	// a random odd N, but one the fixed partial signature is a unit modulo
+	c3 := ffi.Str2BigInt(rst2.PartialSig.C3)
+	N := new(big.Int)
+	for N.Bit(0) == 0 || new(big.Int).GCD(nil, nil, c3, N).Cmp(big.NewInt(1)) != 0 {
+		N.SetBytes(common.MustGetRandomInt(zkp.MinPaillierModulusBitLen).Bytes())
+	}
+	valid := NewSignRound2Message(pIDs[1], rst2, nil).Content().(*SignRound2Message)
+	assert.NoError(t, valid.Validate(ec, nil))
+	assert.NoError(t, valid.Validate(ec, N))
+	assert.Error(t, valid.Validate(ec, big.NewInt(1000003)), "the partial signature must be below N²")
+
+	for name, mutate := range map[string]func(rst *Round2Result){
+		"unknown field":     func(rst *Round2Result) { withUnknownField(rst.CommWitness) },
+		"commitment range":  func(rst *Round2Result) { rst.FirstMsg.PkCommitment = append(rst.FirstMsg.PkCommitment, 1) },
+		"padded commitment": func(rst *Round2Result) { rst.FirstMsg.PkCommitment = append([]byte{0}, rst.FirstMsg.PkCommitment...) },
+		"point off curve":   func(rst *Round2Result) { rst.CommWitness.PublicShare[3] = 191 },
+		"missing witness":   func(rst *Round2Result) { rst.CommWitness = nil },
+		"padded ciphertext": func(rst *Round2Result) { rst.PartialSig.C3 = append([]byte{0}, rst.PartialSig.C3...) },
+		"zero ciphertext":   func(rst *Round2Result) { rst.PartialSig = nil },
+		"long ciphertext":   func(rst *Round2Result) { rst.PartialSig.C3 = make([]byte, maxCiphertextBytes+1) },
+		"bad adaptor nonce": func(rst *Round2Result) { rst.AdaptorNonce = &AdaptorNonce{} },
+	} {
+		m := proto.Clone(valid).(*SignRound2Message)
+		mutate(m.Rst)
+		assert.Error(t, m.Validate(ec, N), name)
+	}
+	assert.Error(t, (&SignRound2Message{}).Validate(ec, nil), "a message without a result must not validate")
+}
+
+func TestPayloadsRoundTrip(t *testing.T) {
+	msg1, rst2 := testPayloads()
+	rst2.AdaptorNonce = &ffi.AdaptorNonce{
+		RHat:  msg1.PublicShare,
+		R:     msg1.C,
+		Proof: ffi.DLEqProof(msg1.DLogProof),
+	}
+	curve := ffi.CurveSecp256k1
+	assert.Equal(t, msg1, newFirstMsgPayload(msg1).toFFI(curve), "lindellcore must get back exactly what it sent")
+	assert.Equal(t, rst2, newRound2ResultPayload(rst2).toFFI(curve), "lindellcore must get back exactly what it sent")
+
+	// and the typed payloads are far smaller than lindellcore's JSON
+	assert.Less(t, proto.Size(newFirstMsgPayload(msg1))*3, len(testFirstMsg))
+	assert.Less(t, proto.Size(newRound2ResultPayload(rst2))*2, len(testRst))
+}
+
+func TestInvalidMessageBlamesItsSender(t *testing.T) {
+	p, _ := newTestParty(t, big.NewInt(42), nil)
+	p.temp.log = zap.NewNop()
+	pIDs := p.params.Parties().IDs()
+	_, rst2 := testPayloads()
+	rst2.EphPartyTwoSecondMessage.CommWitness.C = ffi.Point{}
+	ok, err := p.Update(NewSignRound2Message(pIDs[1], rst2, nil))
+	assert.False(t, ok)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, []*tss.PartyID{pIDs[1]}, err.Culprits())
+	}
+	assert.Nil(t, p.temp.signRound2Messages[1], "an invalid message is never stored")
+}
+
+func TestOversizedWireMessageRejected(t *testing.T) {
+	p, _ := newTestParty(t, big.NewInt(42), nil)
+	pIDs := p.params.Parties().IDs()
+	ok, err := p.UpdateFromBytes(make([]byte, MaxWireBytes+1), pIDs[1], true)
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+}
+
+func TestDuplicateMessageIgnored(t *testing.T) {
+	p := newFuzzParty(t, RoleServer)
+	pIDs := p.params.Parties().IDs()
+	_, rst2 := testPayloads()
+	first := NewSignRound2Message(pIDs[1], rst2, nil).(tss.ParsedMessage)
+	ok, err := p.StoreMessage(first)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	// the same message again, e.g. resent by the transport, is no error and leaves the stored one alone
+	ok, err = p.StoreMessage(NewSignRound2Message(pIDs[1], rst2, nil).(tss.ParsedMessage))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Same(t, first, p.temp.signRound2Messages[1])
+
+	// nor is a copy whose trace context a relay rewrote
+	ok, err = p.StoreMessage(NewSignRound2Message(pIDs[1], rst2, map[string]string{"k": "v"}).(tss.ParsedMessage))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Same(t, first, p.temp.signRound2Messages[1])
+}
+
+func TestEquivocationBlamesItsSender(t *testing.T) {
+	t.Run("round 1", func(t *testing.T) {
+		p := newFuzzParty(t, RoleClient)
+		pIDs := p.params.Parties().IDs()
+		m, N, err := testRound1Message(false)
+		skipWithoutFixtures(t, err)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta := tss.MessageRouting{From: pIDs[0], IsBroadcast: true}
+		ok, tssErr := p.StoreMessage(tss.NewMessage(meta, m, tss.NewMessageWrapper(meta, m)))
+		assert.True(t, ok)
+		assert.Nil(t, tssErr)
+
+		// the same server encrypting another share
+		other := proto.Clone(m).(*SignRound1Message)
+		c, err := (&paillier.PublicKey{N: N}).Encrypt(common.GetRandomPositiveInt(tss.S256().Params().N))
+		assert.NoError(t, err)
+		other.Share = c.Bytes()
+		ok, tssErr = p.StoreMessage(tss.NewMessage(meta, other, tss.NewMessageWrapper(meta, other)))
+		assert.False(t, ok)
+		if assert.NotNil(t, tssErr) {
+			assert.ErrorIs(t, tssErr, ErrEquivocation)
+			assert.Equal(t, []*tss.PartyID{pIDs[0]}, tssErr.Culprits())
+		}
+		assert.Same(t, m, p.temp.signRound1Messages[0].Content(), "the first message stays")
+	})
+
+	t.Run("round 2", func(t *testing.T) {
+		p := newFuzzParty(t, RoleServer)
+		pIDs := p.params.Parties().IDs()
+		_, rst2 := testPayloads()
+		ok, err := p.Update(NewSignRound2Message(pIDs[1], rst2, nil).(tss.ParsedMessage))
+		assert.True(t, ok)
+		assert.Nil(t, err)
+
+		rst2.PartialSig.C3 = "12345"
+		ok, err = p.Update(NewSignRound2Message(pIDs[1], rst2, nil).(tss.ParsedMessage))
+		assert.False(t, ok)
+		if assert.NotNil(t, err) {
+			assert.ErrorIs(t, err, ErrEquivocation)
+			assert.Equal(t, []*tss.PartyID{pIDs[1]}, err.Culprits())
+		}
+		assert.True(t, p.temp.hasEnded(), "equivocation fails the session")
+	})
+}
+
+func TestMessageFromOwnIndexRejected(t *testing.T) {
+	p := newFuzzParty(t, RoleClient)
+	_, rst2 := testPayloads()
+	ok, err := p.StoreMessage(NewSignRound2Message(p.PartyID(), rst2, nil).(tss.ParsedMessage))
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+	assert.Nil(t, p.temp.signRound2Messages[p.PartyID().Index])
+}
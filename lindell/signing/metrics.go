@@ -0,0 +1,84 @@
+package signing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-rust/lindell/metrics"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// sessionMetrics reports one session to the parameters' Metrics. The outcome is reported once, whichever of
+// success, a protocol error or an abort by the session's driver comes first.
+type sessionMetrics struct {
+	m       metrics.Metrics
+	role    string
+	round   int32 // the round last started
+	started int32
+	done    sync.Once
+}
+
+func newSessionMetrics(params *LindellSignParameters) *sessionMetrics {
+	return &sessionMetrics{m: params.Metrics(), role: params.Role().String()}
+}
+
+func (s *sessionMetrics) start() {
+	if atomic.CompareAndSwapInt32(&s.started, 0, 1) {
+		s.m.SessionStarted(s.role)
+	}
+}
+
+// enterRound is deferred by a round's Start: defer s.enterRound(n)()
+func (s *sessionMetrics) enterRound(round int) func() {
+	atomic.StoreInt32(&s.round, int32(round))
+	begin := time.Now()
+	return func() { s.m.ObserveRound(s.role, round, time.Since(begin)) }
+}
+
+func (s *sessionMetrics) observeCall(call string, begin time.Time) {
+	s.m.ObserveCall(call, time.Since(begin))
+}
+
+func (s *sessionMetrics) succeed() {
+	s.done.Do(func() { s.m.SessionSucceeded(s.role) })
+}
+
+func (s *sessionMetrics) fail(round int, reason string) {
+	if atomic.LoadInt32(&s.started) == 0 {
+		return
+	}
+	s.done.Do(func() { s.m.SessionFailed(s.role, round, reason) })
+}
+
+// failed reports err returned by Start or Update, if any, and returns it unchanged
+func (s *sessionMetrics) failed(err *tss.Error) *tss.Error {
+	if err == nil {
+		return nil
+	}
+	reason := metrics.ReasonLocal
+	if len(err.Culprits()) > 0 {
+		reason = metrics.ReasonPeer
+	}
+	s.fail(err.Round(), reason)
+	return err
+}
+
+func (s *sessionMetrics) aborted(err error) {
+	reason := metrics.ReasonTransport
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		reason = metrics.ReasonTimeout
+	case errors.Is(err, context.Canceled):
+		reason = metrics.ReasonCanceled
+	}
+	s.fail(s.currentRound(), reason)
+}
+
+// currentRound is the round last started, 0 before round 1
+func (s *sessionMetrics) currentRound() int {
+	return int(atomic.LoadInt32(&s.round))
+}
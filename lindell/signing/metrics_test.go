@@ -0,0 +1,85 @@
+package signing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-rust/lindell/metrics"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedMetrics struct {
+	mtx    sync.Mutex
+	events []string
+}
+
+func (r *recordedMetrics) record(format string, args ...interface{}) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recordedMetrics) ObserveRound(role string, round int, _ time.Duration) {
+	r.record("round %s %d", role, round)
+}
+func (r *recordedMetrics) ObserveCall(call string, _ time.Duration) { r.record("call %s", call) }
+func (r *recordedMetrics) SessionStarted(role string)               { r.record("started %s", role) }
+func (r *recordedMetrics) SessionSucceeded(role string)             { r.record("succeeded %s", role) }
+func (r *recordedMetrics) SessionFailed(role string, round int, reason string) {
+	r.record("failed %s %d %s", role, round, reason)
+}
+
+func TestSessionMetricsOutcomeReportedOnce(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	for _, tc := range []struct {
+		name string
+		end  func(s *sessionMetrics)
+		want string
+	}{
+		{"peer", func(s *sessionMetrics) {
+			s.failed(tss.NewError(errors.New("bad proof"), TaskName, 2, pIDs[1], pIDs[0]))
+		}, "failed client 2 peer"},
+		{"local", func(s *sessionMetrics) {
+			s.failed(tss.NewError(errors.New("bad input"), TaskName, 1, pIDs[1]))
+		}, "failed client 1 local"},
+		{"timeout", func(s *sessionMetrics) {
+			s.aborted(fmt.Errorf("waiting: %w", context.DeadlineExceeded))
+		}, "failed client 2 timeout"},
+		{"canceled", func(s *sessionMetrics) { s.aborted(context.Canceled) }, "failed client 2 canceled"},
+		{"transport", func(s *sessionMetrics) { s.aborted(errors.New("the peer closed the session")) }, "failed client 2 transport"},
+		{"success", func(s *sessionMetrics) { s.succeed() }, "succeeded client"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := new(recordedMetrics)
+			s := &sessionMetrics{m: rec, role: RoleClient.String()}
+			s.fail(1, metrics.ReasonLocal) // not started yet, not reported
+
+			s.start()
+			s.enterRound(1)()
+			s.enterRound(2)()
+			tc.end(s)
+			// whatever comes after the outcome is not reported again
+			s.succeed()
+			s.aborted(context.Canceled)
+			assert.Nil(t, s.failed(nil))
+
+			assert.Equal(t, []string{"started client", "round client 1", "round client 2", tc.want}, rec.events)
+		})
+	}
+}
+
+func TestMetricsDefaultToNop(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params, err := NewLindellSignParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, metrics.Nop{}, params.Metrics())
+	prom := metrics.NewPrometheus()
+	params.SetMetrics(prom)
+	assert.Equal(t, prom, params.Metrics())
+}
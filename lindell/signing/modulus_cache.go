@@ -0,0 +1,52 @@
+package signing
+
+import (
+	"encoding/hex"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+// PaillierModulusCache remembers the server's Paillier modulus once the client has verified its proof,
+// so the proof is checked once per key and a changed modulus is noticed on every later session.
+type PaillierModulusCache struct {
+	mtx    sync.RWMutex
+	moduli map[string]*big.Int
+}
+
+// DefaultPaillierModulusCache is shared by every client in the process whose parameters have no cache of their own,
+// see LindellSignParameters.SetPaillierModulusCache. It lives as long as the process; a client that relies on it and
+// learns that the server restored its share must call its Forget, or the new modulus is rejected.
+var DefaultPaillierModulusCache = NewPaillierModulusCache()
+
+func NewPaillierModulusCache() *PaillierModulusCache {
+	return &PaillierModulusCache{moduli: make(map[string]*big.Int)}
+}
+
+func (c *PaillierModulusCache) Lookup(keyID string) (*big.Int, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	N, ok := c.moduli[keyID]
+	return N, ok
+}
+
+func (c *PaillierModulusCache) Store(keyID string, N *big.Int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.moduli[keyID] = new(big.Int).Set(N)
+}
+
+// Forget drops the modulus verified for keyID, so a server whose share was restored with a new Paillier key can be
+// verified afresh
+func (c *PaillierModulusCache) Forget(keyID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.moduli, keyID)
+}
+
+// PaillierModulusKeyID identifies the server's Paillier key by the joint public key and the server's party key
+func PaillierModulusKeyID(ecdsaPub *crypto.ECPoint, serverKey *big.Int) string {
+	return hex.EncodeToString(common.SHA512_256i(ecdsaPub.X(), ecdsaPub.Y(), serverKey).Bytes())
+}
@@ -0,0 +1,119 @@
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	"go-rust/lindell/zkp"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// modulusRound returns round 2 of the fixtures' client, checking the server's modulus against cache, and the server's
+// round 1 message with a valid modulus proof
+func modulusRound(t *testing.T, cache *PaillierModulusCache) (*round2, *SignRound1Message, []keygen.LocalPartySaveData) {
+	m, _, err := testRound1Message(false)
+	skipWithoutFixtures(t, err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, pIDs, err := LoadKeygenTestFixtures(3)
+	skipWithoutFixtures(t, err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := NewLindellSignParametersWithRole(tss.S256(), tss.NewPeerContext(pIDs[:2]), pIDs[1], 2, 1, RoleClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.SetPaillierModulusCache(cache)
+	round := &round2{&round1{&base{LindellSignParameters: params, key: &keys[1]}}}
+	return round, m, keys
+}
+
+func TestServerModulusProvenOncePerKey(t *testing.T) {
+	cache := NewPaillierModulusCache()
+	round, m, keys := modulusRound(t, cache)
+	assert.NoError(t, round.verifyServerModulus(0, m))
+	keyID := PaillierModulusKeyID(keys[0].ECDSAPub, keys[0].Ks[0])
+	N, ok := cache.Lookup(keyID)
+	assert.True(t, ok, "a verified modulus is cached")
+	assert.Zero(t, N.Cmp(m.UnmarshalN()))
+
+	// a later session of the same key is not proven again
+	later := proto.Clone(m).(*SignRound1Message)
+	later.PaillierProof = nil
+	assert.NoError(t, round.verifyServerModulus(0, later))
+	later.PaillierProof = [][]byte{{1}}
+	assert.NoError(t, round.verifyServerModulus(0, later), "the proof is not looked at on a cache hit")
+}
+
+func TestUnprovenServerModulusRejected(t *testing.T) {
+	cache := NewPaillierModulusCache()
+	round, m, keys := modulusRound(t, cache)
+	unproven := proto.Clone(m).(*SignRound1Message)
+	unproven.PaillierProof = nil
+	assert.Error(t, round.verifyServerModulus(0, unproven))
+
+	// a proof for another key
+	pf, err := zkp.NewPaillierModulusProof(keys[0].PaillierSK, keys[0].Ks[1], keys[0].ECDSAPub)
+	assert.NoError(t, err)
+	unproven.PaillierProof = common.BigIntsToBytes(pf[:])
+	assert.Error(t, round.verifyServerModulus(0, unproven))
+
+	_, ok := cache.Lookup(PaillierModulusKeyID(keys[0].ECDSAPub, keys[0].Ks[0]))
+	assert.False(t, ok, "nothing is cached for a rejected modulus")
+}
+
+// restoredServer returns m sent by a server whose Paillier key was replaced, with a valid proof for the new modulus
+func restoredServer(t *testing.T, m *SignRound1Message, keys []keygen.LocalPartySaveData) *SignRound1Message {
+	sk := keys[2].PaillierSK
+	pf, err := zkp.NewPaillierModulusProof(sk, keys[0].Ks[0], keys[0].ECDSAPub)
+	assert.NoError(t, err)
+	restored := proto.Clone(m).(*SignRound1Message)
+	restored.N = sk.N.Bytes()
+	restored.PaillierProof = common.BigIntsToBytes(pf[:])
+	return restored
+}
+
+func TestChangedServerModulusRejected(t *testing.T) {
+	round, m, keys := modulusRound(t, NewPaillierModulusCache())
+	assert.NoError(t, round.verifyServerModulus(0, m))
+	assert.Error(t, round.verifyServerModulus(0, restoredServer(t, m, keys)), "even a proven modulus must match the cached one")
+
+	changed := proto.Clone(m).(*SignRound1Message)
+	changed.N = new(big.Int).Add(m.UnmarshalN(), big.NewInt(2)).Bytes()
+	assert.Error(t, round.verifyServerModulus(0, changed))
+}
+
+func TestForgottenServerModulusProvenAgain(t *testing.T) {
+	cache := NewPaillierModulusCache()
+	round, m, keys := modulusRound(t, cache)
+	assert.NoError(t, round.verifyServerModulus(0, m))
+	keyID := PaillierModulusKeyID(keys[0].ECDSAPub, keys[0].Ks[0])
+	otherID := PaillierModulusKeyID(keys[0].ECDSAPub, keys[0].Ks[1])
+	cache.Store(otherID, big.NewInt(35))
+
+	cache.Forget(keyID)
+	_, ok := cache.Lookup(keyID)
+	assert.False(t, ok)
+	_, ok = cache.Lookup(otherID)
+	assert.True(t, ok, "only the given key is forgotten")
+
+	unproven := proto.Clone(m).(*SignRound1Message)
+	unproven.PaillierProof = nil
+	assert.Error(t, round.verifyServerModulus(0, unproven), "a forgotten modulus must be proven again")
+	restored := restoredServer(t, m, keys)
+	assert.NoError(t, round.verifyServerModulus(0, restored), "the restored server's new modulus is accepted")
+	N, _ := cache.Lookup(keyID)
+	assert.Zero(t, N.Cmp(restored.UnmarshalN()))
+}
+
+func TestDefaultPaillierModulusCache(t *testing.T) {
+	round, _, _ := modulusRound(t, nil)
+	assert.Same(t, DefaultPaillierModulusCache, round.PaillierModulusCache())
+}
@@ -0,0 +1,103 @@
+package signing
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"go-rust/lindell/transport/netsim"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// runNetwork signs 42 with the fixtures through a simulated network with cfg, starting party i after startAfter[i],
+// and returns the network, the parties and what each of them output on end, nil if it did not finish
+func runNetwork(t *testing.T, cfg netsim.Config, startAfter ...time.Duration) (*netsim.Network, []*LocalParty,
+	[]*common.SignatureData, []keygen.LocalPartySaveData) {
+	keys, signPIDs, err := LoadKeygenTestFixtures(2)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	net, err := netsim.New(cfg, signPIDs)
+	assert.NoError(t, err)
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	out := make(chan tss.Message, len(signPIDs))
+	parties := make([]*LocalParty, len(signPIDs))
+	ends := make([]chan common.SignatureData, len(signPIDs))
+	for i := range signPIDs {
+		params, err := NewLindellSignParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), 1)
+		assert.NoError(t, err, "should build signing params")
+		params.SetLogger(zap.NewNop())
+		ends[i] = make(chan common.SignatureData, 1)
+		parties[i] = NewLocalParty(big.NewInt(42), params, keys[i], out, ends[i]).(*LocalParty)
+		var delay time.Duration
+		if i < len(startAfter) {
+			delay = startAfter[i]
+		}
+		assert.NoError(t, net.Add(parties[i], out, delay))
+	}
+	assert.NoError(t, net.Run())
+
+	results := make([]*common.SignatureData, len(signPIDs))
+	for i, end := range ends {
+		select {
+		case <-end:
+			// what the party output on end, receiving it would copy its lock
+			results[i] = &parties[i].data
+		default:
+		}
+	}
+	return net, parties, results, keys
+}
+
+func TestE2ENetwork(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg        netsim.Config
+		startAfter []time.Duration
+	}{
+		"latency":   {cfg: netsim.Config{MinLatency: 5 * time.Millisecond, MaxLatency: 50 * time.Millisecond}},
+		"reordered": {cfg: netsim.Config{MaxLatency: 50 * time.Millisecond, Reorder: 0.5}},
+		// every message arrives a second time, the copy often after the session ended
+		"duplicated": {cfg: netsim.Config{MaxLatency: 50 * time.Millisecond, Duplicate: 1}},
+		// a party handed its peer's message before it started
+		"party 0 started late": {cfg: netsim.Config{MaxLatency: time.Millisecond}, startAfter: []time.Duration{time.Second}},
+		"party 1 started late": {cfg: netsim.Config{MaxLatency: time.Millisecond}, startAfter: []time.Duration{0, time.Second}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for seed := int64(0); seed < 4; seed++ {
+				tc.cfg.Seed = seed
+				net, parties, results, keys := runNetwork(t, tc.cfg, tc.startAfter...)
+				for i, P := range parties {
+					if !assert.NotNil(t, results[i], "seed %d: party %d must finish", seed, i) {
+						continue
+					}
+					if !P.params.IsServer() {
+						assert.Nil(t, results[i].GetR(), "the client outputs no signature")
+						continue
+					}
+					pk := ecdsa.PublicKey{Curve: tss.S256(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+					assert.True(t, ecdsa.Verify(&pk, big.NewInt(42).Bytes(),
+						new(big.Int).SetBytes(results[i].GetR()), new(big.Int).SetBytes(results[i].GetS())),
+						"ecdsa verify must pass")
+				}
+				if tc.cfg.Duplicate == 1 {
+					assert.Equal(t, 2*net.Stats().Sent, net.Stats().Delivered, "duplicates must be delivered")
+				}
+			}
+		})
+	}
+}
+
+func TestE2ENetworkLoss(t *testing.T) {
+	net, parties, results, _ := runNetwork(t, netsim.Config{Loss: 1})
+	assert.Equal(t, net.Stats().Sent, net.Stats().Lost)
+	for i, P := range parties {
+		assert.Nil(t, results[i], "party %d cannot finish without its peer's message", i)
+		assert.Len(t, P.WaitingFor(), 1)
+	}
+}
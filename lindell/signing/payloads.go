@@ -0,0 +1,149 @@
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go-rust/lindell/ffi"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The protobuf payloads of the signing messages carry lindellcore's types without their JSON: points are SEC1
+// compressed, scalars keep lindellcore's bytes and its decimal numbers become big-endian bytes. The curve is the
+// session's, so it is not sent.
+
+func newFirstMsgPayload(msg ffi.EphKeyGenFirstMsg) *EphKeyGenFirstMsg {
+	return &EphKeyGenFirstMsg{
+		DLogProof:   newProofPayload(msg.DLogProof.A1, msg.DLogProof.A2, msg.DLogProof.Z),
+		PublicShare: ffi.Uint2Byte(msg.PublicShare.Point),
+		C:           ffi.Uint2Byte(msg.C.Point),
+	}
+}
+
+func newRound2ResultPayload(rst ffi.Round2Result) *Round2Result {
+	first, witness := rst.EphPartyTwoFirstMessage, rst.EphPartyTwoSecondMessage.CommWitness
+	payload := &Round2Result{
+		FirstMsg: &PartyTwoEphKeyGenFirstMsg{
+			PkCommitment:    ffi.Str2BigInt(first.PkCommitment).Bytes(),
+			ZkPokCommitment: ffi.Str2BigInt(first.ZkPokCommitment).Bytes(),
+		},
+		CommWitness: &EphCommWitness{
+			PkCommitmentBlindFactor: ffi.Str2BigInt(witness.PkCommitmentBlindFactor).Bytes(),
+			ZkPokBlindFactor:        ffi.Str2BigInt(witness.ZkPokBlindFactor).Bytes(),
+			PublicShare:             ffi.Uint2Byte(witness.PublicShare.Point),
+			DLogProof:               newProofPayload(witness.DLogProof.A1, witness.DLogProof.A2, witness.DLogProof.Z),
+			C:                       ffi.Uint2Byte(witness.C.Point),
+		},
+		PartialSig: &PartialSig{C3: ffi.Str2BigInt(rst.PartialSig.C3).Bytes()},
+	}
+	if nonce := rst.AdaptorNonce; nonce != nil {
+		payload.AdaptorNonce = &AdaptorNonce{
+			RHat:  ffi.Uint2Byte(nonce.RHat.Point),
+			R:     ffi.Uint2Byte(nonce.R.Point),
+			Proof: newProofPayload(nonce.Proof.A1, nonce.Proof.A2, nonce.Proof.Z),
+		}
+	}
+	return payload
+}
+
+func newProofPayload(a1, a2 ffi.Point, z ffi.Scalar) *ECDDHProof {
+	return &ECDDHProof{A1: ffi.Uint2Byte(a1.Point), A2: ffi.Uint2Byte(a2.Point), Z: ffi.Uint2Byte(z.Scalar)}
+}
+
+// ----- //
+
+// toFFI returns the first message as lindellcore takes it, on the named curve. It does not validate it.
+func (m *EphKeyGenFirstMsg) toFFI(curve string) ffi.EphKeyGenFirstMsg {
+	proof := m.GetDLogProof()
+	return ffi.EphKeyGenFirstMsg{
+		DLogProof:   ffi.ECDDHProof{A1: point(curve, proof.GetA1()), A2: point(curve, proof.GetA2()), Z: scalar(curve, proof.GetZ())},
+		PublicShare: point(curve, m.GetPublicShare()),
+		C:           point(curve, m.GetC()),
+	}
+}
+
+// toFFI returns the client's output as lindellcore takes it, on the named curve. It does not validate it.
+func (m *Round2Result) toFFI(curve string) ffi.Round2Result {
+	first, witness := m.GetFirstMsg(), m.GetCommWitness()
+	proof := witness.GetDLogProof()
+	rst := ffi.Round2Result{
+		EphPartyTwoFirstMessage: ffi.PartyTwoEphKeyGenFirstMsg{
+			PkCommitment:    decimal(first.GetPkCommitment()),
+			ZkPokCommitment: decimal(first.GetZkPokCommitment()),
+		},
+		EphPartyTwoSecondMessage: ffi.EphKeyGenSecondMsg{CommWitness: ffi.EphCommWitness{
+			PkCommitmentBlindFactor: decimal(witness.GetPkCommitmentBlindFactor()),
+			ZkPokBlindFactor:        decimal(witness.GetZkPokBlindFactor()),
+			PublicShare:             point(curve, witness.GetPublicShare()),
+			DLogProof:               ffi.ECDDHProof{A1: point(curve, proof.GetA1()), A2: point(curve, proof.GetA2()), Z: scalar(curve, proof.GetZ())},
+			C:                       point(curve, witness.GetC()),
+		}},
+		PartialSig: ffi.PartialSig{C3: decimal(m.GetPartialSig().GetC3())},
+	}
+	if nonce := m.GetAdaptorNonce(); nonce != nil {
+		proof := nonce.GetProof()
+		rst.AdaptorNonce = &ffi.AdaptorNonce{
+			RHat:  point(curve, nonce.GetRHat()),
+			R:     point(curve, nonce.GetR()),
+			Proof: ffi.DLEqProof{A1: point(curve, proof.GetA1()), A2: point(curve, proof.GetA2()), Z: scalar(curve, proof.GetZ())},
+		}
+	}
+	return rst
+}
+
+// checkNumbers checks that the big-endian numbers of the client's output have a single encoding and fit their types
+func (m *Round2Result) checkNumbers(maxCiphertextBytes int) error {
+	first, witness := m.GetFirstMsg(), m.GetCommWitness()
+	for name, bz := range map[string][]byte{
+		"pk commitment":              first.GetPkCommitment(),
+		"zk pok commitment":          first.GetZkPokCommitment(),
+		"pk commitment blind factor": witness.GetPkCommitmentBlindFactor(),
+		"zk pok blind factor":        witness.GetZkPokBlindFactor(),
+	} {
+		if err := checkNumber(name, bz, 32); err != nil {
+			return err
+		}
+	}
+	return checkNumber("partial signature", m.GetPartialSig().GetC3(), maxCiphertextBytes)
+}
+
+// ----- //
+
+func point(curve string, bz []byte) ffi.Point {
+	return ffi.Point{Curve: curve, Point: ffi.Bytes2Uint(bz)}
+}
+
+func scalar(curve string, bz []byte) ffi.Scalar {
+	return ffi.Scalar{Curve: curve, Scalar: ffi.Bytes2Uint(bz)}
+}
+
+func decimal(bz []byte) string {
+	return new(big.Int).SetBytes(bz).String()
+}
+
+func checkNumber(name string, bz []byte, max int) error {
+	if len(bz) > max {
+		return fmt.Errorf("%s is longer than %d bytes", name, max)
+	}
+	if len(bz) > 0 && bz[0] == 0 {
+		return fmt.Errorf("%s has leading zeros", name)
+	}
+	return nil
+}
+
+// checkUnknownFields rejects fields this side does not know anywhere in m, so every payload has a single schema
+func checkUnknownFields(m protoreflect.Message) error {
+	if len(m.GetUnknown()) != 0 {
+		return errors.New("message has unknown fields")
+	}
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			err = checkUnknownFields(v.Message())
+		}
+		return err == nil
+	})
+	return err
+}
@@ -7,20 +7,24 @@
 package signing
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"go-rust/lindell/ffi"
+	"go-rust/lindell/metrics"
+	"go-rust/lindell/zeroize"
+	"go-rust/lindell/zkp"
 
 	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
-func newRound1(params *LindellSignParameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- common.SignatureData) tss.Round {
+func newRound1(params *LindellSignParameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- common.SignatureData, preEnd chan<- *PreSignature) tss.Round {
 	return &round1{
-		&base{params, key, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+		&base{params, key, data, temp, out, end, preEnd, make([]bool, len(params.Parties().IDs())), false, 1}}
 }
 
 func (round *round1) Start() *tss.Error {
@@ -35,30 +39,64 @@ func (round *round1) Start() *tss.Error {
 	if round.temp.m.Cmp(round.Params().EC().Params().N) >= 0 {
 		return round.WrapError(errors.New("hashed message is not valid"))
 	}
+	if round.isAdaptor() {
+		Y := round.temp.adaptorPoint
+		if Y == nil || !Y.ValidateBasic() || !Y.IsOnCurve() {
+			return round.WrapError(errors.New("adaptor point is not a valid curve point"))
+		}
+		if name, err := ffi.CurveName(Y.Curve()); err != nil || name != round.CurveName() {
+			return round.WrapError(errors.New("adaptor point is on a different curve"))
+		}
+	}
 
 	round.number = 1
 	round.started = true
 	round.resetOK()
+	defer round.enter(1)()
 
 	i := round.PartyID().Index
 	round.ok[i] = true
 
-	if !round.isServer {
+	if !round.IsServer() {
 		return nil
 	}
 
-	encryptedShare, _, err := round.key.PaillierSK.EncryptAndReturnRandomness(round.temp.secretShare)
+	done := round.timeCall(metrics.CallPaillierEncrypt)
+	encryptedShare, randomness, err := round.key.PaillierSK.EncryptAndReturnRandomness(round.temp.secretShare)
+	done()
+	if err != nil {
+		return round.WrapError(err)
+	}
+	// the randomness opens encryptedShare
+	defer zeroize.BigInt(randomness)
+
+	// prove to the client that encryptedShare holds the discrete log of our public share, against the client's
+	// ring-Pedersen parameters; otherwise a malicious server could learn the client's share from failed signatures
+	j := round.getOtherPartyId()
+	pdlProof, err := zkp.NewPDLwSlackProof(round.Params().EC(), &round.key.PaillierSK.PublicKey, encryptedShare,
+		crypto.ScalarBaseMult(round.Params().EC(), round.temp.secretShare),
+		round.key.NTildej[j], round.key.H1j[j], round.key.H2j[j], round.temp.secretShare, randomness)
+	if err != nil {
+		return round.WrapError(err)
+	}
+	paillierProof, err := zkp.NewPaillierModulusProof(round.key.PaillierSK, round.key.ShareID, round.key.ECDSAPub)
 	if err != nil {
 		return round.WrapError(err)
 	}
 
-	r1Rst := ffi.Round1()
-	round.temp.round1Rst = &r1Rst
-
-	firstMsg, err := json.Marshal(r1Rst.EphPartyOneFirstMessage)
+	done = round.timeCall(metrics.CallFFIRound1)
+	r1Rst, err := ffi.Round1(ffi.Round1Input{Curve: round.CurveName()})
+	done()
 	if err != nil {
 		return round.WrapError(err)
 	}
+	round.temp.round1Rst = &r1Rst
+
+	if round.isAdaptor() {
+		if err := round.adaptorNonceShare(); err != nil {
+			return round.WrapError(err)
+		}
+	}
 
 	//for _, Pj := range round.Parties().IDs() {
 	//	if j == i {
@@ -69,7 +107,8 @@ func (round *round1) Start() *tss.Error {
 	//	round.out <- r1msg
 	//}
 
-	r1msg := NewSignRound1Message(round.PartyID(), round.key.PaillierSK.PublicKey.N, encryptedShare, firstMsg)
+	r1msg := NewSignRound1Message(round.PartyID(), round.key.PaillierSK.PublicKey.N, encryptedShare, r1Rst.EphPartyOneFirstMessage, pdlProof, paillierProof,
+		round.temp.r1Y, round.temp.r1Proof, round.temp.tracing.inject())
 	round.out <- r1msg
 
 	// server auto advanced to next round
@@ -79,7 +118,8 @@ func (round *round1) Start() *tss.Error {
 }
 
 func (round *round1) Update() (bool, *tss.Error) {
-	if round.isServer {
+	defer round.temp.tracing.span("round1.update")()
+	if round.IsServer() {
 		round.setOK()
 		return true, nil
 	}
@@ -108,6 +148,25 @@ func (round *round1) NextRound() tss.Round {
 	return &round2{round}
 }
 
+// adaptorNonceShare computes the server's nonce share k1·Y and proves it has the same discrete log as k1·G
+func (round *round1) adaptorNonceShare() error {
+	ec := round.Params().EC()
+	k1 := round.temp.round1Rst.EphEcKeyPairParty1.SecretShare.BigInt()
+	defer zeroize.BigInt(k1)
+	R1, err := round.temp.round1Rst.EphPartyOneFirstMessage.PublicShare.ECPoint(ec)
+	if err != nil {
+		return err
+	}
+	Y := round.temp.adaptorPoint
+	R1Y := Y.ScalarMult(k1)
+	proof, err := zkp.NewDLEQProof(k1, crypto.ScalarBaseMult(ec, big.NewInt(1)), R1, Y, R1Y)
+	if err != nil {
+		return err
+	}
+	round.temp.r1, round.temp.r1Y, round.temp.r1Proof = R1, R1Y, proof
+	return nil
+}
+
 // ----- //
 
 // helper to call into PrepareForSigning()
@@ -124,14 +183,33 @@ func (round *round1) prepare() error {
 		mod := common.ModInt(round.Params().EC().Params().N)
 		xi = mod.Add(round.temp.keyDerivationDelta, xi)
 		round.key.Xi = xi
+		round.temp.derivedXi = xi
 	}
 
 	if round.Threshold()+1 > len(ks) {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
 	wi := PrepareForSigning(round.Params().EC(), i, len(ks), xi, ks)
+	if wi == xi {
+		// wi is wiped when the session ends, never the caller's key share
+		wi = new(big.Int).Set(xi)
+	}
 
 	round.temp.secretShare = wi
-	round.temp.publicShare = round.key.ECDSAPub.X() // todo: 设置的不对，需要重新设置
 	return nil
 }
+
+// peerPublicShare returns party j's weighted public share, i.e. the public counterpart of the wi that party j
+// computes for itself in prepare()
+func (round *round1) peerPublicShare(j int) (*crypto.ECPoint, error) {
+	ec := round.Params().EC()
+	Xj := round.key.BigXj[j]
+	if round.temp.keyDerivationDelta != nil {
+		var err error
+		if Xj, err = Xj.Add(crypto.ScalarBaseMult(ec, round.temp.keyDerivationDelta)); err != nil {
+			return nil, err
+		}
+	}
+	lambdaJ := PrepareForSigning(ec, j, len(round.key.Ks), big.NewInt(1), round.key.Ks)
+	return Xj.ScalarMult(lambdaJ), nil
+}
@@ -7,12 +7,15 @@
 package signing
 
 import (
-	"encoding/json"
 	"errors"
 	"math/big"
 
 	"go-rust/lindell/ffi"
+	"go-rust/lindell/metrics"
+	"go-rust/lindell/zkp"
 
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
@@ -23,46 +26,84 @@ func (round *round2) Start() *tss.Error {
 	round.number = 2
 	round.started = true
 	round.resetOK()
+	defer round.enter(2)()
 
 	i := round.PartyID().Index
 	round.ok[i] = true
 
-	if round.isServer {
+	if round.IsServer() {
 		return nil
 	}
 
-	r1msg := round.temp.signRound1Messages[round.getOtherPartyId()].Content().(*SignRound1Message)
-	var msg1 ffi.EphKeyGenFirstMsg
-	err := json.Unmarshal(r1msg.FirstMsg, &msg1)
+	j := round.getOtherPartyId()
+	Pj := round.Parties().IDs()[j]
+	r1msg := round.temp.signRound1Messages[j].Content().(*SignRound1Message)
+	round.temp.tracing.join(r1msg.GetTraceContext())
+
+	if err := round.verifyServerModulus(j, r1msg); err != nil {
+		return round.WrapError(err, Pj)
+	}
+
+	// never compute a partial signature before the server proved the encrypted share is the discrete log of its public share
+	pdlProof, err := r1msg.UnmarshalPDLwSlackProof(round.Params().EC())
+	if err != nil {
+		return round.WrapError(err, Pj)
+	}
+	serverShare, err := round.peerPublicShare(j)
 	if err != nil {
 		return round.WrapError(err)
 	}
+	serverPK := &paillier.PublicKey{N: r1msg.UnmarshalN()}
+	if !pdlProof.Verify(round.Params().EC(), serverPK, r1msg.UnmarshalShare(), serverShare,
+		round.key.NTildei, round.key.H1i, round.key.H2i) {
+		return round.WrapError(errors.New("failed to verify the PDL proof of the server's encrypted share"), Pj)
+	}
 
-	pubShare := make([]byte, 1, 33)
-	pubShare[0] = 3
-	pubShare = append(pubShare, round.temp.publicShare.Bytes()...)
+	msg1, err := r1msg.UnmarshalFirstMsg(round.Params().EC())
+	if err != nil {
+		return round.WrapError(err, Pj)
+	}
+
+	// this party's weighted share x2 and x2·G
+	ec := round.Params().EC()
+	pubShare, err := ffi.NewPoint(crypto.ScalarBaseMult(ec, round.temp.secretShare))
+	if err != nil {
+		return round.WrapError(err)
+	}
+	secretShare, err := ffi.NewScalar(ec, round.temp.secretShare)
+	if err != nil {
+		return round.WrapError(err)
+	}
 
 	input2 := ffi.Round2Input{
+		Curve:          round.CurveName(),
 		PaillierN:      new(big.Int).SetBytes(r1msg.N).String(),
 		EncryptedShare: new(big.Int).SetBytes(r1msg.Share).String(),
 		EcKeyPairParty2: ffi.EphEcKeyPair{
-			PublicShare: ffi.Point{
-				Curve: "secp256k1",
-				Point: ffi.Bytes2Uint(pubShare),
-			},
-			SecretShare: ffi.Scalar{
-				Curve:  "secp256k1",
-				Scalar: ffi.Bytes2Uint(round.temp.secretShare.Bytes()),
-			},
+			PublicShare: pubShare,
+			SecretShare: secretShare,
 		},
 		Message:                 round.temp.m.String(),
 		EphPartyOneFirstMessage: msg1,
 	}
+	defer input2.Zeroize()
+	if r1msg.HasAdaptorShare() != round.isAdaptor() {
+		return round.WrapError(errors.New("the server does not agree on signing in adaptor mode"), Pj)
+	}
+	if round.isAdaptor() {
+		adaptorShare, err := round.verifyAdaptorNonceShare(r1msg, msg1)
+		if err != nil {
+			return round.WrapError(err, Pj)
+		}
+		input2.AdaptorPoint = &adaptorShare
+	}
 
-	rst2 := ffi.Round2(input2)
-	rstData, err := json.Marshal(rst2)
+	done := round.timeCall(metrics.CallFFIRound2)
+	rst2, err := ffi.Round2(input2)
+	done()
 	if err != nil {
-		return round.WrapError(err)
+		// lindellcore only rejects the server's first message
+		return round.WrapError(err, Pj)
 	}
 
 	// create and send messages
@@ -74,7 +115,7 @@ func (round *round2) Start() *tss.Error {
 	//	round.out <- r2msg
 	//}
 
-	r2msg := NewSignRound2Message(round.PartyID(), rstData)
+	r2msg := NewSignRound2Message(round.PartyID(), rst2, round.temp.tracing.inject())
 	round.out <- r2msg
 
 	// client auto advanced to next round
@@ -83,8 +124,58 @@ func (round *round2) Start() *tss.Error {
 	return nil
 }
 
+// verifyServerModulus checks the server's Paillier modulus proof the first time a key is used and afterwards only
+// accepts the modulus that was verified for that key
+func (round *round2) verifyServerModulus(j int, r1msg *SignRound1Message) error {
+	N := r1msg.UnmarshalN()
+	serverKey := round.key.Ks[j]
+	cache := round.PaillierModulusCache()
+	keyID := PaillierModulusKeyID(round.key.ECDSAPub, serverKey)
+	if verified, ok := cache.Lookup(keyID); ok {
+		if verified.Cmp(N) != 0 {
+			return errors.New("the server's paillier modulus differs from the one verified for this key")
+		}
+		return nil
+	}
+	if !r1msg.HasPaillierProof() {
+		return errors.New("the server's paillier modulus has not been proven for this key")
+	}
+	pf, err := r1msg.UnmarshalPaillierProof()
+	if err != nil {
+		return err
+	}
+	if err = zkp.VerifyPaillierModulus(N, pf, serverKey, round.key.ECDSAPub); err != nil {
+		return err
+	}
+	cache.Store(keyID, N)
+	return nil
+}
+
+// verifyAdaptorNonceShare checks the server's proof that its adaptor nonce share k1·Y matches its ephemeral public
+// share k1·G and returns the share for lindellcore
+func (round *round2) verifyAdaptorNonceShare(r1msg *SignRound1Message, msg1 ffi.EphKeyGenFirstMsg) (ffi.Point, error) {
+	ec := round.Params().EC()
+	R1Y, err := r1msg.UnmarshalAdaptorShare(ec)
+	if err != nil {
+		return ffi.Point{}, err
+	}
+	proof, err := r1msg.UnmarshalAdaptorProof(ec)
+	if err != nil {
+		return ffi.Point{}, err
+	}
+	R1, err := msg1.PublicShare.ECPoint(ec)
+	if err != nil {
+		return ffi.Point{}, err
+	}
+	if !proof.Verify(crypto.ScalarBaseMult(ec, big.NewInt(1)), R1, round.temp.adaptorPoint, R1Y) {
+		return ffi.Point{}, errors.New("failed to verify the server's adaptor nonce share")
+	}
+	return ffi.NewPoint(R1Y)
+}
+
 func (round *round2) Update() (bool, *tss.Error) {
-	if !round.isServer {
+	defer round.temp.tracing.span("round2.update")()
+	if !round.IsServer() {
 		round.setOK()
 		return true, nil
 	}
@@ -112,16 +203,3 @@ func (round *round2) NextRound() tss.Round {
 	round.started = false
 	return &round3{round}
 }
-
-func (round *round2) getOtherPartyId() int {
-	i := round.PartyID().Index
-
-	for j, _ := range round.Parties().IDs() {
-		if j == i {
-			continue
-		}
-		return j
-	}
-
-	return i
-}
@@ -8,14 +8,17 @@ package signing
 
 import (
 	"crypto/ecdsa"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 
 	"go-rust/lindell/ffi"
+	"go-rust/lindell/metrics"
+	"go-rust/lindell/zeroize"
+	"go-rust/lindell/zkp"
 
 	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
 	"github.com/bnb-chain/tss-lib/tss"
 )
 
@@ -26,36 +29,66 @@ func (round *round3) Start() *tss.Error {
 	round.number = 3
 	round.started = true
 	round.resetOK()
+	defer round.enter(3)()
 
 	i := round.PartyID().Index
 	round.ok[i] = true
 
-	if !round.isServer {
+	if !round.IsServer() {
+		round.succeed()
+		if round.isAdaptor() {
+			round.preEnd <- &PreSignature{}
+			return nil
+		}
 		round.end <- common.SignatureData{}
 
 		return nil
 	}
 
+	Pj := round.Parties().IDs()[round.getOtherPartyId()]
 	r2msg := round.temp.signRound2Messages[round.getOtherPartyId()].Content().(*SignRound2Message)
-	var msg2 ffi.Round2Result
-	if err := json.Unmarshal(r2msg.Rst, &msg2); err != nil {
-		return round.WrapError(err)
+	round.temp.tracing.followPeer(r2msg.GetTraceContext())
+	msg2, err := r2msg.UnmarshalRst(round.Params().EC())
+	if err != nil {
+		return round.WrapError(err, Pj)
+	}
+	if (msg2.AdaptorNonce != nil) != round.isAdaptor() {
+		return round.WrapError(errors.New("the client does not agree on signing in adaptor mode"), Pj)
+	}
+	var nonce *adaptorNonce
+	if round.isAdaptor() {
+		if nonce, err = round.verifyAdaptorNonce(msg2); err != nil {
+			return round.WrapError(err, Pj)
+		}
 	}
 
-	partialSign := new(big.Int)
-	partialSign.SetString(msg2.PartialSig.C3, 10)
+	partialSign := ffi.Str2BigInt(msg2.PartialSig.C3)
+	done := round.timeCall(metrics.CallPaillierDecrypt)
 	plain, err := round.key.PaillierSK.Decrypt(partialSign)
+	done()
 	if err != nil {
 		return round.WrapError(err)
 	}
+	// with the signature, the plaintext reveals k1; the copy in input3 is a string and stays until collected
+	defer zeroize.BigInt(plain)
 
 	input3 := ffi.Round3Input{
+		Curve:    round.CurveName(),
 		PlainSig: plain.String(),
 		R1Rst:    *round.temp.round1Rst,
 		R2Rst:    msg2,
 	}
 
-	rst3 := ffi.Round3(input3)
+	done = round.timeCall(metrics.CallFFIRound3)
+	rst3, err := ffi.Round3(input3)
+	done()
+	if err != nil {
+		// the client's message is all lindellcore checks that this party did not produce itself
+		return round.WrapError(err, Pj)
+	}
+	if round.isAdaptor() {
+		return round.finishPreSignature(nonce, rst3, Pj)
+	}
 
 	sumS := new(big.Int)
 	sumS.SetString(rst3.Sig.S, 10)
@@ -63,25 +96,15 @@ func (round *round3) Start() *tss.Error {
 	Rx := new(big.Int)
 	Rx.SetString(rst3.Sig.R, 10)
 
-	recid := 0
-	// byte v = if(R.X > curve.N) then 2 else 0) | (if R.Y.IsEven then 0 else 1);
-	if Rx.Cmp(round.Params().EC().Params().N) > 0 {
-		recid = 2
+	// the nonce is R = k1·R2; lindellcore only returns its x coordinate reduced mod q
+	R, err := round.noncePoint(msg2)
+	if err != nil {
+		return round.WrapError(err, Pj)
 	}
-	// TODO: should update it here
-	//if round.temp.ry.Bit(0) != 0 {
-	//	recid |= 1
-	//}
-
-	// This is copied from:
-	// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L442-L444
-	// This is needed because of tendermint checks here:
+	// lindellcore returns s as computed, so its sign and the recovery id are adjusted together here. Low s is needed
+	// because of tendermint checks here:
 	// https://github.com/tendermint/tendermint/blob/d9481e3648450cb99e15c6a070c1fb69aa0c255b/crypto/secp256k1/secp256k1_nocgo.go#L43-L47
-	secp256k1halfN := new(big.Int).Rsh(round.Params().EC().Params().N, 1)
-	if sumS.Cmp(secp256k1halfN) > 0 {
-		sumS.Sub(round.Params().EC().Params().N, sumS)
-		recid ^= 1
-	}
+	sumS, recid := lowS(round.Params().EC().Params().N, R, sumS)
 
 	// save the signature for final output
 	bitSizeInBytes := round.Params().EC().Params().BitSize / 8
@@ -101,17 +124,93 @@ func (round *round3) Start() *tss.Error {
 		return round.WrapError(fmt.Errorf("signature verification failed"))
 	}
 
+	round.succeed()
 	round.end <- *round.data
 
 	return nil
 }
 
+// noncePoint recomputes the signing nonce R = k1·R2 from our ephemeral secret and the client's ephemeral share
+func (round *round3) noncePoint(msg2 ffi.Round2Result) (*crypto.ECPoint, error) {
+	R2, err := msg2.EphPartyTwoSecondMessage.CommWitness.PublicShare.ECPoint(round.Params().EC())
+	if err != nil {
+		return nil, err
+	}
+	k1 := round.temp.round1Rst.EphEcKeyPairParty1.SecretShare.BigInt()
+	defer zeroize.BigInt(k1)
+	return R2.ScalarMult(k1), nil
+}
+
+// adaptorNonce is the client's adaptor nonce once verified: RHat = k·G and RPoint = k·Y with the proof of that
+type adaptorNonce struct {
+	RHat, RPoint *crypto.ECPoint
+	proof        *zkp.DLEQProof
+}
+
+// verifyAdaptorNonce checks the client's adaptor nonce before lindellcore sees it: RHat must be k1·R2 and its proof
+// must show log_R1(RHat) = log_R1Y(RPoint)
+func (round *round3) verifyAdaptorNonce(msg2 ffi.Round2Result) (*adaptorNonce, error) {
+	ec := round.Params().EC()
+	nonce := msg2.AdaptorNonce
+	RHat, err := nonce.RHat.ECPoint(ec)
+	if err != nil {
+		return nil, err
+	}
+	RPoint, err := nonce.R.ECPoint(ec)
+	if err != nil {
+		return nil, err
+	}
+	A1, err := nonce.Proof.A1.ECPoint(ec)
+	if err != nil {
+		return nil, err
+	}
+	A2, err := nonce.Proof.A2.ECPoint(ec)
+	if err != nil {
+		return nil, err
+	}
+	R, err := round.noncePoint(msg2)
+	if err != nil {
+		return nil, err
+	}
+	if !RHat.Equals(R) {
+		return nil, errors.New("adaptor nonce does not match the ephemeral key exchange")
+	}
+	proof := &zkp.DLEQProof{A1: A1, A2: A2, Z: nonce.Proof.Z.BigInt()}
+	if !proof.Verify(round.temp.r1, RHat, round.temp.r1Y, RPoint) {
+		return nil, errors.New("failed to verify the adaptor nonce")
+	}
+	return &adaptorNonce{RHat: RHat, RPoint: RPoint, proof: proof}, nil
+}
+
+// finishPreSignature assembles the pre-signature from lindellcore's output and only releases it once it verifies
+func (round *round3) finishPreSignature(nonce *adaptorNonce, rst3 ffi.Round3Result, Pj *tss.PartyID) *tss.Error {
+	pre := &PreSignature{
+		M:       round.temp.m,
+		R:       ffi.Str2BigInt(rst3.Sig.R),
+		SHat:    ffi.Str2BigInt(rst3.Sig.S),
+		RHat:    nonce.RHat,
+		RPoint:  nonce.RPoint,
+		R1:      round.temp.r1,
+		R1Y:     round.temp.r1Y,
+		R1Proof: round.temp.r1Proof,
+		RProof:  nonce.proof,
+	}
+	if err := VerifyPreSignature(round.key.ECDSAPub, round.temp.m, round.temp.adaptorPoint, pre); err != nil {
+		return round.WrapError(err, Pj)
+	}
+
+	round.succeed()
+	round.preEnd <- pre
+	return nil
+}
+
 func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
 	// not expecting any incoming messages in this round
 	return false
 }
 
 func (round *round3) Update() (bool, *tss.Error) {
+	defer round.temp.tracing.span("round3.update")()
 	// not expecting any incoming messages in this round
 	return false, nil
 }
@@ -120,6 +219,24 @@ func (round *round3) NextRound() tss.Round {
 	return nil // finished!
 }
 
+// lowS returns s normalised to the lower half of the order q, as btcec does in
+// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L442-L444
+// together with the recovery id of the signature over the nonce R: bit 0 is the parity of R.y, flipped when s is
+// negated since (r, q-s) is the signature over -R, and bit 1 is set when R.x overflows q.
+func lowS(q *big.Int, R *crypto.ECPoint, s *big.Int) (*big.Int, int) {
+	recid := 0
+	if R.X().Cmp(q) >= 0 {
+		recid = 2
+	}
+	if R.Y().Bit(0) != 0 {
+		recid |= 1
+	}
+	if s.Cmp(new(big.Int).Rsh(q, 1)) > 0 {
+		return new(big.Int).Sub(q, s), recid ^ 1
+	}
+	return s, recid
+}
+
 func padToLengthBytesInPlace(src []byte, length int) []byte {
 	oriLen := len(src)
 	if oriLen < length {
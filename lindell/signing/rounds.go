@@ -1,9 +1,15 @@
 package signing
 
 import (
+	"fmt"
+	"time"
+
+	"go-rust/lindell/logging"
+
 	"github.com/bnb-chain/tss-lib/common"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/tss"
+	"go.uber.org/zap"
 )
 
 const (
@@ -18,6 +24,7 @@ type (
 		temp    *localTempData
 		out     chan<- tss.Message
 		end     chan<- common.SignatureData
+		preEnd  chan<- *PreSignature
 		ok      []bool // `ok` tracks parties which have been verified by Update()
 		started bool
 		number  int
@@ -79,6 +86,40 @@ func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
 	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
 }
 
+// enter measures round n's Start for metrics and tracing: defer round.enter(n)()
+func (round *base) enter(n int) func() {
+	round.temp.log.Debug("round started", zap.Int(logging.KeyRound, n))
+	observe := round.temp.metrics.enterRound(n)
+	finish := round.temp.tracing.span(fmt.Sprintf("round%d.start", n))
+	return func() {
+		finish()
+		observe()
+	}
+}
+
+// timeCall measures a cgo call or a Paillier operation, named by one of the metrics.Call constants
+func (round *base) timeCall(call string) func() {
+	begin := time.Now()
+	finish := round.temp.tracing.span(call)
+	return func() {
+		finish()
+		round.temp.metrics.observeCall(call, begin)
+	}
+}
+
+// succeed reports the session's success, before its result is output
+func (round *base) succeed() {
+	round.temp.metrics.succeed()
+	round.temp.tracing.finish(nil)
+	round.temp.end()
+	round.temp.log.Info("session succeeded", zap.Int(logging.KeyRound, round.number))
+}
+
+// isAdaptor reports whether the session produces an adaptor pre-signature rather than a signature
+func (round *base) isAdaptor() bool {
+	return round.preEnd != nil
+}
+
 // ----- //
 
 // `ok` tracks parties which have been verified by Update()
@@ -93,3 +134,16 @@ func (round *base) setOK() {
 		round.ok[j] = true
 	}
 }
+
+func (round *base) getOtherPartyId() int {
+	i := round.PartyID().Index
+
+	for j, _ := range round.Parties().IDs() {
+		if j == i {
+			continue
+		}
+		return j
+	}
+
+	return i
+}
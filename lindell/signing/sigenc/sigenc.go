@@ -0,0 +1,238 @@
+// Package sigenc converts the common.SignatureData produced by lindell/signing into the wire formats ECDSA
+// signatures travel in: ASN.1 DER, 64-byte compact R || S, 65-byte Ethereum R || S || V or R || S with V apart, and
+// Bitcoin's compact-recoverable form with a header byte, and parses each of them back.
+//
+// The recovery id is the one round 3 stores in SignatureRecovery: bit 0 is the parity of R.y and bit 1 is set
+// when R.x overflowed the group order.
+package sigenc
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	// RecoverableSize is the length of the Ethereum and Bitcoin recoverable encodings
+	RecoverableSize = 65
+
+	ethereumLegacyV  = 27
+	ethereumEIP155V  = 35
+	bitcoinHeader    = 27
+	bitcoinHeaderMax = bitcoinHeader + 7
+	bitcoinCompFlag  = 4
+)
+
+type derSignature struct {
+	R, S *big.Int
+}
+
+// DER encodes sig as SEQUENCE { INTEGER r, INTEGER s }
+func DER(ec elliptic.Curve, sig *common.SignatureData) ([]byte, error) {
+	r, s, err := scalars(ec, sig)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(derSignature{r, s})
+}
+
+// ParseDER accepts only the canonical DER encoding, so a signature has exactly one valid serialisation
+func ParseDER(ec elliptic.Curve, der []byte) (*common.SignatureData, error) {
+	var ds derSignature
+	rest, err := asn1.Unmarshal(der, &ds)
+	if err != nil {
+		return nil, fmt.Errorf("malformed DER signature: %v", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing bytes after DER signature")
+	}
+	if canonical, err := asn1.Marshal(ds); err != nil || string(canonical) != string(der) {
+		return nil, errors.New("DER signature is not canonically encoded")
+	}
+	return newSignatureData(ec, ds.R, ds.S, nil)
+}
+
+// Compact encodes sig as R || S, each padded to the byte size of the curve order
+func Compact(ec elliptic.Curve, sig *common.SignatureData) ([]byte, error) {
+	r, s, err := scalars(ec, sig)
+	if err != nil {
+		return nil, err
+	}
+	size := scalarSize(ec)
+	bz := make([]byte, 2*size)
+	r.FillBytes(bz[:size])
+	s.FillBytes(bz[size:])
+	return bz, nil
+}
+
+func ParseCompact(ec elliptic.Curve, bz []byte) (*common.SignatureData, error) {
+	size := scalarSize(ec)
+	if len(bz) != 2*size {
+		return nil, fmt.Errorf("compact signature must be %d bytes, got %d", 2*size, len(bz))
+	}
+	return newSignatureData(ec, new(big.Int).SetBytes(bz[:size]), new(big.Int).SetBytes(bz[size:]), nil)
+}
+
+// EthereumRSV encodes a secp256k1 sig as R || S || V with V in a single byte, as wallets and eth_sign use it. A nil
+// chainID gives the pre-EIP-155 V = 27 + recid, otherwise V = recid + 35 + 2*chainID, so the one byte only holds the
+// V of chain ids up to 109. Transactions of other chains take EthereumV or, when typed, EthereumYParity next to
+// Compact.
+func EthereumRSV(sig *common.SignatureData, chainID *big.Int) ([]byte, error) {
+	v, err := EthereumV(sig, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !v.IsUint64() || v.Uint64() > 0xff {
+		return nil, fmt.Errorf("V = %s does not fit in one byte, use EthereumV", v)
+	}
+	bz, err := Compact(tss.S256(), sig)
+	if err != nil {
+		return nil, err
+	}
+	return append(bz, byte(v.Uint64())), nil
+}
+
+// EthereumV returns the V of a legacy transaction signed with sig: 27 + recid for a nil chainID, otherwise the
+// EIP-155 recid + 35 + 2*chainID of any size. R and S are the halves of Compact.
+func EthereumV(sig *common.SignatureData, chainID *big.Int) (*big.Int, error) {
+	recid, err := EthereumYParity(sig)
+	if err != nil {
+		return nil, err
+	}
+	v := big.NewInt(int64(ethereumLegacyV + recid))
+	if chainID != nil {
+		if chainID.Sign() < 0 {
+			return nil, errors.New("negative chain id")
+		}
+		v.Lsh(chainID, 1).Add(v, big.NewInt(int64(ethereumEIP155V+recid)))
+	}
+	return v, nil
+}
+
+// EthereumYParity returns the y parity of sig, which typed transactions (EIP-2930, EIP-1559) carry in place of V
+func EthereumYParity(sig *common.SignatureData) (byte, error) {
+	recid, err := recoveryID(sig)
+	if err != nil {
+		return 0, err
+	}
+	if recid > 1 {
+		return 0, errors.New("ethereum cannot encode a signature whose R.x overflowed the group order")
+	}
+	return byte(recid), nil
+}
+
+// ParseEthereumRSV accepts V as a bare recovery id (0, 1), the legacy 27/28 or, when chainID is given, the EIP-155
+// value for that chain
+func ParseEthereumRSV(bz []byte, chainID *big.Int) (*common.SignatureData, error) {
+	if len(bz) != RecoverableSize {
+		return nil, fmt.Errorf("ethereum signature must be %d bytes, got %d", RecoverableSize, len(bz))
+	}
+	return ParseEthereumV(bz[:64], big.NewInt(int64(bz[64])), chainID)
+}
+
+// ParseEthereumV parses the compact R || S of a signature with its V apart, which takes the V of any chain id or a
+// typed transaction's y parity
+func ParseEthereumV(rs []byte, v, chainID *big.Int) (*common.SignatureData, error) {
+	if len(rs) != 64 {
+		return nil, fmt.Errorf("ethereum R || S must be 64 bytes, got %d", len(rs))
+	}
+	if v == nil || v.Sign() < 0 {
+		return nil, errors.New("missing V")
+	}
+	recid := new(big.Int)
+	switch {
+	case v.Cmp(big.NewInt(1)) <= 0:
+		recid.Set(v)
+	case v.Cmp(big.NewInt(ethereumLegacyV)) == 0 || v.Cmp(big.NewInt(ethereumLegacyV+1)) == 0:
+		recid.Sub(v, big.NewInt(ethereumLegacyV))
+	case chainID != nil:
+		recid.Lsh(chainID, 1).Add(recid, big.NewInt(ethereumEIP155V)).Sub(v, recid)
+		if recid.Sign() < 0 || recid.Cmp(big.NewInt(1)) > 0 {
+			return nil, fmt.Errorf("V = %s does not belong to chain %s", v, chainID)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected V = %s without a chain id", v)
+	}
+	return newSignatureData(tss.S256(), new(big.Int).SetBytes(rs[:32]), new(big.Int).SetBytes(rs[32:]), []byte{byte(recid.Uint64())})
+}
+
+// BitcoinCompactRecoverable encodes a secp256k1 sig in the form of Bitcoin's signmessage: a header byte
+// 27 + recid (+ 4 when the key is serialised compressed) followed by R || S
+func BitcoinCompactRecoverable(sig *common.SignatureData, compressed bool) ([]byte, error) {
+	recid, err := recoveryID(sig)
+	if err != nil {
+		return nil, err
+	}
+	header := byte(bitcoinHeader + recid)
+	if compressed {
+		header += bitcoinCompFlag
+	}
+	bz, err := Compact(tss.S256(), sig)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{header}, bz...), nil
+}
+
+// ParseBitcoinCompactRecoverable returns the signature and whether the signing key is serialised compressed
+func ParseBitcoinCompactRecoverable(bz []byte) (*common.SignatureData, bool, error) {
+	if len(bz) != RecoverableSize {
+		return nil, false, fmt.Errorf("compact recoverable signature must be %d bytes, got %d", RecoverableSize, len(bz))
+	}
+	header := int(bz[0])
+	if header < bitcoinHeader || header > bitcoinHeaderMax {
+		return nil, false, fmt.Errorf("invalid compact signature header %d", header)
+	}
+	recid := (header - bitcoinHeader) & 3
+	compressed := (header-bitcoinHeader)&bitcoinCompFlag != 0
+	sig, err := newSignatureData(tss.S256(), new(big.Int).SetBytes(bz[1:33]), new(big.Int).SetBytes(bz[33:]), []byte{byte(recid)})
+	return sig, compressed, err
+}
+
+// ----- //
+
+func scalars(ec elliptic.Curve, sig *common.SignatureData) (r, s *big.Int, err error) {
+	if sig == nil || len(sig.GetR()) == 0 || len(sig.GetS()) == 0 {
+		return nil, nil, errors.New("signature is missing R or S")
+	}
+	r, s = new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())
+	if !inRange(ec, r) || !inRange(ec, s) {
+		return nil, nil, errors.New("signature values are out of range")
+	}
+	return r, s, nil
+}
+
+func recoveryID(sig *common.SignatureData) (int, error) {
+	if sig == nil || len(sig.GetSignatureRecovery()) != 1 || sig.GetSignatureRecovery()[0] > 3 {
+		return 0, errors.New("signature has no valid recovery id")
+	}
+	return int(sig.GetSignatureRecovery()[0]), nil
+}
+
+// newSignatureData lays out r and s the way round 3 does
+func newSignatureData(ec elliptic.Curve, r, s *big.Int, recovery []byte) (*common.SignatureData, error) {
+	if !inRange(ec, r) || !inRange(ec, s) {
+		return nil, errors.New("signature values are out of range")
+	}
+	size := scalarSize(ec)
+	sig := &common.SignatureData{
+		R:                 r.FillBytes(make([]byte, size)),
+		S:                 s.FillBytes(make([]byte, size)),
+		SignatureRecovery: recovery,
+	}
+	sig.Signature = append(append([]byte{}, sig.R...), sig.S...)
+	return sig, nil
+}
+
+func inRange(ec elliptic.Curve, v *big.Int) bool {
+	return v.Sign() > 0 && v.Cmp(ec.Params().N) < 0
+}
+
+func scalarSize(ec elliptic.Curve) int {
+	return (ec.Params().N.BitLen() + 7) / 8
+}
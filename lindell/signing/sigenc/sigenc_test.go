@@ -0,0 +1,144 @@
+package sigenc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDER(t *testing.T) {
+	for _, ec := range []elliptic.Curve{elliptic.P256(), tss.S256()} {
+		priv, err := ecdsa.GenerateKey(ec, rand.Reader)
+		assert.NoError(t, err)
+		hash := sha256.Sum256([]byte("lindell"))
+		r, s, err := ecdsa.Sign(rand.Reader, priv, hash[:])
+		assert.NoError(t, err)
+		sig, err := newSignatureData(ec, r, s, nil)
+		assert.NoError(t, err)
+
+		der, err := DER(ec, sig)
+		assert.NoError(t, err)
+		assert.True(t, ecdsa.VerifyASN1(&priv.PublicKey, hash[:], der), "DER must verify with crypto/ecdsa")
+
+		parsed, err := ParseDER(ec, der)
+		assert.NoError(t, err)
+		assert.Equal(t, sig.GetSignature(), parsed.GetSignature())
+
+		// a zero-padded INTEGER is valid BER but not DER
+		padded := append([]byte{0x30, der[1] + 1, 0x02, der[3] + 1, 0x00}, der[4:]...)
+		_, err = ParseDER(ec, padded)
+		assert.Error(t, err, "non-canonical DER must be rejected")
+		_, err = ParseDER(ec, append(der, 0))
+		assert.Error(t, err, "trailing bytes must be rejected")
+	}
+
+	// stdlib signatures parse back
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := ecdsa.SignASN1(rand.Reader, priv, []byte("digest"))
+	assert.NoError(t, err)
+	sig, err := ParseDER(elliptic.P256(), der)
+	assert.NoError(t, err)
+	assert.True(t, ecdsa.Verify(&priv.PublicKey, []byte("digest"),
+		new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())))
+}
+
+func TestCompact(t *testing.T) {
+	ec := tss.S256()
+	sig, err := newSignatureData(ec, big.NewInt(1), new(big.Int).Sub(ec.Params().N, big.NewInt(1)), nil)
+	assert.NoError(t, err)
+	bz, err := Compact(ec, sig)
+	assert.NoError(t, err)
+	assert.Len(t, bz, 64)
+	assert.Equal(t, sig.GetSignature(), bz)
+
+	parsed, err := ParseCompact(ec, bz)
+	assert.NoError(t, err)
+	assert.Equal(t, sig.GetSignature(), parsed.GetSignature())
+
+	_, err = ParseCompact(ec, make([]byte, 64))
+	assert.Error(t, err, "zero r and s must be rejected")
+	_, err = ParseCompact(ec, bz[:63])
+	assert.Error(t, err)
+}
+
+func TestRecoverable(t *testing.T) {
+	ec := btcec.S256()
+	priv, err := btcec.NewPrivateKey(ec)
+	assert.NoError(t, err)
+	hash := sha256.Sum256([]byte("lindell"))
+
+	for _, compressed := range []bool{true, false} {
+		// btcec's signmessage encoding is the reference for the Bitcoin form
+		ref, err := btcec.SignCompact(ec, priv, hash[:], compressed)
+		assert.NoError(t, err)
+		sig, gotCompressed, err := ParseBitcoinCompactRecoverable(ref)
+		assert.NoError(t, err)
+		assert.Equal(t, compressed, gotCompressed)
+
+		bz, err := BitcoinCompactRecoverable(sig, compressed)
+		assert.NoError(t, err)
+		assert.Equal(t, ref, bz)
+
+		pub, _, err := btcec.RecoverCompact(ec, bz, hash[:])
+		assert.NoError(t, err)
+		assert.True(t, pub.IsEqual(priv.PubKey()), "recovered key must match")
+
+		// the same signature in Ethereum's RSV form
+		recid := sig.GetSignatureRecovery()[0]
+		rsv, err := EthereumRSV(sig, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, byte(27)+recid, rsv[64])
+		assert.Equal(t, sig.GetSignature(), rsv[:64])
+
+		rsv, err = EthereumRSV(sig, big.NewInt(1))
+		assert.NoError(t, err)
+		assert.Equal(t, byte(37)+recid, rsv[64])
+		parsed, err := ParseEthereumRSV(rsv, big.NewInt(1))
+		assert.NoError(t, err)
+		assert.Equal(t, sig.GetSignature(), parsed.GetSignature())
+		assert.Equal(t, sig.GetSignatureRecovery(), parsed.GetSignatureRecovery())
+
+		_, err = ParseEthereumRSV(rsv, big.NewInt(5))
+		assert.Error(t, err, "V of another chain must be rejected")
+		_, err = ParseEthereumRSV(rsv, nil)
+		assert.Error(t, err, "an EIP-155 V needs the chain id")
+
+		_, err = EthereumRSV(sig, big.NewInt(109))
+		assert.NoError(t, err, "the last chain id whose V always fits")
+		_, err = EthereumRSV(sig, big.NewInt(111))
+		assert.Error(t, err, "V must fit in one byte")
+
+		// chains past 109 take V apart from R || S, whatever its size
+		for _, chainID := range []*big.Int{big.NewInt(137), new(big.Int).Lsh(big.NewInt(1), 70)} {
+			v, err := EthereumV(sig, chainID)
+			assert.NoError(t, err)
+			want := new(big.Int).Lsh(chainID, 1)
+			assert.Zero(t, want.Add(want, big.NewInt(35+int64(recid))).Cmp(v))
+			parsed, err = ParseEthereumV(rsv[:64], v, chainID)
+			assert.NoError(t, err)
+			assert.Equal(t, sig.GetSignatureRecovery(), parsed.GetSignatureRecovery())
+			_, err = ParseEthereumV(rsv[:64], v, big.NewInt(1))
+			assert.Error(t, err, "V of another chain must be rejected")
+		}
+
+		// typed transactions carry the y parity instead
+		parity, err := EthereumYParity(sig)
+		assert.NoError(t, err)
+		assert.Equal(t, recid, parity)
+		parsed, err = ParseEthereumV(rsv[:64], big.NewInt(int64(parity)), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, sig.GetSignature(), parsed.GetSignature())
+		assert.Equal(t, sig.GetSignatureRecovery(), parsed.GetSignatureRecovery())
+	}
+
+	_, _, err = ParseBitcoinCompactRecoverable(append([]byte{26}, make([]byte, 64)...))
+	assert.Error(t, err)
+}
@@ -25,6 +25,7 @@ func BenchmarkLindellSigningParallel(b *testing.B) {
 
 func runSigningSerial(b *testing.B) {
 	signKeys, signPIDs, err := LoadKeygenTestFixturesRandomSet(2, 3)
+	skipWithoutFixtures(b, err)
 	assert.NoError(b, err, "should load keygen fixtures")
 	assert.Equal(b, 2, len(signKeys))
 	assert.Equal(b, 2, len(signPIDs))
@@ -37,6 +38,7 @@ func runSigningSerial(b *testing.B) {
 
 func runSigningParallel(b *testing.B) {
 	signKeys, signPIDs, err := LoadKeygenTestFixturesRandomSet(2, 3)
+	skipWithoutFixtures(b, err)
 	assert.NoError(b, err, "should load keygen fixtures")
 	assert.Equal(b, 2, len(signKeys))
 	assert.Equal(b, 2, len(signPIDs))
@@ -59,17 +61,12 @@ func runSigningOnce(b *testing.B, signPIDs tss.SortedPartyIDs, signKeys []keygen
 	signingEndCh := make(chan common.SignatureData, len(signPIDs))
 
 	var wgPrepare sync.WaitGroup
-	isServer := false
 	// init the signingParties
 	for i := 0; i < len(signPIDs); i++ {
 		i := i
 
-		if i == 0 {
-			isServer = true
-		} else {
-			isServer = false
-		}
-		params := NewLindellSignParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), 1, isServer)
+		params, err := NewLindellSignParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), 1)
+		assert.NoError(b, err, "should build signing params")
 
 		wgPrepare.Add(1)
 		go func() {
@@ -99,9 +96,9 @@ signing:
 			dest := msg.GetTo()
 			if dest == nil {
 				for _, P := range signingParties {
-					//if P.PartyID().Index == msg.GetFrom().Index {
-					//	continue
-					//}
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
 					go SharedPartyUpdater(P, msg, signingErrCh)
 				}
 			} else {
@@ -7,49 +7,64 @@
 package signing
 
 import (
+	"crypto/elliptic"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math/big"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
 
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/tss"
 	"github.com/pkg/errors"
 )
 
+//go:generate go run ../../cmd/lindellctl keygen -parties 3 -threshold 1 -out ../../test/_ecdsa_fixtures
+//go:generate go run ../../cmd/lindellctl keygen -parties 2 -threshold 1 -out ../../test/_ecdsa_fixtures_2of2
+//go:generate go run ../../cmd/lindellctl keygen -parties 5 -threshold 2 -out ../../test/_ecdsa_fixtures_3of5
+
 const (
-	testFixtureDirFormat  = "%s/../../test/_ecdsa_fixtures"
+	testFixtureDirFormat  = "%s/../../test/%s"
 	testFixtureFileFormat = "keygen_data_%d.json"
 )
 
+// The fixture directories under test/, one per configuration written by go generate
+const (
+	// TestFixtureDir holds 3 parties of threshold 1, any two of them sign
+	TestFixtureDir = "_ecdsa_fixtures"
+	// TestFixtureDir2of2 holds 2 parties of threshold 1
+	TestFixtureDir2of2 = "_ecdsa_fixtures_2of2"
+	// TestFixtureDir3of5 holds 5 parties of threshold 2, too many signers for two-party signing
+	TestFixtureDir3of5 = "_ecdsa_fixtures_3of5"
+)
+
+// ErrKeygenFixturesMissing is returned by the fixture loaders when a fixture file is absent or empty; tests skip on it
+var ErrKeygenFixturesMissing = errors.New("keygen test fixtures are missing, create them with go generate ./lindell/signing/")
+
 func LoadKeygenTestFixtures(qty int, optionalStart ...int) ([]keygen.LocalPartySaveData, tss.SortedPartyIDs, error) {
+	return LoadKeygenTestFixturesFromDir(TestFixtureDir, qty, optionalStart...)
+}
+
+// LoadKeygenTestFixturesFromDir is LoadKeygenTestFixtures for the fixtures in dir, one of the TestFixtureDir constants
+func LoadKeygenTestFixturesFromDir(dir string, qty int, optionalStart ...int) ([]keygen.LocalPartySaveData,
+	tss.SortedPartyIDs, error) {
 	keys := make([]keygen.LocalPartySaveData, 0, qty)
 	start := 0
 	if 0 < len(optionalStart) {
 		start = optionalStart[0]
 	}
 	for i := start; i < qty; i++ {
-		fixtureFilePath := makeTestFixtureFilePath(i)
-		bz, err := ioutil.ReadFile(fixtureFilePath)
+		key, err := loadTestFixture(dir, i)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err,
-				"could not open the test fixture for party %d in the expected location: %s. run keygen tests first.",
-				i, fixtureFilePath)
-		}
-		var key keygen.LocalPartySaveData
-		if err = json.Unmarshal(bz, &key); err != nil {
-			return nil, nil, errors.Wrapf(err,
-				"could not unmarshal fixture data for party %d located at: %s",
-				i, fixtureFilePath)
-		}
-		for _, kbxj := range key.BigXj {
-			kbxj.SetCurve(tss.S256())
+			return nil, nil, err
 		}
-		key.ECDSAPub.SetCurve(tss.S256())
 		keys = append(keys, key)
 	}
 	partyIDs := make(tss.UnSortedPartyIDs, len(keys))
@@ -71,23 +86,10 @@ func LoadKeygenTestFixturesRandomSet(qty, fixtureCount int) ([]keygen.LocalParty
 		}
 	}
 	for i := range plucked {
-		fixtureFilePath := makeTestFixtureFilePath(i)
-		bz, err := ioutil.ReadFile(fixtureFilePath)
+		key, err := loadTestFixture(TestFixtureDir, i)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err,
-				"could not open the test fixture for party %d in the expected location: %s. run keygen tests first.",
-				i, fixtureFilePath)
+			return nil, nil, err
 		}
-		var key keygen.LocalPartySaveData
-		if err = json.Unmarshal(bz, &key); err != nil {
-			return nil, nil, errors.Wrapf(err,
-				"could not unmarshal fixture data for party %d located at: %s",
-				i, fixtureFilePath)
-		}
-		for _, kbxj := range key.BigXj {
-			kbxj.SetCurve(tss.S256())
-		}
-		key.ECDSAPub.SetCurve(tss.S256())
 		keys = append(keys, key)
 	}
 	partyIDs := make(tss.UnSortedPartyIDs, len(keys))
@@ -113,9 +115,76 @@ func LoadNTildeH1H2FromTestFixture(idx int) (NTildei, h1i, h2i *big.Int, err err
 	return
 }
 
-func makeTestFixtureFilePath(partyIndex int) string {
+// RedealKeygenTestFixtures deals a fresh secret on another curve to the same party keys, keeping the Paillier and
+// ring-Pedersen material of the loaded fixtures. The fixtures are all secp256k1; this lets tests cover other curves.
+func RedealKeygenTestFixtures(ec elliptic.Curve, keys []keygen.LocalPartySaveData, threshold int,
+) ([]keygen.LocalPartySaveData, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no fixtures to redeal")
+	}
+	Ks := keys[0].Ks
+	secret := common.GetRandomPositiveInt(ec.Params().N)
+	_, shares, err := vss.Create(ec, threshold, secret, Ks)
+	if err != nil {
+		return nil, err
+	}
+	bigXj := make([]*crypto.ECPoint, len(shares))
+	for j, share := range shares {
+		bigXj[j] = crypto.ScalarBaseMult(ec, share.Share)
+	}
+	redealt := make([]keygen.LocalPartySaveData, len(keys))
+	for i, key := range keys {
+		k := findShareIndex(Ks, key.ShareID)
+		if k < 0 {
+			return nil, fmt.Errorf("share id of fixture %d is not among its Ks", i)
+		}
+		key.Xi = new(big.Int).Set(shares[k].Share)
+		key.BigXj = bigXj
+		key.ECDSAPub = crypto.ScalarBaseMult(ec, secret)
+		redealt[i] = key
+	}
+	return redealt, nil
+}
+
+func findShareIndex(Ks []*big.Int, shareID *big.Int) int {
+	for j, k := range Ks {
+		if k.Cmp(shareID) == 0 {
+			return j
+		}
+	}
+	return -1
+}
+
+func loadTestFixture(dir string, partyIndex int) (keygen.LocalPartySaveData, error) {
+	var key keygen.LocalPartySaveData
+	fixtureFilePath := makeTestFixtureFilePath(dir, partyIndex)
+	bz, err := readTestFixture(fixtureFilePath)
+	if err != nil {
+		return key, errors.Wrapf(err, "could not read the test fixture for party %d at %s", partyIndex, fixtureFilePath)
+	}
+	if err = json.Unmarshal(bz, &key); err != nil {
+		return key, errors.Wrapf(err,
+			"could not unmarshal fixture data for party %d located at: %s",
+			partyIndex, fixtureFilePath)
+	}
+	for _, kbxj := range key.BigXj {
+		kbxj.SetCurve(tss.S256())
+	}
+	key.ECDSAPub.SetCurve(tss.S256())
+	return key, nil
+}
+
+func readTestFixture(path string) ([]byte, error) {
+	bz, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && len(bz) == 0) {
+		return nil, ErrKeygenFixturesMissing
+	}
+	return bz, err
+}
+
+func makeTestFixtureFilePath(dir string, partyIndex int) string {
 	_, callerFileName, _, _ := runtime.Caller(0)
 	srcDirName := filepath.Dir(callerFileName)
-	fixtureDirName := fmt.Sprintf(testFixtureDirFormat, srcDirName)
+	fixtureDirName := fmt.Sprintf(testFixtureDirFormat, srcDirName, dir)
 	return fmt.Sprintf("%s/"+testFixtureFileFormat, fixtureDirName, partyIndex)
 }
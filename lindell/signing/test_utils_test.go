@@ -0,0 +1,40 @@
+package signing
+
+import (
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/vss"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeygenFixtureConfigurations(t *testing.T) {
+	for _, tc := range []struct {
+		dir                string
+		parties, threshold int
+	}{
+		{TestFixtureDir, 3, 1},
+		{TestFixtureDir2of2, 2, 1},
+		{TestFixtureDir3of5, 5, 2},
+	} {
+		t.Run(tc.dir, func(t *testing.T) {
+			keys, pIDs, err := LoadKeygenTestFixturesFromDir(tc.dir, tc.parties)
+			skipWithoutFixtures(t, err)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, pIDs, tc.parties)
+			shares := make(vss.Shares, len(keys))
+			for i, key := range keys {
+				assert.Len(t, key.Ks, tc.parties)
+				assert.True(t, key.ECDSAPub.Equals(keys[0].ECDSAPub), "party %d must hold a share of the same key", i)
+				shares[i] = &vss.Share{Threshold: tc.threshold, ID: key.ShareID, Share: key.Xi}
+			}
+			// any threshold+1 shares give the key, here the last ones
+			x, err := shares[tc.parties-tc.threshold-1:].ReConstruct(tss.S256())
+			assert.NoError(t, err)
+			assert.True(t, crypto.ScalarBaseMult(tss.S256(), x).Equals(keys[0].ECDSAPub))
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package signing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+)
+
+// sessionTracing records a session as one span with a child span per prepare, round Start and Update, cgo call and
+// Paillier operation. The server's session span is sent in its round 1 message and the client's session span is its
+// child, so both halves of a signature end up in one trace. As the client only learns the server's span when round 1
+// arrives, the spans are created once they end, with their real start time, and those the client ends earlier are
+// kept until then.
+type sessionTracing struct {
+	tracer opentracing.Tracer
+	parent opentracing.SpanContext
+	role   Role
+	party  *tss.PartyID
+
+	mtx      sync.Mutex
+	begin    time.Time
+	session  opentracing.Span
+	peer     opentracing.SpanContext
+	pending  []pendingSpan
+	finished bool
+}
+
+type pendingSpan struct {
+	name       string
+	begin, end time.Time
+}
+
+func newSessionTracing(params *LindellSignParameters) *sessionTracing {
+	return &sessionTracing{
+		tracer: params.Tracer(),
+		parent: params.ParentSpanContext(),
+		role:   params.Role(),
+		party:  params.PartyID(),
+	}
+}
+
+func (s *sessionTracing) start() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if !s.begin.IsZero() {
+		return
+	}
+	s.begin = time.Now()
+	if s.role == RoleServer {
+		s.open(nil)
+	}
+}
+
+// join is called by the client with the server's span context from round 1
+func (s *sessionTracing) join(carrier map[string]string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.session != nil || s.finished {
+		return
+	}
+	s.open(s.extract(carrier))
+}
+
+// followPeer is called by the server with the client's span context from round 2; the spans ending afterwards
+// follow from it
+func (s *sessionTracing) followPeer(carrier map[string]string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.peer = s.extract(carrier)
+}
+
+// span starts a span and returns the func that ends it: defer s.span(name)()
+func (s *sessionTracing) span(name string) func() {
+	begin := time.Now()
+	return func() {
+		end := time.Now()
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		switch {
+		case s.finished:
+		case s.session == nil:
+			s.pending = append(s.pending, pendingSpan{name, begin, end})
+		default:
+			s.record(pendingSpan{name, begin, end})
+		}
+	}
+}
+
+// inject returns the session's span context to send to the peer, nil if there is none yet
+func (s *sessionTracing) inject() map[string]string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.session == nil {
+		return nil
+	}
+	carrier := make(map[string]string)
+	if err := s.tracer.Inject(s.session.Context(), opentracing.TextMap, opentracing.TextMapCarrier(carrier)); err != nil || len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// finish ends the session span once, marking it failed if err is set
+func (s *sessionTracing) finish(err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.finished || s.begin.IsZero() {
+		return
+	}
+	if s.session == nil {
+		// a client that never heard from the server
+		s.open(nil)
+	}
+	if err != nil {
+		ext.Error.Set(s.session, true)
+		s.session.LogFields(otlog.Error(err))
+	}
+	s.session.Finish()
+	s.finished = true
+}
+
+// ----- //
+
+// open starts the session span as a child of the server's span, or of the caller's parent span if there is none, and
+// records the spans that ended before. Called with mtx held.
+func (s *sessionTracing) open(server opentracing.SpanContext) {
+	opts := []opentracing.StartSpanOption{opentracing.StartTime(s.begin)}
+	switch {
+	case server != nil:
+		opts = append(opts, opentracing.ChildOf(server))
+		if s.parent != nil {
+			opts = append(opts, opentracing.FollowsFrom(s.parent))
+		}
+	case s.parent != nil:
+		opts = append(opts, opentracing.ChildOf(s.parent))
+	}
+	s.session = s.tracer.StartSpan(TaskName, opts...)
+	ext.Component.Set(s.session, TaskName)
+	s.session.SetTag("lindell.role", s.role.String())
+	s.session.SetTag("lindell.party", s.party.Id)
+	for _, p := range s.pending {
+		s.record(p)
+	}
+	s.pending = nil
+}
+
+func (s *sessionTracing) record(p pendingSpan) {
+	opts := []opentracing.StartSpanOption{opentracing.ChildOf(s.session.Context()), opentracing.StartTime(p.begin)}
+	if s.peer != nil {
+		opts = append(opts, opentracing.FollowsFrom(s.peer))
+	}
+	s.tracer.StartSpan(p.name, opts...).FinishWithOptions(opentracing.FinishOptions{FinishTime: p.end})
+}
+
+func (s *sessionTracing) extract(carrier map[string]string) opentracing.SpanContext {
+	if len(carrier) == 0 {
+		return nil
+	}
+	// a peer without a tracer, or with another kind, sends nothing usable; its spans then stay in their own trace
+	ctx, err := s.tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier(carrier))
+	if err != nil {
+		return nil
+	}
+	return ctx
+}
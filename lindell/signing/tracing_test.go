@@ -0,0 +1,108 @@
+package signing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-rust/lindell/ffi"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTracing(t *testing.T, tracer opentracing.Tracer, role Role) *sessionTracing {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params, err := NewLindellSignParametersWithRole(tss.S256(), tss.NewPeerContext(pIDs), pIDs[int(role)-1], 2, 1, role)
+	assert.NoError(t, err)
+	params.SetTracer(tracer)
+	return newSessionTracing(params)
+}
+
+func spansByName(spans []*mocktracer.MockSpan) map[string]*mocktracer.MockSpan {
+	byName := make(map[string]*mocktracer.MockSpan)
+	for _, sp := range spans {
+		byName[sp.OperationName] = sp
+	}
+	return byName
+}
+
+func TestTracingJoinsTheServersTrace(t *testing.T) {
+	serverTracer, clientTracer := mocktracer.New(), mocktracer.New()
+	server, client := newTestTracing(t, serverTracer, RoleServer), newTestTracing(t, clientTracer, RoleClient)
+
+	server.start()
+	server.span("prepare")()
+	client.start()
+	clientPrepare := client.span("prepare")
+	time.Sleep(time.Millisecond)
+	clientPrepare()
+	assert.Nil(t, client.inject(), "the client has no session span before it hears from the server")
+
+	client.join(server.inject())
+	client.span("ffi_round2")()
+	server.followPeer(client.inject())
+	server.span("round3.start")()
+	client.finish(nil)
+	server.finish(nil)
+	server.finish(errors.New("reported once"))
+
+	serverSpans, clientSpans := spansByName(serverTracer.FinishedSpans()), spansByName(clientTracer.FinishedSpans())
+	assert.Len(t, serverSpans, 3)
+	assert.Len(t, clientSpans, 3)
+
+	serverSession, clientSession := serverSpans[TaskName], clientSpans[TaskName]
+	assert.NotNil(t, serverSession)
+	assert.NotNil(t, clientSession)
+	assert.Equal(t, "server", serverSession.Tag("lindell.role"))
+	assert.Equal(t, serverSession.SpanContext.SpanID, clientSession.ParentID, "the client's session is a child of the server's")
+	assert.Nil(t, serverSession.Tag("error"))
+	for _, spans := range []map[string]*mocktracer.MockSpan{serverSpans, clientSpans} {
+		for name, sp := range spans {
+			assert.Equal(t, serverSession.SpanContext.TraceID, sp.SpanContext.TraceID, "%s must be in the server's trace", name)
+		}
+	}
+	for _, name := range []string{"prepare", "ffi_round2"} {
+		assert.Equal(t, clientSession.SpanContext.SpanID, clientSpans[name].ParentID, "%s is a child of the client's session", name)
+	}
+	// the client's prepare ended before it joined and keeps its real timing
+	prepare := clientSpans["prepare"]
+	assert.False(t, prepare.StartTime.Before(clientSession.StartTime))
+	assert.True(t, prepare.FinishTime.Sub(prepare.StartTime) >= time.Millisecond)
+	assert.NotNil(t, server.peer, "round 3 follows from the client's session")
+}
+
+func TestTracingClientWithoutServer(t *testing.T) {
+	tracer := mocktracer.New()
+	client := newTestTracing(t, tracer, RoleClient)
+	client.start()
+	client.span("prepare")()
+	client.join(map[string]string{"garbage": "x"})
+	client.finish(errors.New("timed out"))
+
+	spans := tracer.FinishedSpans()
+	assert.Len(t, spans, 2)
+	var session *mocktracer.MockSpan
+	for _, sp := range spans {
+		if sp.OperationName == TaskName {
+			session = sp
+		}
+	}
+	assert.NotNil(t, session)
+	assert.Equal(t, 0, session.ParentID, "an unreadable span context starts a trace of its own")
+	assert.Equal(t, true, session.Tag("error"))
+	assert.Len(t, session.Logs(), 1)
+}
+
+func TestTraceContextOnTheWire(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	carrier := map[string]string{"mockpfx-ids-traceid": "1", "mockpfx-ids-spanid": "2"}
+	msg := NewSignRound2Message(pIDs[1], ffi.Round2Result{}, carrier)
+	bz, _, err := msg.WireBytes()
+	assert.NoError(t, err)
+	parsed, err := tss.ParseWireMessage(bz, pIDs[1], true)
+	assert.NoError(t, err)
+	assert.Equal(t, carrier, parsed.Content().(*SignRound2Message).GetTraceContext())
+}
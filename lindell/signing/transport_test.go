@@ -0,0 +1,203 @@
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-rust/lindell/transport"
+	"go-rust/lindell/transport/grpctransport"
+	"go-rust/lindell/transport/relay"
+	"go-rust/lindell/transport/wstransport"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestE2EOverGRPC(t *testing.T) {
+	keys, signPIDs, err := LoadKeygenTestFixtures(2)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := grpctransport.NewServer()
+	gs := grpc.NewServer()
+	grpctransport.RegisterTransportServer(gs, srv)
+	go gs.Serve(lis)
+	defer gs.Stop()
+	cc, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer cc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	const sessionID = "lindell-1"
+	sigs := make(chan *common.SignatureData, 2)
+	errs := make(chan error, 2)
+	for i := range signPIDs {
+		go func(i int) {
+			var conn transport.Conn
+			var err error
+			if i == 0 {
+				conn, _, err = srv.Accept(ctx, sessionID)
+			} else {
+				conn, err = grpctransport.Dial(ctx, cc, sessionID, signPIDs[i])
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+
+			params, err := NewLindellSignParameters(tss.S256(), p2pCtx, signPIDs[i], 2, 1)
+			if err != nil {
+				errs <- err
+				return
+			}
+			out, end := make(chan tss.Message, 2), make(chan common.SignatureData, 1)
+			party := NewLocalParty(big.NewInt(42), params, keys[i], out, end)
+			data, err := transport.Run(ctx, conn, sessionID, signPIDs, party, out, end)
+			sigs <- &data
+			errs <- err
+		}(i)
+	}
+	for range signPIDs {
+		assert.NoError(t, <-errs)
+	}
+	close(sigs)
+
+	pk := ecdsa.PublicKey{Curve: tss.S256(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	for sig := range sigs {
+		if sig.GetR() == nil {
+			continue
+		}
+		assert.True(t, ecdsa.Verify(&pk, big.NewInt(42).Bytes(), new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())))
+	}
+}
+
+func TestE2EOverRelay(t *testing.T) {
+	keys, signPIDs, err := LoadKeygenTestFixtures(2)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+
+	hs := httptest.NewServer(relay.NewServer(time.Minute))
+	defer hs.Close()
+	client := relay.NewClient(hs.URL, hs.Client())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	sess, err := client.CreateSession(ctx, tss.UnSortedPartyIDs{signPIDs[0], signPIDs[1]})
+	assert.NoError(t, err)
+
+	sigs := make(chan *common.SignatureData, 2)
+	errs := make(chan error, 2)
+	for i := range signPIDs {
+		go func(i int) {
+			role, err := ParseRole(sess.Roles[signPIDs[i].Id])
+			if err != nil {
+				errs <- err
+				return
+			}
+			conn, err := client.Join(ctx, sess.SessionID, signPIDs[i], sess.Tokens[signPIDs[i].Id])
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+
+			params, err := NewLindellSignParametersWithRole(tss.S256(), p2pCtx, signPIDs[i], 2, 1, role)
+			if err != nil {
+				errs <- err
+				return
+			}
+			out, end := make(chan tss.Message, 2), make(chan common.SignatureData, 1)
+			party := NewLocalParty(big.NewInt(42), params, keys[i], out, end)
+			data, err := transport.Run(ctx, conn, sess.SessionID, signPIDs, party, out, end)
+			sigs <- &data
+			errs <- err
+		}(i)
+	}
+	for range signPIDs {
+		assert.NoError(t, <-errs)
+	}
+	close(sigs)
+
+	pk := ecdsa.PublicKey{Curve: tss.S256(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	for sig := range sigs {
+		if sig.GetR() == nil {
+			continue
+		}
+		assert.True(t, ecdsa.Verify(&pk, big.NewInt(42).Bytes(), new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())))
+	}
+}
+
+func TestE2EOverWebSocket(t *testing.T) {
+	keys, signPIDs, err := LoadKeygenTestFixtures(2)
+	skipWithoutFixtures(t, err)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+
+	srv := wstransport.NewServer(10*time.Second, nil)
+	hs := httptest.NewServer(srv)
+	defer hs.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client, err := wstransport.Dial(ctx, "ws"+strings.TrimPrefix(hs.URL, "http"), nil, 10*time.Second)
+	assert.NoError(t, err)
+	defer client.Close()
+
+	const sessionID = "lindell-ws-1"
+	sigs := make(chan *common.SignatureData, 2)
+	errs := make(chan error, 2)
+	for i := range signPIDs {
+		go func(i int) {
+			var conn transport.Conn
+			var err error
+			if i == 0 {
+				conn, _, err = srv.Accept(ctx, sessionID)
+			} else {
+				conn, err = client.Open(sessionID, signPIDs[i])
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+
+			params, err := NewLindellSignParameters(tss.S256(), p2pCtx, signPIDs[i], 2, 1)
+			if err != nil {
+				errs <- err
+				return
+			}
+			out, end := make(chan tss.Message, 2), make(chan common.SignatureData, 1)
+			party := NewLocalParty(big.NewInt(42), params, keys[i], out, end)
+			data, err := transport.Run(ctx, conn, sessionID, signPIDs, party, out, end)
+			sigs <- &data
+			errs <- err
+		}(i)
+	}
+	for range signPIDs {
+		assert.NoError(t, <-errs)
+	}
+	close(sigs)
+
+	pk := ecdsa.PublicKey{Curve: tss.S256(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	for sig := range sigs {
+		if sig.GetR() == nil {
+			continue
+		}
+		assert.True(t, ecdsa.Verify(&pk, big.NewInt(42).Bytes(), new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())))
+	}
+}
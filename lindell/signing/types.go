@@ -2,20 +2,211 @@ package signing
 
 import (
 	"crypto/elliptic"
+	"errors"
+	"fmt"
+
+	"go-rust/lindell/ffi"
+	"go-rust/lindell/logging"
+	"go-rust/lindell/metrics"
 
 	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
+)
+
+// Role is the part a party plays in the two-party Lindell 2017 signing protocol.
+type Role int
+
+const (
+	// RoleAuto derives the role from the sorted PartyID set: the party with the lowest key is the server.
+	RoleAuto Role = iota
+	// RoleServer (party 1) holds the Paillier key, opens round 1 and outputs the final signature.
+	RoleServer
+	// RoleClient (party 2) computes the encrypted partial signature in round 2.
+	RoleClient
 )
 
+// Lindell 2017 is strictly a two-party protocol
+const lindellPartyCount = 2
+
 type LindellSignParameters struct {
 	*tss.Parameters
-	isServer bool
+	role         Role
+	curveName    string
+	modulusCache *PaillierModulusCache
+	metrics      metrics.Metrics
+	tracer       opentracing.Tracer
+	parentSpan   opentracing.SpanContext
+	logger       *zap.Logger
+	sessionID    string
 }
 
+// NewLindellSignParameters builds the signing parameters and assigns the role deterministically from the sorted
+// PartyID set, so both parties agree on who is the server without any extra coordination.
 func NewLindellSignParameters(ec elliptic.Curve, ctx *tss.PeerContext, partyID *tss.PartyID, partyCount, threshold int,
-	isServer bool) *LindellSignParameters {
+) (*LindellSignParameters, error) {
+	return NewLindellSignParametersWithRole(ec, ctx, partyID, partyCount, threshold, RoleAuto)
+}
+
+// NewLindellSignParametersWithRole is like NewLindellSignParameters but lets the caller pin the role explicitly.
+// Passing RoleAuto is equivalent to calling NewLindellSignParameters.
+func NewLindellSignParametersWithRole(ec elliptic.Curve, ctx *tss.PeerContext, partyID *tss.PartyID, partyCount,
+	threshold int, role Role) (*LindellSignParameters, error) {
+	curveName, err := ffi.CurveName(ec)
+	if err != nil {
+		return nil, fmt.Errorf("lindell signing: %v", err)
+	}
+	if ctx == nil || !partyID.ValidateBasic() {
+		return nil, errors.New("lindell signing: peer context and party id are required")
+	}
+	if partyCount != lindellPartyCount {
+		return nil, fmt.Errorf("lindell signing: exactly %d parties are required, got partyCount=%d",
+			lindellPartyCount, partyCount)
+	}
+	ids := ctx.IDs()
+	if len(ids) != lindellPartyCount {
+		return nil, fmt.Errorf("lindell signing: exactly %d parties are required, peer context has %d",
+			lindellPartyCount, len(ids))
+	}
+	if threshold+1 != lindellPartyCount {
+		return nil, fmt.Errorf("lindell signing: threshold must be %d for two signers, got %d",
+			lindellPartyCount-1, threshold)
+	}
+	if ids[0].KeyInt().Cmp(ids[1].KeyInt()) == 0 {
+		return nil, errors.New("lindell signing: the two parties share the same key")
+	}
+	self := ids.FindByKey(partyID.KeyInt())
+	if self == nil || self.Index != partyID.Index {
+		return nil, fmt.Errorf("lindell signing: party %s is not part of the peer context", partyID)
+	}
+
+	switch role {
+	case RoleAuto:
+		role = roleForIndex(partyID.Index)
+	case RoleServer, RoleClient:
+	default:
+		return nil, fmt.Errorf("lindell signing: unknown role %d", role)
+	}
+
 	params := tss.NewParameters(ec, ctx, partyID, partyCount, threshold)
 	return &LindellSignParameters{
 		Parameters: params,
-		isServer:   isServer,
+		role:       role,
+		curveName:  curveName,
+	}, nil
+}
+
+func (params *LindellSignParameters) Role() Role {
+	return params.role
+}
+
+// CurveName is the name of EC() as understood by lindellcore
+func (params *LindellSignParameters) CurveName() string {
+	return params.curveName
+}
+
+func (params *LindellSignParameters) IsServer() bool {
+	return params.role == RoleServer
+}
+
+// PaillierModulusCache returns the cache of verified server moduli, DefaultPaillierModulusCache unless
+// SetPaillierModulusCache was called
+func (params *LindellSignParameters) PaillierModulusCache() *PaillierModulusCache {
+	if params.modulusCache == nil {
+		return DefaultPaillierModulusCache
+	}
+	return params.modulusCache
+}
+
+func (params *LindellSignParameters) SetPaillierModulusCache(cache *PaillierModulusCache) {
+	params.modulusCache = cache
+}
+
+// Metrics returns where the session reports its measurements, nowhere unless SetMetrics was called
+func (params *LindellSignParameters) Metrics() metrics.Metrics {
+	if params.metrics == nil {
+		return metrics.Nop{}
+	}
+	return params.metrics
+}
+
+func (params *LindellSignParameters) SetMetrics(m metrics.Metrics) {
+	params.metrics = m
+}
+
+// Tracer returns the tracer of the session's spans, the global tracer unless SetTracer was called
+func (params *LindellSignParameters) Tracer() opentracing.Tracer {
+	if params.tracer == nil {
+		return opentracing.GlobalTracer()
+	}
+	return params.tracer
+}
+
+func (params *LindellSignParameters) SetTracer(tracer opentracing.Tracer) {
+	params.tracer = tracer
+}
+
+// ParentSpanContext is the span the session's span is a child of, e.g. the request that asked for the signature.
+// The client's session span is a child of the server's, it only follows from its parent.
+func (params *LindellSignParameters) ParentSpanContext() opentracing.SpanContext {
+	return params.parentSpan
+}
+
+func (params *LindellSignParameters) SetParentSpanContext(parent opentracing.SpanContext) {
+	params.parentSpan = parent
+}
+
+// Logger returns the logger of the session's entries, logging.Default() unless SetLogger was called. It never
+// renders a secret.
+func (params *LindellSignParameters) Logger() *zap.Logger {
+	if params.logger == nil {
+		return logging.Default()
+	}
+	return params.logger
+}
+
+// SetLogger replaces the session's logger, wrapping it with logging.Redacting
+func (params *LindellSignParameters) SetLogger(logger *zap.Logger) {
+	params.logger = logging.Redacting(logger)
+}
+
+// SessionID names the session in its log entries; set the same one on both parties to correlate their entries.
+// Without it each party logs a random one.
+func (params *LindellSignParameters) SessionID() string {
+	return params.sessionID
+}
+
+func (params *LindellSignParameters) SetSessionID(id string) {
+	params.sessionID = id
+}
+
+// roleForIndex maps a position in the sorted PartyID set to its role
+func roleForIndex(index int) Role {
+	if index == 0 {
+		return RoleServer
+	}
+	return RoleClient
+}
+
+func (r Role) String() string {
+	switch r {
+	case RoleAuto:
+		return "auto"
+	case RoleServer:
+		return "server"
+	case RoleClient:
+		return "client"
+	default:
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+}
+
+// ParseRole is the inverse of Role.String, e.g. for the roles a relay assigns
+func ParseRole(s string) (Role, error) {
+	for _, r := range []Role{RoleAuto, RoleServer, RoleClient} {
+		if r.String() == s {
+			return r, nil
+		}
 	}
+	return RoleAuto, fmt.Errorf("unknown role %q", s)
 }
@@ -0,0 +1,65 @@
+package signing
+
+import (
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleAssignedFromSortedPartyIDs(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	server, err := NewLindellSignParameters(tss.S256(), p2pCtx, pIDs[0], 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleServer, server.Role())
+	assert.True(t, server.IsServer())
+
+	client, err := NewLindellSignParameters(tss.S256(), p2pCtx, pIDs[1], 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleClient, client.Role())
+	assert.False(t, client.IsServer())
+}
+
+func TestExplicitRoleOverridesSortedOrder(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	params, err := NewLindellSignParametersWithRole(tss.S256(), p2pCtx, pIDs[0], 2, 1, RoleClient)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleClient, params.Role())
+
+	_, err = NewLindellSignParametersWithRole(tss.S256(), p2pCtx, pIDs[0], 2, 1, Role(42))
+	assert.Error(t, err, "unknown roles must be rejected")
+}
+
+func TestInconsistentConfigurationRejected(t *testing.T) {
+	two := tss.GenerateTestPartyIDs(2)
+	three := tss.GenerateTestPartyIDs(3)
+
+	_, err := NewLindellSignParameters(tss.S256(), tss.NewPeerContext(three), three[0], 3, 1)
+	assert.Error(t, err, "three parties must be rejected")
+
+	_, err = NewLindellSignParameters(tss.S256(), tss.NewPeerContext(three), three[0], 2, 1)
+	assert.Error(t, err, "a peer context larger than two must be rejected")
+
+	_, err = NewLindellSignParameters(tss.S256(), tss.NewPeerContext(two), two[0], 2, 0)
+	assert.Error(t, err, "threshold must allow exactly two signers")
+
+	_, err = NewLindellSignParameters(tss.S256(), tss.NewPeerContext(two), three[2], 2, 1)
+	assert.Error(t, err, "party outside of the peer context must be rejected")
+
+	_, err = NewLindellSignParameters(nil, tss.NewPeerContext(two), two[0], 2, 1)
+	assert.Error(t, err, "nil curve must be rejected")
+}
+
+func TestParseRole(t *testing.T) {
+	for _, r := range []Role{RoleAuto, RoleServer, RoleClient} {
+		parsed, err := ParseRole(r.String())
+		assert.NoError(t, err)
+		assert.Equal(t, r, parsed)
+	}
+	_, err := ParseRole("observer")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package signing
+
+import (
+	"sync/atomic"
+
+	"go-rust/lindell/zeroize"
+)
+
+// lockSession is deferred by Start and Update: defer p.lockSession()(). The func it returns wipes the session's
+// secrets if the session ended meanwhile.
+func (p *LocalParty) lockSession() func() {
+	p.sessionMtx.Lock()
+	return func() {
+		if p.temp.hasEnded() {
+			p.temp.wipe()
+		}
+		p.sessionMtx.Unlock()
+	}
+}
+
+// end marks the session as over, whatever its outcome
+func (temp *localTempData) end() {
+	atomic.StoreInt32(&temp.ended, 1)
+}
+
+func (temp *localTempData) hasEnded() bool {
+	return atomic.LoadInt32(&temp.ended) == 1
+}
+
+// wipe overwrites the secrets of the session: the weighted share wi, the derived share xi and the ephemeral key
+// pair of round 1. The key share itself belongs to the caller and is left alone. Called with sessionMtx held.
+func (temp *localTempData) wipe() {
+	zeroize.BigInt(temp.secretShare)
+	zeroize.BigInt(temp.derivedXi)
+	temp.secretShare, temp.derivedXi = nil, nil
+	if temp.round1Rst != nil {
+		temp.round1Rst.Zeroize()
+		temp.round1Rst = nil
+	}
+}
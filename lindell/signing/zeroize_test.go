@@ -0,0 +1,87 @@
+package signing
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"go-rust/lindell/ffi"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestParty returns a server party over a key that is good enough to prepare but not to sign
+func newTestParty(t *testing.T, msg, delta *big.Int) (*LocalParty, keygen.LocalPartySaveData) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	params, err := NewLindellSignParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], 2, 1)
+	assert.NoError(t, err)
+	key := keygen.NewLocalPartySaveData(2)
+	key.Ks = []*big.Int{pIDs[0].KeyInt(), pIDs[1].KeyInt()}
+	key.Xi = big.NewInt(1234567)
+	key.ECDSAPub = crypto.ScalarBaseMult(tss.S256(), big.NewInt(7))
+	return NewLocalPartyWithKDD(msg, params, key, delta, nil, nil).(*LocalParty), key
+}
+
+// withSecrets fills in the secrets a session holds after round 1 and returns their backing arrays
+func withSecrets(p *LocalParty) ([]big.Word, []uint) {
+	p.temp.secretShare = new(big.Int).Lsh(big.NewInt(987654321), 200)
+	p.temp.round1Rst = &ffi.Round1Result{EphEcKeyPairParty1: ffi.EphEcKeyPair{
+		SecretShare: ffi.Scalar{Curve: ffi.CurveSecp256k1, Scalar: []uint{9, 8, 7}},
+	}}
+	return p.temp.secretShare.Bits(), p.temp.round1Rst.EphEcKeyPairParty1.SecretShare.Scalar
+}
+
+func assertWiped(t *testing.T, p *LocalParty, words []big.Word, scalar []uint) {
+	assert.Nil(t, p.temp.secretShare)
+	assert.Nil(t, p.temp.round1Rst)
+	assert.Nil(t, p.temp.derivedXi)
+	for _, w := range words {
+		assert.Zero(t, w)
+	}
+	assert.Equal(t, []uint{0, 0, 0}, scalar)
+}
+
+func TestSecretsWipedWhenAborted(t *testing.T) {
+	p, _ := newTestParty(t, big.NewInt(42), nil)
+	words, scalar := withSecrets(p)
+	p.Abort(context.DeadlineExceeded)
+	assertWiped(t, p, words, scalar)
+
+	// the session is over: no round runs on wiped secrets
+	assert.Error(t, p.Start())
+	pIDs := p.params.Parties().IDs()
+	ok, err := p.Update(NewSignRound2Message(pIDs[1], ffi.Round2Result{}, nil).(tss.ParsedMessage))
+	assert.False(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestSecretsWipedAfterTheRunningRound(t *testing.T) {
+	p, _ := newTestParty(t, big.NewInt(42), nil)
+	words, scalar := withSecrets(p)
+	unlock := p.lockSession()
+	p.Abort(context.DeadlineExceeded)
+	assert.NotNil(t, p.temp.secretShare, "the secrets of a running round are left to it")
+	unlock()
+	assertWiped(t, p, words, scalar)
+}
+
+func TestSecretsWipedOnError(t *testing.T) {
+	// the message is too large, so round 1 fails right after prepare computed wi and the derived xi
+	p, key := newTestParty(t, tss.S256().Params().N, big.NewInt(5))
+	assert.NotNil(t, p.Start())
+	assertWiped(t, p, nil, []uint{0, 0, 0})
+	assert.Equal(t, int64(1234567), key.Xi.Int64(), "the caller's key share is left alone")
+	assert.Zero(t, p.keys.Xi.Sign(), "the derived share is wiped")
+}
+
+func TestSecretsWipedOnSuccess(t *testing.T) {
+	p, _ := newTestParty(t, big.NewInt(42), nil)
+	words, scalar := withSecrets(p)
+	unlock := p.lockSession()
+	p.FirstRound().(*round1).succeed()
+	unlock()
+	assertWiped(t, p, words, scalar)
+}
@@ -0,0 +1,113 @@
+package grpctransport
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"google.golang.org/grpc"
+)
+
+// frameStream is the part of Transport_SessionClient and Transport_SessionServer a Conn needs
+type frameStream interface {
+	Send(*Frame) error
+	Recv() (*Frame, error)
+}
+
+type streamConn struct {
+	stream    frameStream
+	sessionID string
+	sendMtx   sync.Mutex
+	closeOnce sync.Once
+	close     func() error
+}
+
+var _ transport.Conn = (*streamConn)(nil)
+
+// Dial opens the session stream to a Server and announces self as the calling party
+func Dial(ctx context.Context, cc grpc.ClientConnInterface, sessionID string, self *tss.PartyID) (transport.Conn, error) {
+	if sessionID == "" || self == nil {
+		return nil, errors.New("a session id and the calling party are required")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := NewTransportClient(cc).Session(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err = stream.Send(&Frame{SessionID: sessionID, From: newPartyID(self)}); err != nil {
+		cancel()
+		return nil, err
+	}
+	return &streamConn{
+		stream:    stream,
+		sessionID: sessionID,
+		close: func() error {
+			err := stream.CloseSend()
+			cancel()
+			return err
+		},
+	}, nil
+}
+
+func (c *streamConn) Send(env *transport.Envelope) error {
+	if env.SessionID != c.sessionID {
+		return errors.New("envelope belongs to another session")
+	}
+	frame := &Frame{
+		SessionID:   env.SessionID,
+		From:        newPartyID(env.From),
+		IsBroadcast: env.IsBroadcast,
+		WireBytes:   env.WireBytes,
+	}
+	for _, to := range env.To {
+		frame.To = append(frame.To, newPartyID(to))
+	}
+	// grpc streams allow one concurrent sender
+	c.sendMtx.Lock()
+	defer c.sendMtx.Unlock()
+	return c.stream.Send(frame)
+}
+
+func (c *streamConn) Recv() (*transport.Envelope, error) {
+	frame, err := c.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	env := &transport.Envelope{
+		SessionID:   frame.GetSessionID(),
+		From:        frame.GetFrom().unmarshal(),
+		IsBroadcast: frame.GetIsBroadcast(),
+		WireBytes:   frame.GetWireBytes(),
+	}
+	for _, to := range frame.GetTo() {
+		env.To = append(env.To, to.unmarshal())
+	}
+	return env, nil
+}
+
+func (c *streamConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() { err = c.close() })
+	return err
+}
+
+// ----- //
+
+func newPartyID(p *tss.PartyID) *PartyID {
+	if p == nil {
+		return nil
+	}
+	return &PartyID{Id: p.Id, Moniker: p.Moniker, Key: p.Key}
+}
+
+func (p *PartyID) unmarshal() *tss.PartyID {
+	if p == nil {
+		return nil
+	}
+	return tss.NewPartyID(p.GetId(), p.GetMoniker(), new(big.Int).SetBytes(p.GetKey()))
+}
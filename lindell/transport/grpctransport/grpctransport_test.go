@@ -0,0 +1,202 @@
+package grpctransport
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net"
+	"testing"
+	"time"
+
+	"go-rust/lindell/eddsa"
+	"go-rust/lindell/eddsa/keygen"
+	"go-rust/lindell/eddsa/signing"
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// startLoopback serves a Server on a local port and returns a client connection to it
+func startLoopback(t *testing.T) (*Server, *grpc.ClientConn) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := NewServer()
+	gs := grpc.NewServer()
+	RegisterTransportServer(gs, srv)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	cc, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { cc.Close() })
+	return srv, cc
+}
+
+// runPair runs party 0 behind the server and party 1 behind the client and returns both results
+func runPair[T any](t *testing.T, srv *Server, cc *grpc.ClientConn, sessionID string, pIDs tss.SortedPartyIDs,
+	newParty func(i int, out chan<- tss.Message, end chan<- T) tss.Party) [2]T {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var results [2]T
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			var conn transport.Conn
+			var err error
+			if i == 0 {
+				var peer *tss.PartyID
+				conn, peer, err = srv.Accept(ctx, sessionID)
+				if err == nil && peer.KeyInt().Cmp(pIDs[1].KeyInt()) != 0 {
+					t.Errorf("accepted %s, expected %s", peer, pIDs[1])
+				}
+			} else {
+				conn, err = Dial(ctx, cc, sessionID, pIDs[1])
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+
+			out, end := make(chan tss.Message, 2), make(chan T, 1)
+			results[i], err = transport.Run(ctx, conn, sessionID, pIDs, newParty(i, out, end), out, end)
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, <-errs)
+	}
+	return results
+}
+
+func TestLoopbackKeygenAndSigning(t *testing.T) {
+	srv, cc := startLoopback(t)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	keys := runPair(t, srv, cc, "keygen-1", pIDs,
+		func(i int, out chan<- tss.Message, end chan<- keygen.LocalPartySaveData) tss.Party {
+			return keygen.NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], 2, 1), out, end)
+		})
+	assert.True(t, keys[0].EDDSAPub.Equals(keys[1].EDDSAPub), "both parties must agree on the public key")
+
+	msg := []byte("over the wire")
+	sigs := runPair(t, srv, cc, "signing-1", pIDs,
+		func(i int, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Party {
+			return signing.NewLocalParty(msg, tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], 2, 1), keys[i], out, end)
+		})
+	pub := ed25519.PublicKey(eddsa.EncodePoint(keys[0].EDDSAPub))
+	for _, sig := range sigs {
+		assert.True(t, ed25519.Verify(pub, msg, sig.GetSignature()), "ed25519 verify must pass")
+	}
+}
+
+func TestRejectsUnknownSender(t *testing.T) {
+	srv, cc := startLoopback(t)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	accepted := make(chan transport.Conn, 1)
+	go func() {
+		conn, _, err := srv.Accept(ctx, "spoof")
+		assert.NoError(t, err)
+		accepted <- conn
+	}()
+	client, err := Dial(ctx, cc, "spoof", pIDs[1])
+	assert.NoError(t, err)
+	defer client.Close()
+	server := <-accepted
+	defer server.Close()
+
+	// a frame from a party outside the session must fail the receiving party's run
+	stranger := tss.GenerateTestPartyIDs(1, 5)[0]
+	assert.NoError(t, client.Send(&transport.Envelope{SessionID: "spoof", From: stranger, IsBroadcast: true, WireBytes: []byte{1}}))
+
+	out, end := make(chan tss.Message, 2), make(chan keygen.LocalPartySaveData, 1)
+	party := keygen.NewLocalParty(tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[0], 2, 1), out, end)
+	_, err = transport.Run(ctx, server, "spoof", pIDs, party, out, end)
+	assert.Error(t, err)
+}
+
+func TestSecondPeerRejected(t *testing.T) {
+	_, cc := startLoopback(t)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first, err := Dial(ctx, cc, "busy", pIDs[1])
+	assert.NoError(t, err)
+	defer first.Close()
+	second, err := Dial(ctx, cc, "busy", pIDs[0])
+	assert.NoError(t, err)
+	defer second.Close()
+
+	// whichever hello reaches the server last is turned away
+	errs := make(chan error, 2)
+	for _, conn := range []transport.Conn{first, second} {
+		go func(conn transport.Conn) {
+			_, err := conn.Recv()
+			errs <- err
+		}(conn)
+	}
+	assert.Equal(t, codes.AlreadyExists, status.Code(<-errs), "a queued session must not take a second peer")
+}
+
+func TestAcceptedSessionCannotBeJoinedAgain(t *testing.T) {
+	srv, cc := startLoopback(t)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	accepted := make(chan transport.Conn, 1)
+	go func() {
+		conn, _, err := srv.Accept(ctx, "once")
+		assert.NoError(t, err)
+		accepted <- conn
+	}()
+	first, err := Dial(ctx, cc, "once", pIDs[1])
+	assert.NoError(t, err)
+	defer first.Close()
+	server := <-accepted
+	server.Close()
+
+	// the ID stays taken after its session ended, a late stream is answered rather than parked
+	again, err := Dial(ctx, cc, "once", pIDs[1])
+	assert.NoError(t, err)
+	defer again.Close()
+	_, err = again.Recv()
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, _, err = srv.Accept(ctx, "once")
+	assert.Error(t, err)
+}
+
+func TestAcceptedSessionsExpire(t *testing.T) {
+	srv := NewServer()
+	now := time.Now()
+	srv.now = func() time.Time { return now }
+
+	for _, id := range []string{"first", "second"} {
+		srv.release(id, true)
+		now = now.Add(AcceptedTTL / 2)
+	}
+	_, ok := srv.sessionChan("first")
+	assert.False(t, ok, "a session is remembered for AcceptedTTL")
+
+	// the next release drops the tombstones older than AcceptedTTL
+	now = now.Add(time.Second)
+	srv.release("third", false)
+	assert.Len(t, srv.accepted, 1)
+	assert.Len(t, srv.acceptedOrder, 1)
+	_, ok = srv.sessionChan("second")
+	assert.False(t, ok)
+	_, ok = srv.sessionChan("first")
+	assert.True(t, ok, "an expired session ID is no longer turned away")
+}
@@ -0,0 +1,277 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v3.20.3
+// source: lindell-transport.proto
+
+package grpctransport
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PartyID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Moniker string `protobuf:"bytes,2,opt,name=moniker,proto3" json:"moniker,omitempty"`
+	Key     []byte `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *PartyID) Reset() {
+	*x = PartyID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_transport_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PartyID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyID) ProtoMessage() {}
+
+func (x *PartyID) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_transport_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyID.ProtoReflect.Descriptor instead.
+func (*PartyID) Descriptor() ([]byte, []int) {
+	return file_lindell_transport_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PartyID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PartyID) GetMoniker() string {
+	if x != nil {
+		return x.Moniker
+	}
+	return ""
+}
+
+func (x *PartyID) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+// The routing of a tss message together with its WireBytes()
+type Frame struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionID   string     `protobuf:"bytes,1,opt,name=sessionID,proto3" json:"sessionID,omitempty"`
+	From        *PartyID   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To          []*PartyID `protobuf:"bytes,3,rep,name=to,proto3" json:"to,omitempty"`
+	IsBroadcast bool       `protobuf:"varint,4,opt,name=isBroadcast,proto3" json:"isBroadcast,omitempty"`
+	WireBytes   []byte     `protobuf:"bytes,5,opt,name=wireBytes,proto3" json:"wireBytes,omitempty"`
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lindell_transport_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_lindell_transport_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_lindell_transport_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Frame) GetSessionID() string {
+	if x != nil {
+		return x.SessionID
+	}
+	return ""
+}
+
+func (x *Frame) GetFrom() *PartyID {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Frame) GetTo() []*PartyID {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Frame) GetIsBroadcast() bool {
+	if x != nil {
+		return x.IsBroadcast
+	}
+	return false
+}
+
+func (x *Frame) GetWireBytes() []byte {
+	if x != nil {
+		return x.WireBytes
+	}
+	return nil
+}
+
+var File_lindell_transport_proto protoreflect.FileDescriptor
+
+var file_lindell_transport_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70,
+	0x6f, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x6c, 0x69, 0x6e, 0x64, 0x65,
+	0x6c, 0x6c, 0x2e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x45, 0x0a, 0x07,
+	0x50, 0x61, 0x72, 0x74, 0x79, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x6e, 0x69, 0x6b,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x6f, 0x6e, 0x69, 0x6b, 0x65,
+	0x72, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x22, 0xc1, 0x01, 0x0a, 0x05, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x2e, 0x0a, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x69, 0x6e, 0x64,
+	0x65, 0x6c, 0x6c, 0x2e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x50, 0x61,
+	0x72, 0x74, 0x79, 0x49, 0x44, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74,
+	0x6f, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c,
+	0x6c, 0x2e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x50, 0x61, 0x72, 0x74,
+	0x79, 0x49, 0x44, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x73, 0x42, 0x72, 0x6f,
+	0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73,
+	0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x69, 0x72,
+	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x77, 0x69,
+	0x72, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x32, 0x4e, 0x0a, 0x09, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x41, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x2e, 0x6c, 0x69, 0x6e, 0x64, 0x65, 0x6c, 0x6c, 0x2e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70,
+	0x6f, 0x72, 0x74, 0x2e, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x1a, 0x18, 0x2e, 0x6c, 0x69, 0x6e, 0x64,
+	0x65, 0x6c, 0x6c, 0x2e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x46, 0x72,
+	0x61, 0x6d, 0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x21, 0x5a, 0x1f, 0x6c, 0x69, 0x6e, 0x64, 0x65,
+	0x6c, 0x6c, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_lindell_transport_proto_rawDescOnce sync.Once
+	file_lindell_transport_proto_rawDescData = file_lindell_transport_proto_rawDesc
+)
+
+func file_lindell_transport_proto_rawDescGZIP() []byte {
+	file_lindell_transport_proto_rawDescOnce.Do(func() {
+		file_lindell_transport_proto_rawDescData = protoimpl.X.CompressGZIP(file_lindell_transport_proto_rawDescData)
+	})
+	return file_lindell_transport_proto_rawDescData
+}
+
+var file_lindell_transport_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_lindell_transport_proto_goTypes = []interface{}{
+	(*PartyID)(nil), // 0: lindell.transport.PartyID
+	(*Frame)(nil),   // 1: lindell.transport.Frame
+}
+var file_lindell_transport_proto_depIdxs = []int32{
+	0, // 0: lindell.transport.Frame.from:type_name -> lindell.transport.PartyID
+	0, // 1: lindell.transport.Frame.to:type_name -> lindell.transport.PartyID
+	1, // 2: lindell.transport.Transport.Session:input_type -> lindell.transport.Frame
+	1, // 3: lindell.transport.Transport.Session:output_type -> lindell.transport.Frame
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_lindell_transport_proto_init() }
+func file_lindell_transport_proto_init() {
+	if File_lindell_transport_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_lindell_transport_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PartyID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lindell_transport_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Frame); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_lindell_transport_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lindell_transport_proto_goTypes,
+		DependencyIndexes: file_lindell_transport_proto_depIdxs,
+		MessageInfos:      file_lindell_transport_proto_msgTypes,
+	}.Build()
+	File_lindell_transport_proto = out.File
+	file_lindell_transport_proto_rawDesc = nil
+	file_lindell_transport_proto_goTypes = nil
+	file_lindell_transport_proto_depIdxs = nil
+}
@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.20.3
+// source: lindell-transport.proto
+
+package grpctransport
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Transport_Session_FullMethodName = "/lindell.transport.Transport/Session"
+)
+
+// TransportClient is the client API for Transport service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransportClient interface {
+	Session(ctx context.Context, opts ...grpc.CallOption) (Transport_SessionClient, error)
+}
+
+type transportClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransportClient(cc grpc.ClientConnInterface) TransportClient {
+	return &transportClient{cc}
+}
+
+func (c *transportClient) Session(ctx context.Context, opts ...grpc.CallOption) (Transport_SessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Transport_ServiceDesc.Streams[0], Transport_Session_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transportSessionClient{stream}
+	return x, nil
+}
+
+type Transport_SessionClient interface {
+	Send(*Frame) error
+	Recv() (*Frame, error)
+	grpc.ClientStream
+}
+
+type transportSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *transportSessionClient) Send(m *Frame) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *transportSessionClient) Recv() (*Frame, error) {
+	m := new(Frame)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TransportServer is the server API for Transport service.
+// All implementations must embed UnimplementedTransportServer
+// for forward compatibility
+type TransportServer interface {
+	Session(Transport_SessionServer) error
+	mustEmbedUnimplementedTransportServer()
+}
+
+// UnimplementedTransportServer must be embedded to have forward compatible implementations.
+type UnimplementedTransportServer struct {
+}
+
+func (UnimplementedTransportServer) Session(Transport_SessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedTransportServer) mustEmbedUnimplementedTransportServer() {}
+
+// UnsafeTransportServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransportServer will
+// result in compilation errors.
+type UnsafeTransportServer interface {
+	mustEmbedUnimplementedTransportServer()
+}
+
+func RegisterTransportServer(s grpc.ServiceRegistrar, srv TransportServer) {
+	s.RegisterService(&Transport_ServiceDesc, srv)
+}
+
+func _Transport_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TransportServer).Session(&transportSessionServer{stream})
+}
+
+type Transport_SessionServer interface {
+	Send(*Frame) error
+	Recv() (*Frame, error)
+	grpc.ServerStream
+}
+
+type transportSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *transportSessionServer) Send(m *Frame) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *transportSessionServer) Recv() (*Frame, error) {
+	m := new(Frame)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Transport_ServiceDesc is the grpc.ServiceDesc for Transport service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Transport_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lindell.transport.Transport",
+	HandlerType: (*TransportServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Session",
+			Handler:       _Transport_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "lindell-transport.proto",
+}
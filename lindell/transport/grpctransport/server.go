@@ -0,0 +1,154 @@
+// Package grpctransport carries two-party sessions over a bidirectional gRPC stream per session. One process
+// registers a Server on its grpc.Server and Accepts sessions, the other Dials them.
+package grpctransport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AcceptedTTL is how long a Server remembers the ID of an accepted session to turn away later streams with it. A
+// session ID is unique, so only a stream that arrives later than this is left waiting for an Accept.
+const AcceptedTTL = 24 * time.Hour
+
+// Server hands each incoming session stream to the Accept call waiting for its session ID
+type Server struct {
+	UnimplementedTransportServer
+
+	now func() time.Time
+
+	mtx     sync.Mutex
+	pending map[string]chan *acceptedConn
+	// IDs of the sessions accepted within AcceptedTTL, with acceptedOrder holding them oldest first to expire them
+	accepted      map[string]struct{}
+	acceptedOrder []tombstone
+}
+
+type tombstone struct {
+	sessionID string
+	at        time.Time
+}
+
+type acceptedConn struct {
+	*streamConn
+	peer *tss.PartyID
+	done chan struct{}
+}
+
+func NewServer() *Server {
+	return &Server{now: time.Now, pending: make(map[string]chan *acceptedConn), accepted: make(map[string]struct{})}
+}
+
+// Accept waits until the peer of sessionID connects and returns the stream as a Conn. The stream stays open until
+// the Conn is closed or ctx of the caller's RPC ends. A session ID can only be accepted once.
+func (s *Server) Accept(ctx context.Context, sessionID string) (transport.Conn, *tss.PartyID, error) {
+	ch, ok := s.sessionChan(sessionID)
+	if !ok {
+		return nil, nil, fmt.Errorf("grpctransport: session %q was already accepted", sessionID)
+	}
+	select {
+	case conn := <-ch:
+		s.release(sessionID, true)
+		return conn, conn.peer, nil
+	case <-ctx.Done():
+		s.release(sessionID, false)
+		return nil, nil, ctx.Err()
+	}
+}
+
+// Session implements TransportServer
+func (s *Server) Session(stream Transport_SessionServer) error {
+	hello, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if hello.GetSessionID() == "" || hello.GetFrom() == nil || len(hello.GetWireBytes()) != 0 {
+		return status.Error(codes.InvalidArgument, "the first frame must name the session and the calling party")
+	}
+	done := make(chan struct{})
+	conn := &acceptedConn{
+		streamConn: &streamConn{
+			stream:    stream,
+			sessionID: hello.GetSessionID(),
+			close: func() error {
+				close(done)
+				return nil
+			},
+		},
+		peer: hello.GetFrom().unmarshal(),
+		done: done,
+	}
+	ch, ok := s.sessionChan(hello.GetSessionID())
+	if !ok {
+		return status.Error(codes.AlreadyExists, "the session was already accepted")
+	}
+	select {
+	case ch <- conn:
+	default:
+		return status.Error(codes.AlreadyExists, "the session already has a peer")
+	}
+
+	// returning ends the stream, so hold it until the local side is finished with it
+	select {
+	case <-done:
+		return nil
+	case <-stream.Context().Done():
+		// take the stream back if nobody accepted it
+		select {
+		case queued := <-ch:
+			if queued != conn {
+				ch <- queued
+			} else {
+				s.release(hello.GetSessionID(), false)
+			}
+		default:
+		}
+		return stream.Context().Err()
+	}
+}
+
+// sessionChan returns the channel an incoming stream of sessionID is handed over on, false once the session was
+// accepted
+func (s *Server) sessionChan(sessionID string) (chan *acceptedConn, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, done := s.accepted[sessionID]; done {
+		return nil, false
+	}
+	ch, ok := s.pending[sessionID]
+	if !ok {
+		ch = make(chan *acceptedConn, 1)
+		s.pending[sessionID] = ch
+	}
+	return ch, true
+}
+
+// release forgets the pending channel of sessionID. An accepted session leaves a tombstone for AcceptedTTL, so a later
+// stream with its ID is turned away rather than left waiting for an Accept that never comes.
+func (s *Server) release(sessionID string, accepted bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.pending, sessionID)
+	now := s.now()
+	expired := 0
+	for _, ts := range s.acceptedOrder {
+		if now.Sub(ts.at) <= AcceptedTTL {
+			break
+		}
+		delete(s.accepted, ts.sessionID)
+		expired++
+	}
+	s.acceptedOrder = s.acceptedOrder[expired:]
+	if accepted {
+		s.accepted[sessionID] = struct{}{}
+		s.acceptedOrder = append(s.acceptedOrder, tombstone{sessionID: sessionID, at: now})
+	}
+}
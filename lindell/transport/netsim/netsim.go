@@ -0,0 +1,319 @@
+// Package netsim is an in-memory network for tss parties that delays, reorders, duplicates, loses and partitions
+// their messages. Time is simulated and every random choice is drawn from one seeded source, so a session run through
+// a Network delivers the same messages in the same order for the same Seed.
+//
+// Determinism needs the parties to send on their out channel from within Start and Update, as the parties of this
+// module do. The out channels and the parties' end channels must be buffered, Run calls the parties from a single
+// goroutine.
+package netsim
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// Config describes the conditions of a Network. The zero value delivers every message once and instantly, in the order
+// it was sent.
+type Config struct {
+	// Seed drives every random choice of the network
+	Seed int64
+	// MinLatency and MaxLatency bound the uniformly drawn delay of each message
+	MinLatency, MaxLatency time.Duration
+	// Reorder is the probability that a message is held back by another MaxLatency, so messages sent after it overtake
+	// it. It has no effect without a MaxLatency.
+	Reorder float64
+	// Duplicate is the probability that a message is delivered a second time, after its own delay
+	Duplicate float64
+	// Loss is the probability that a message is never delivered
+	Loss float64
+}
+
+// Delivery is a message the network handed to a party
+type Delivery struct {
+	At       time.Duration
+	From, To int
+	Type     string
+	// Duplicate is set on the second copy of a duplicated message
+	Duplicate bool
+}
+
+// Stats counts what happened to the messages sent on a Network
+type Stats struct {
+	// Sent counts messages per receiver, a broadcast counts once for every party it is sent to
+	Sent, Delivered, Duplicated, Lost int
+	// Partitioned counts messages lost because sender and receiver were partitioned when it arrived
+	Partitioned int
+}
+
+// Network carries the messages of the parties of one session
+type Network struct {
+	cfg   Config
+	rng   *rand.Rand
+	ids   tss.SortedPartyIDs
+	nodes []*node
+
+	now    time.Duration
+	seq    uint64
+	queue  eventQueue
+	groups []int
+
+	deliveries []Delivery
+	stats      Stats
+}
+
+type node struct {
+	party tss.Party
+	out   <-chan tss.Message
+}
+
+// New returns an idle network for the parties ids
+func New(cfg Config, ids tss.SortedPartyIDs) (*Network, error) {
+	if cfg.MinLatency < 0 || cfg.MaxLatency < cfg.MinLatency {
+		return nil, fmt.Errorf("netsim: latency range [%v, %v] is invalid", cfg.MinLatency, cfg.MaxLatency)
+	}
+	for _, p := range []float64{cfg.Reorder, cfg.Duplicate, cfg.Loss} {
+		if p < 0 || p > 1 {
+			return nil, fmt.Errorf("netsim: probability %v is not within [0, 1]", p)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("netsim: no parties")
+	}
+	return &Network{
+		cfg:    cfg,
+		rng:    rand.New(rand.NewSource(cfg.Seed)),
+		ids:    ids,
+		nodes:  make([]*node, len(ids)),
+		groups: make([]int, len(ids)),
+	}, nil
+}
+
+// Add connects party, which sends on out, and has Run start it after startAfter. A party started late finds the
+// messages that reached it before in its store.
+func (n *Network) Add(party tss.Party, out <-chan tss.Message, startAfter time.Duration) error {
+	i, err := n.index(party.PartyID())
+	if err != nil {
+		return err
+	}
+	if n.nodes[i] != nil {
+		return fmt.Errorf("netsim: party %s was added twice", party.PartyID())
+	}
+	n.nodes[i] = &node{party: party, out: out}
+	n.schedule(startAfter, &event{to: i, start: true})
+	return nil
+}
+
+// At has Run call f once the simulated clock reaches at, e.g. to Partition or Heal the network mid-session
+func (n *Network) At(at time.Duration, f func()) {
+	n.schedule(at-n.now, &event{call: f})
+}
+
+// Partition splits the network into groups; a message arriving from a party in another group is lost. Parties that
+// are in no group form one more group.
+func (n *Network) Partition(groups ...[]*tss.PartyID) error {
+	split := make([]int, len(n.ids))
+	for g, group := range groups {
+		for _, id := range group {
+			i, err := n.index(id)
+			if err != nil {
+				return err
+			}
+			split[i] = g + 1
+		}
+	}
+	n.groups = split
+	return nil
+}
+
+// Heal ends a partition
+func (n *Network) Heal() {
+	n.groups = make([]int, len(n.ids))
+}
+
+// Run starts the parties and delivers messages until none is in flight. It returns the first error a party reports.
+// A session that lost messages is still waiting when Run returns nil, which the parties' WaitingFor tells.
+func (n *Network) Run() error {
+	for n.queue.Len() > 0 {
+		ev := heap.Pop(&n.queue).(*event)
+		n.now = ev.at
+		if err := n.handle(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Now is the simulated time
+func (n *Network) Now() time.Duration {
+	return n.now
+}
+
+// Deliveries lists the messages handed to the parties, in the order they arrived
+func (n *Network) Deliveries() []Delivery {
+	return append([]Delivery(nil), n.deliveries...)
+}
+
+// Stats counts what happened to the messages sent so far
+func (n *Network) Stats() Stats {
+	return n.stats
+}
+
+func (n *Network) handle(ev *event) error {
+	switch {
+	case ev.call != nil:
+		ev.call()
+		return nil
+	case ev.start:
+		to := n.nodes[ev.to]
+		if err := to.party.Start(); err != nil {
+			return fmt.Errorf("netsim: %s failed to start at %v: %w", to.party.PartyID(), n.now, err)
+		}
+		return n.collect(to)
+	}
+	if n.groups[ev.from] != n.groups[ev.to] {
+		n.stats.Partitioned++
+		return nil
+	}
+	to := n.nodes[ev.to]
+	n.stats.Delivered++
+	n.deliveries = append(n.deliveries, Delivery{At: n.now, From: ev.from, To: ev.to, Type: ev.msgType, Duplicate: ev.duplicate})
+	if _, err := to.party.UpdateFromBytes(ev.wire, n.ids[ev.from], ev.broadcast); err != nil {
+		return fmt.Errorf("netsim: %s failed at %v: %w", to.party.PartyID(), n.now, err)
+	}
+	return n.collect(to)
+}
+
+// collect sends the messages a party output while it was called
+func (n *Network) collect(from *node) error {
+	for {
+		select {
+		case msg := <-from.out:
+			if err := n.send(msg); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (n *Network) send(msg tss.Message) error {
+	bz, routing, err := msg.WireBytes()
+	if err != nil {
+		return err
+	}
+	from, err := n.index(routing.From)
+	if err != nil {
+		return err
+	}
+	to := make([]int, 0, len(n.ids))
+	if routing.To == nil {
+		for j := range n.ids {
+			if j != from {
+				to = append(to, j)
+			}
+		}
+	} else {
+		for _, id := range routing.To {
+			j, err := n.index(id)
+			if err != nil {
+				return err
+			}
+			to = append(to, j)
+		}
+	}
+	for _, j := range to {
+		n.stats.Sent++
+		if n.nodes[j] == nil || n.chance(n.cfg.Loss) {
+			n.stats.Lost++
+			continue
+		}
+		ev := event{from: from, to: j, msgType: msg.Type(), wire: bz, broadcast: routing.IsBroadcast}
+		n.schedule(n.latency(), &ev)
+		if n.chance(n.cfg.Duplicate) {
+			n.stats.Duplicated++
+			dup := ev
+			dup.duplicate = true
+			n.schedule(n.latency(), &dup)
+		}
+	}
+	return nil
+}
+
+func (n *Network) latency() time.Duration {
+	d := n.cfg.MinLatency
+	if spread := n.cfg.MaxLatency - n.cfg.MinLatency; spread > 0 {
+		d += time.Duration(n.rng.Int63n(int64(spread) + 1))
+	}
+	if n.chance(n.cfg.Reorder) {
+		d += n.cfg.MaxLatency
+	}
+	return d
+}
+
+// chance draws whether an event of probability p happens. It draws even for p = 0 and 1, so changing one probability
+// of a Config does not shift the schedule of the others.
+func (n *Network) chance(p float64) bool {
+	return n.rng.Float64() < p
+}
+
+func (n *Network) schedule(after time.Duration, ev *event) {
+	if after < 0 {
+		after = 0
+	}
+	n.seq++
+	ev.at, ev.seq = n.now+after, n.seq
+	heap.Push(&n.queue, ev)
+}
+
+func (n *Network) index(id *tss.PartyID) (int, error) {
+	if id != nil {
+		if found := n.ids.FindByKey(id.KeyInt()); found != nil {
+			return found.Index, nil
+		}
+	}
+	return 0, fmt.Errorf("netsim: %s is not part of the network", id)
+}
+
+// event is a party starting, a message arriving or a call of At, ordered by time and then by when it was scheduled
+type event struct {
+	at  time.Duration
+	seq uint64
+
+	start bool
+	call  func()
+
+	from, to  int
+	msgType   string
+	wire      []byte
+	broadcast bool
+	duplicate bool
+}
+
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	ev := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return ev
+}
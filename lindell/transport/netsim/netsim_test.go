@@ -0,0 +1,241 @@
+package netsim
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"go-rust/lindell/eddsa"
+	"go-rust/lindell/eddsa/keygen"
+	"go-rust/lindell/eddsa/signing"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMsg = []byte("through the simulated network")
+
+// runKeygen runs an EdDSA key generation of the parties pIDs through a network with cfg and returns its key shares,
+// nil for a party that did not finish
+func runKeygen(t *testing.T, cfg Config, pIDs tss.SortedPartyIDs) (*Network, []*keygen.LocalPartySaveData, error) {
+	net, err := New(cfg, pIDs)
+	assert.NoError(t, err)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	out := make(chan tss.Message, 4*len(pIDs))
+	ends := make([]chan keygen.LocalPartySaveData, len(pIDs))
+	for i := range pIDs {
+		ends[i] = make(chan keygen.LocalPartySaveData, 1)
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), len(pIDs)-1)
+		assert.NoError(t, net.Add(keygen.NewLocalParty(params, out, ends[i]), out, 0))
+	}
+	err = net.Run()
+
+	keys := make([]*keygen.LocalPartySaveData, len(pIDs))
+	for i, end := range ends {
+		select {
+		case key := <-end:
+			keys[i] = &key
+		default:
+		}
+	}
+	return net, keys, err
+}
+
+// runSigning signs testMsg with keys through a network with cfg, starting each party after its entry of startAfter,
+// and returns the parties and their signatures, nil for a party that did not finish
+func runSigning(t *testing.T, cfg Config, pIDs tss.SortedPartyIDs, keys []*keygen.LocalPartySaveData,
+	startAfter ...time.Duration) (*Network, []tss.Party, []*common.SignatureData, error) {
+	net, err := New(cfg, pIDs)
+	assert.NoError(t, err)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	out := make(chan tss.Message, 4*len(pIDs))
+	parties := make([]tss.Party, len(pIDs))
+	ends := make([]chan *common.SignatureData, len(pIDs))
+	for i := range pIDs {
+		ends[i] = make(chan *common.SignatureData, 1)
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), len(pIDs)-1)
+		parties[i] = signing.NewLocalParty(testMsg, params, *keys[i], out, ends[i])
+		var delay time.Duration
+		if i < len(startAfter) {
+			delay = startAfter[i]
+		}
+		assert.NoError(t, net.Add(parties[i], out, delay))
+	}
+	err = net.Run()
+
+	sigs := make([]*common.SignatureData, len(pIDs))
+	for i, end := range ends {
+		select {
+		case sig := <-end:
+			sigs[i] = sig
+		default:
+		}
+	}
+	return net, parties, sigs, err
+}
+
+func testKeys(t *testing.T, pIDs tss.SortedPartyIDs) []*keygen.LocalPartySaveData {
+	_, keys, err := runKeygen(t, Config{Seed: 1}, pIDs)
+	assert.NoError(t, err)
+	for _, key := range keys {
+		if key == nil {
+			t.Fatal("key generation did not finish")
+		}
+	}
+	return keys
+}
+
+func TestSessionsUnderAdverseConditions(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := testKeys(t, pIDs)
+	pub := ed25519.PublicKey(eddsa.EncodePoint(keys[0].EDDSAPub))
+
+	for name, cfg := range map[string]Config{
+		"perfect":   {},
+		"latency":   {MinLatency: 5 * time.Millisecond, MaxLatency: 50 * time.Millisecond},
+		"reordered": {MaxLatency: 50 * time.Millisecond, Reorder: 0.5},
+		// every message arrives twice, the copies often after the session ended
+		"duplicated": {MaxLatency: 50 * time.Millisecond, Duplicate: 1},
+		"everything": {MinLatency: time.Millisecond, MaxLatency: 80 * time.Millisecond, Reorder: 0.3, Duplicate: 0.5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for seed := int64(0); seed < 4; seed++ {
+				cfg.Seed = seed
+				net, keys2, err := runKeygen(t, cfg, pIDs)
+				assert.NoError(t, err, "seed %d", seed)
+				assert.True(t, keys2[0] != nil && keys2[1] != nil, "seed %d: key generation must finish", seed)
+				assert.Zero(t, net.Stats().Lost)
+
+				net, _, sigs, err := runSigning(t, cfg, pIDs, keys)
+				assert.NoError(t, err, "seed %d", seed)
+				for i, sig := range sigs {
+					if assert.NotNil(t, sig, "seed %d: party %d must finish", seed, i) {
+						assert.True(t, ed25519.Verify(pub, testMsg, sig.GetSignature()), "ed25519 verify must pass")
+					}
+				}
+				if cfg.Duplicate == 1 {
+					assert.Equal(t, net.Stats().Sent, net.Stats().Duplicated)
+				}
+			}
+		})
+	}
+}
+
+func TestPartyStartedLate(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := testKeys(t, pIDs)
+
+	// party 1 is handed party 0's first message before it starts
+	net, _, sigs, err := runSigning(t, Config{MaxLatency: time.Millisecond}, pIDs, keys, 0, time.Second)
+	assert.NoError(t, err)
+	assert.Less(t, net.Deliveries()[0].At, time.Second)
+	assert.Equal(t, 1, net.Deliveries()[0].To)
+	assert.NotNil(t, sigs[0])
+	assert.NotNil(t, sigs[1])
+}
+
+func TestDeterministicUnderSeed(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := testKeys(t, pIDs)
+	cfg := Config{Seed: 7, MaxLatency: 100 * time.Millisecond, Reorder: 0.5, Duplicate: 0.5}
+
+	first, _, _, err := runSigning(t, cfg, pIDs, keys)
+	assert.NoError(t, err)
+	second, _, _, err := runSigning(t, cfg, pIDs, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Deliveries(), second.Deliveries())
+	assert.Equal(t, first.Stats(), second.Stats())
+	assert.Equal(t, first.Now(), second.Now())
+
+	cfg.Seed++
+	other, _, _, err := runSigning(t, cfg, pIDs, keys)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Deliveries(), other.Deliveries(), "another seed must give another schedule")
+}
+
+func TestLostMessagesStallTheSession(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := testKeys(t, pIDs)
+
+	net, parties, sigs, err := runSigning(t, Config{Loss: 1}, pIDs, keys)
+	assert.NoError(t, err, "a lost message is no error of the parties")
+	assert.Equal(t, net.Stats().Sent, net.Stats().Lost)
+	assert.Empty(t, net.Deliveries())
+	for i, P := range parties {
+		assert.Nil(t, sigs[i])
+		assert.Equal(t, []*tss.PartyID{pIDs[1-i]}, P.WaitingFor())
+	}
+}
+
+func TestPartition(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := testKeys(t, pIDs)
+	cfg := Config{MinLatency: 10 * time.Millisecond, MaxLatency: 10 * time.Millisecond}
+
+	t.Run("lasting", func(t *testing.T) {
+		net, err := New(cfg, pIDs)
+		assert.NoError(t, err)
+		assert.NoError(t, net.Partition(pIDs[:1]))
+		out := make(chan tss.Message, 8)
+		end := make(chan *common.SignatureData, 2)
+		for i := range pIDs {
+			params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[i], 2, 1)
+			assert.NoError(t, net.Add(signing.NewLocalParty(testMsg, params, *keys[i], out, end), out, 0))
+		}
+		assert.NoError(t, net.Run())
+		assert.Len(t, end, 0)
+		assert.Equal(t, 2, net.Stats().Partitioned)
+	})
+
+	t.Run("healed in time", func(t *testing.T) {
+		net, err := New(cfg, pIDs)
+		assert.NoError(t, err)
+		net.At(0, func() { assert.NoError(t, net.Partition(pIDs[:1], pIDs[1:])) })
+		// the first messages are still in flight when the partition ends
+		net.At(5*time.Millisecond, net.Heal)
+		out := make(chan tss.Message, 8)
+		end := make(chan *common.SignatureData, 2)
+		for i := range pIDs {
+			params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(pIDs), pIDs[i], 2, 1)
+			assert.NoError(t, net.Add(signing.NewLocalParty(testMsg, params, *keys[i], out, end), out, 0))
+		}
+		assert.NoError(t, net.Run())
+		assert.Len(t, end, 2)
+		assert.Zero(t, net.Stats().Partitioned)
+	})
+}
+
+func TestPartyErrorStopsRun(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := testKeys(t, pIDs)
+	// party 1 holds the share of another key, so party 0 cannot verify what it receives
+	other := testKeys(t, pIDs)
+
+	_, _, _, err := runSigning(t, Config{}, pIDs, []*keygen.LocalPartySaveData{keys[0], other[1]})
+	var tssErr *tss.Error
+	if assert.True(t, errors.As(err, &tssErr), "the party's error must be returned: %v", err) {
+		assert.NotEmpty(t, tssErr.Culprits())
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(2)
+	for _, cfg := range []Config{
+		{MinLatency: -time.Millisecond},
+		{MinLatency: 2 * time.Millisecond, MaxLatency: time.Millisecond},
+		{Loss: 1.5},
+		{Duplicate: -0.1},
+	} {
+		_, err := New(cfg, pIDs)
+		assert.Error(t, err, "%+v", cfg)
+	}
+	_, err := New(Config{}, nil)
+	assert.Error(t, err)
+
+	net, err := New(Config{}, pIDs)
+	assert.NoError(t, err)
+	stranger := tss.GenerateTestPartyIDs(1, 5)[0]
+	assert.Error(t, net.Partition([]*tss.PartyID{stranger}))
+}
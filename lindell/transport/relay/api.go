@@ -0,0 +1,64 @@
+// Package relay routes the messages of two-party sessions through a standalone HTTP/JSON relay. The relay creates
+// sessions, assigns the server and client roles from the sorted party set, stores the wire bytes each party posts
+// and hands them out to the other party through long polling. Idle sessions expire.
+//
+//	POST   /sessions                    create a session: {"parties": [...]} -> id, roles and per-party tokens
+//	POST   /sessions/{id}/messages      post one message (Authorization: Bearer <token>)
+//	GET    /sessions/{id}/messages      long-poll messages for the caller: ?after=<seq>&wait=<duration>
+//	DELETE /sessions/{id}               end a session
+package relay
+
+import (
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// role names, matching signing.Role's String()
+const (
+	RoleServer = "server"
+	RoleClient = "client"
+)
+
+type PartyID struct {
+	ID      string `json:"id"`
+	Moniker string `json:"moniker"`
+	Key     []byte `json:"key"`
+}
+
+type CreateSessionRequest struct {
+	Parties []PartyID `json:"parties"`
+}
+
+type CreateSessionResponse struct {
+	SessionID string `json:"session_id"`
+	// Roles and Tokens are keyed by party id; each party authenticates with its own token
+	Roles  map[string]string `json:"roles"`
+	Tokens map[string]string `json:"tokens"`
+}
+
+type Message struct {
+	Seq         uint64    `json:"seq,omitempty"`
+	From        PartyID   `json:"from"`
+	To          []PartyID `json:"to,omitempty"`
+	IsBroadcast bool      `json:"is_broadcast"`
+	WireBytes   []byte    `json:"wire_bytes"`
+}
+
+type PollResponse struct {
+	Messages []Message `json:"messages"`
+	// Next is the seq to pass as `after` in the following poll
+	Next uint64 `json:"next"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newPartyID(p *tss.PartyID) PartyID {
+	return PartyID{ID: p.Id, Moniker: p.Moniker, Key: p.Key}
+}
+
+func (p PartyID) unmarshal() *tss.PartyID {
+	return tss.NewPartyID(p.ID, p.Moniker, new(big.Int).SetBytes(p.Key))
+}
@@ -0,0 +1,191 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// PollWait is how long a Conn asks the relay to hold each poll open
+const PollWait = 25 * time.Second
+
+// Client talks to a relay Server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a client of the relay at baseURL. A nil httpClient uses http.DefaultClient; its Timeout, if any,
+// must be longer than PollWait.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), httpClient: httpClient}
+}
+
+// CreateSession opens a session between the two parties. The response carries the role and the token of each party,
+// the caller hands every party its own token.
+func (c *Client) CreateSession(ctx context.Context, parties tss.UnSortedPartyIDs) (*CreateSessionResponse, error) {
+	req := CreateSessionRequest{}
+	for _, p := range parties {
+		req.Parties = append(req.Parties, newPartyID(p))
+	}
+	resp := new(CreateSessionResponse)
+	if err := c.do(ctx, http.MethodPost, "/sessions", "", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteSession ends a session before it expires
+func (c *Client) DeleteSession(ctx context.Context, sessionID, token string) error {
+	return c.do(ctx, http.MethodDelete, "/sessions/"+url.PathEscape(sessionID), token, nil, nil)
+}
+
+// Join returns a Conn through which self exchanges the messages of the session, authenticated by its token.
+// Closing the Conn stops polling but leaves the session to expire, the peer may still have messages to collect.
+func (c *Client) Join(ctx context.Context, sessionID string, self *tss.PartyID, token string) (transport.Conn, error) {
+	if sessionID == "" || self == nil || token == "" {
+		return nil, errors.New("a session id, the calling party and its token are required")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &conn{
+		client:    c,
+		sessionID: sessionID,
+		self:      self,
+		token:     token,
+		ctx:       ctx,
+		cancel:    cancel,
+	}, nil
+}
+
+type conn struct {
+	client    *Client
+	sessionID string
+	self      *tss.PartyID
+	token     string
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// Recv is called from a single goroutine
+	after   uint64
+	pending []Message
+}
+
+var _ transport.Conn = (*conn)(nil)
+
+func (c *conn) Send(env *transport.Envelope) error {
+	if env.SessionID != c.sessionID {
+		return errors.New("envelope belongs to another session")
+	}
+	msg := Message{
+		From:        newPartyID(env.From),
+		IsBroadcast: env.IsBroadcast,
+		WireBytes:   env.WireBytes,
+	}
+	for _, to := range env.To {
+		msg.To = append(msg.To, newPartyID(to))
+	}
+	return c.client.do(c.ctx, http.MethodPost, c.path(), c.token, msg, nil)
+}
+
+func (c *conn) Recv() (*transport.Envelope, error) {
+	for len(c.pending) == 0 {
+		query := url.Values{
+			"after": {strconv.FormatUint(c.after, 10)},
+			"wait":  {PollWait.String()},
+		}
+		var resp PollResponse
+		err := c.client.do(c.ctx, http.MethodGet, c.path()+"?"+query.Encode(), c.token, nil, &resp)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+			// the session was deleted or expired
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.after, c.pending = resp.Next, resp.Messages
+	}
+	msg := c.pending[0]
+	c.pending = c.pending[1:]
+	env := &transport.Envelope{
+		SessionID:   c.sessionID,
+		From:        msg.From.unmarshal(),
+		IsBroadcast: msg.IsBroadcast,
+		WireBytes:   msg.WireBytes,
+	}
+	for _, to := range msg.To {
+		env.To = append(env.To, to.unmarshal())
+	}
+	return env, nil
+}
+
+func (c *conn) Close() error {
+	c.cancel()
+	return nil
+}
+
+func (c *conn) path() string {
+	return "/sessions/" + url.PathEscape(c.sessionID) + "/messages"
+}
+
+// ----- //
+
+// StatusError is returned for a non-2xx response of the relay
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("relay: %d %s: %s", e.Code, http.StatusText(e.Code), e.Message)
+}
+
+func (c *Client) do(ctx context.Context, method, path, token string, body, out interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(bz)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, rd)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var e errorResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, maxBodyBytes)).Decode(&e)
+		return &StatusError{Code: resp.StatusCode, Message: e.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxBodyBytes)).Decode(out)
+}
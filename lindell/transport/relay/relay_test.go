@@ -0,0 +1,185 @@
+package relay
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-rust/lindell/eddsa"
+	"go-rust/lindell/eddsa/keygen"
+	"go-rust/lindell/eddsa/signing"
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func startRelay(t *testing.T, ttl time.Duration) (*Server, *Client) {
+	srv := NewServer(ttl)
+	hs := httptest.NewServer(srv)
+	t.Cleanup(hs.Close)
+	return srv, NewClient(hs.URL, hs.Client())
+}
+
+// runPair creates a relay session and runs both parties through it
+func runPair[T any](t *testing.T, client *Client, pIDs tss.SortedPartyIDs,
+	newParty func(i int, out chan<- tss.Message, end chan<- T) tss.Party) [2]T {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sess, err := client.CreateSession(ctx, tss.UnSortedPartyIDs{pIDs[1], pIDs[0]})
+	assert.NoError(t, err)
+	assert.Equal(t, RoleServer, sess.Roles[pIDs[0].Id])
+	assert.Equal(t, RoleClient, sess.Roles[pIDs[1].Id])
+
+	var results [2]T
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			conn, err := client.Join(ctx, sess.SessionID, pIDs[i], sess.Tokens[pIDs[i].Id])
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+
+			out, end := make(chan tss.Message, 2), make(chan T, 1)
+			results[i], err = transport.Run(ctx, conn, sess.SessionID, pIDs, newParty(i, out, end), out, end)
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, <-errs)
+	}
+	return results
+}
+
+func TestRelayKeygenAndSigning(t *testing.T) {
+	_, client := startRelay(t, time.Minute)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	keys := runPair(t, client, pIDs,
+		func(i int, out chan<- tss.Message, end chan<- keygen.LocalPartySaveData) tss.Party {
+			return keygen.NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], 2, 1), out, end)
+		})
+	assert.True(t, keys[0].EDDSAPub.Equals(keys[1].EDDSAPub), "both parties must agree on the public key")
+
+	msg := []byte("through the relay")
+	sigs := runPair(t, client, pIDs,
+		func(i int, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Party {
+			return signing.NewLocalParty(msg, tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], 2, 1), keys[i], out, end)
+		})
+	pub := ed25519.PublicKey(eddsa.EncodePoint(keys[0].EDDSAPub))
+	for _, sig := range sigs {
+		assert.True(t, ed25519.Verify(pub, msg, sig.GetSignature()), "ed25519 verify must pass")
+	}
+}
+
+func TestRelayRejectsImpersonation(t *testing.T) {
+	_, client := startRelay(t, time.Minute)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	ctx := context.Background()
+	sess, err := client.CreateSession(ctx, tss.UnSortedPartyIDs{pIDs[0], pIDs[1]})
+	assert.NoError(t, err)
+
+	// party 1's token cannot post as party 0
+	conn, err := client.Join(ctx, sess.SessionID, pIDs[1], sess.Tokens[pIDs[1].Id])
+	assert.NoError(t, err)
+	err = conn.Send(&transport.Envelope{SessionID: sess.SessionID, From: pIDs[0], IsBroadcast: true, WireBytes: []byte{1}})
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusForbidden, statusErr.Code)
+
+	// nor can an unknown token
+	conn, err = client.Join(ctx, sess.SessionID, pIDs[1], "not-a-token")
+	assert.NoError(t, err)
+	err = conn.Send(&transport.Envelope{SessionID: sess.SessionID, From: pIDs[1], IsBroadcast: true, WireBytes: []byte{1}})
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusUnauthorized, statusErr.Code)
+
+	// sessions are strictly two-party
+	_, err = client.CreateSession(ctx, tss.UnSortedPartyIDs{pIDs[0]})
+	assert.Error(t, err)
+}
+
+func TestRelayExpiresIdleSessions(t *testing.T) {
+	srv, client := startRelay(t, time.Minute)
+	var mtx sync.Mutex
+	now := time.Now()
+	srv.now = func() time.Time {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mtx.Lock()
+		now = now.Add(d)
+		mtx.Unlock()
+	}
+
+	pIDs := tss.GenerateTestPartyIDs(2)
+	ctx := context.Background()
+	sess, err := client.CreateSession(ctx, tss.UnSortedPartyIDs{pIDs[0], pIDs[1]})
+	assert.NoError(t, err)
+	conn, err := client.Join(ctx, sess.SessionID, pIDs[0], sess.Tokens[pIDs[0].Id])
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	advance(30 * time.Second)
+	assert.Equal(t, 0, srv.ExpireIdle(), "an active session must survive")
+
+	// a pending poll ends once its session expires
+	recvErr := make(chan error, 1)
+	go func() {
+		_, err := conn.Recv()
+		recvErr <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	advance(2 * time.Minute)
+	assert.Equal(t, 1, srv.ExpireIdle())
+	select {
+	case err = <-recvErr:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the poll outlived its session")
+	}
+}
+
+func TestRelayLimitsSessions(t *testing.T) {
+	srv, client := startRelay(t, time.Minute)
+	srv.maxSessions = 1
+	pIDs := tss.GenerateTestPartyIDs(2)
+	ctx := context.Background()
+
+	sess, err := client.CreateSession(ctx, tss.UnSortedPartyIDs{pIDs[0], pIDs[1]})
+	assert.NoError(t, err)
+	_, err = client.CreateSession(ctx, tss.UnSortedPartyIDs{pIDs[0], pIDs[1]})
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.Code)
+
+	// ending a session makes room for the next one
+	assert.NoError(t, client.DeleteSession(ctx, sess.SessionID, sess.Tokens[pIDs[0].Id]))
+	_, err = client.CreateSession(ctx, tss.UnSortedPartyIDs{pIDs[0], pIDs[1]})
+	assert.NoError(t, err)
+}
+
+func TestRelayRejectsOversizedCreate(t *testing.T) {
+	_, client := startRelay(t, time.Minute)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	pIDs[0].Moniker = strings.Repeat("x", maxCreateBodyBytes)
+
+	_, err := client.CreateSession(context.Background(), tss.UnSortedPartyIDs{pIDs[0], pIDs[1]})
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusBadRequest, statusErr.Code)
+}
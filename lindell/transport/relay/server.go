@@ -0,0 +1,348 @@
+package relay
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+const (
+	// a two-party session exchanges a handful of messages, these only bound what a misbehaving party can store
+	maxBodyBytes           = 1 << 20
+	maxMessagesPerSession  = 256
+	defaultMaxPollDuration = 30 * time.Second
+	// a create request only names the two parties
+	maxCreateBodyBytes = 4 << 10
+	// anyone may create sessions, this bounds the memory they hold until they expire
+	defaultMaxSessions = 10000
+)
+
+// Server is the relay. It keeps sessions in memory and is safe for concurrent use.
+type Server struct {
+	ttl     time.Duration
+	maxWait time.Duration
+	// live sessions beyond which createSession answers 503
+	maxSessions int
+	now         func() time.Time
+
+	mtx      sync.Mutex
+	sessions map[string]*session
+}
+
+type session struct {
+	parties    tss.SortedPartyIDs
+	tokens     map[string]string // party id -> token
+	messages   []Message
+	lastActive time.Time
+	// closed and replaced whenever a message arrives, wakes up pending polls
+	notify chan struct{}
+}
+
+// NewServer returns a relay that expires sessions after ttl without activity
+func NewServer(ttl time.Duration) *Server {
+	return &Server{
+		ttl:         ttl,
+		maxWait:     defaultMaxPollDuration,
+		maxSessions: defaultMaxSessions,
+		now:         time.Now,
+		sessions:    make(map[string]*session),
+	}
+}
+
+// ExpireIdle removes the sessions that have been idle for longer than the ttl and returns how many were removed.
+// Run calls it periodically.
+func (s *Server) ExpireIdle() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	expired := 0
+	for id, sess := range s.sessions {
+		if s.now().Sub(sess.lastActive) > s.ttl {
+			close(sess.notify)
+			delete(s.sessions, id)
+			expired++
+		}
+	}
+	return expired
+}
+
+// Run expires idle sessions until stop is closed
+func (s *Server) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.ttl / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.ExpireIdle()
+		}
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "sessions" && r.Method == http.MethodPost:
+		s.createSession(w, r)
+	case len(parts) == 2 && parts[0] == "sessions" && r.Method == http.MethodDelete:
+		s.deleteSession(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "sessions" && parts[2] == "messages" && r.Method == http.MethodPost:
+		s.postMessage(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "sessions" && parts[2] == "messages" && r.Method == http.MethodGet:
+		s.pollMessages(w, r, parts[1])
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (s *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCreateBodyBytes)
+	var req CreateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Parties) != 2 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("exactly 2 parties are required, got %d", len(req.Parties)))
+		return
+	}
+	unsorted := make(tss.UnSortedPartyIDs, 0, len(req.Parties))
+	for _, p := range req.Parties {
+		if p.ID == "" || len(p.Key) == 0 {
+			writeError(w, http.StatusBadRequest, errors.New("every party needs an id and a key"))
+			return
+		}
+		unsorted = append(unsorted, p.unmarshal())
+	}
+	parties := tss.SortPartyIDs(unsorted)
+	if parties[0].Id == parties[1].Id || parties[0].KeyInt().Cmp(parties[1].KeyInt()) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("the parties must have distinct ids and keys"))
+		return
+	}
+
+	resp := CreateSessionResponse{
+		SessionID: randomID(),
+		Roles:     make(map[string]string),
+		Tokens:    make(map[string]string),
+	}
+	sess := &session{
+		parties:    parties,
+		tokens:     make(map[string]string),
+		lastActive: s.now(),
+		notify:     make(chan struct{}),
+	}
+	// the party with the lowest key is the server, as signing.RoleAuto decides
+	for _, p := range parties {
+		role := RoleClient
+		if p.Index == 0 {
+			role = RoleServer
+		}
+		token := randomID()
+		resp.Roles[p.Id] = role
+		resp.Tokens[p.Id] = token
+		sess.tokens[p.Id] = token
+	}
+
+	s.mtx.Lock()
+	if len(s.sessions) >= s.maxSessions {
+		s.mtx.Unlock()
+		writeError(w, http.StatusServiceUnavailable, errors.New("too many live sessions, try again later"))
+		return
+	}
+	s.sessions[resp.SessionID] = sess
+	s.mtx.Unlock()
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+func (s *Server) deleteSession(w http.ResponseWriter, r *http.Request, id string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("unknown session"))
+		return
+	}
+	if _, err := authenticate(r, sess); err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	close(sess.notify)
+	delete(s.sessions, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) postMessage(w http.ResponseWriter, r *http.Request, id string) {
+	var msg Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(msg.WireBytes) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("empty message"))
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("unknown session"))
+		return
+	}
+	from, err := authenticate(r, sess)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	// the sender is the authenticated party, whatever the body claims
+	if msg.From.ID != from.Id || new(big.Int).SetBytes(msg.From.Key).Cmp(from.KeyInt()) != 0 {
+		writeError(w, http.StatusForbidden, errors.New("message sender does not match the authenticated party"))
+		return
+	}
+	for _, to := range msg.To {
+		if sess.parties.FindByKey(new(big.Int).SetBytes(to.Key)) == nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("recipient %s is not part of the session", to.ID))
+			return
+		}
+	}
+	if len(sess.messages) >= maxMessagesPerSession {
+		writeError(w, http.StatusTooManyRequests, errors.New("session message limit reached"))
+		return
+	}
+
+	msg.From = newPartyID(from)
+	msg.Seq = uint64(len(sess.messages) + 1)
+	sess.messages = append(sess.messages, msg)
+	sess.lastActive = s.now()
+	close(sess.notify)
+	sess.notify = make(chan struct{})
+	writeJSON(w, http.StatusAccepted, struct {
+		Seq uint64 `json:"seq"`
+	}{msg.Seq})
+}
+
+func (s *Server) pollMessages(w http.ResponseWriter, r *http.Request, id string) {
+	after, err := parseUint(r.URL.Query().Get("after"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid after: %v", err))
+		return
+	}
+	wait := s.maxWait
+	if v := r.URL.Query().Get("wait"); v != "" {
+		if wait, err = time.ParseDuration(v); err != nil || wait < 0 {
+			writeError(w, http.StatusBadRequest, errors.New("invalid wait"))
+			return
+		}
+		if wait > s.maxWait {
+			wait = s.maxWait
+		}
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		s.mtx.Lock()
+		sess, ok := s.sessions[id]
+		if !ok {
+			s.mtx.Unlock()
+			writeError(w, http.StatusNotFound, errors.New("unknown session"))
+			return
+		}
+		self, err := authenticate(r, sess)
+		if err != nil {
+			s.mtx.Unlock()
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		sess.lastActive = s.now()
+		resp := PollResponse{Messages: []Message{}, Next: after}
+		for _, msg := range sess.messages {
+			if msg.Seq > after && addressedTo(msg, self) {
+				resp.Messages = append(resp.Messages, msg)
+			}
+		}
+		if len(sess.messages) > 0 && uint64(len(sess.messages)) > after {
+			resp.Next = uint64(len(sess.messages))
+		}
+		notify := sess.notify
+		s.mtx.Unlock()
+
+		if len(resp.Messages) > 0 {
+			writeJSON(w, http.StatusOK, resp)
+			return
+		}
+		select {
+		case <-notify:
+		case <-timer.C:
+			writeJSON(w, http.StatusOK, resp)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// ----- //
+
+// authenticate returns the party whose token the request carries
+func authenticate(r *http.Request, sess *session) (*tss.PartyID, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	for _, p := range sess.parties {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(sess.tokens[p.Id])) == 1 {
+			return p, nil
+		}
+	}
+	return nil, errors.New("missing or invalid token")
+}
+
+func addressedTo(msg Message, p *tss.PartyID) bool {
+	if msg.From.ID == p.Id {
+		return false
+	}
+	if msg.IsBroadcast {
+		return true
+	}
+	for _, to := range msg.To {
+		if to.ID == p.Id {
+			return true
+		}
+	}
+	return false
+}
+
+func randomID() string {
+	bz := make([]byte, 16)
+	if _, err := rand.Read(bz); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(bz)
+}
+
+func parseUint(v string) (uint64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,243 @@
+// Package securechannel authenticates and encrypts a two-party session on top of any transport.Conn. The parties run
+// a Noise KK handshake (Noise_KK_25519_ChaChaPoly_SHA256) between their long-term identity keys, which each side
+// knows for its peer in advance, and then seal every message with the resulting keys. The routing of a message, its
+// session, sender and recipients, is authenticated as associated data, so the peer cannot be impersonated and a
+// message cannot be replayed, reordered or moved to another session.
+package securechannel
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/flynn/noise"
+)
+
+const (
+	frameHandshake byte = 1
+	frameData      byte = 2
+
+	protocolName = "lindell-securechannel-v1"
+)
+
+var cipherSuite = noise.NewCipherSuite(noise.DH25519, noise.CipherChaChaPoly, noise.HashSHA256)
+
+// Identity is the long-term X25519 key pair of a party
+type Identity struct {
+	Private []byte `json:"private"`
+	Public  []byte `json:"public"`
+}
+
+// Peer is the other party of a session together with its long-term public key
+type Peer struct {
+	ID        *tss.PartyID
+	StaticKey []byte
+}
+
+func GenerateIdentity() (*Identity, error) {
+	kp, err := cipherSuite.GenerateKeypair(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{Private: kp.Private, Public: kp.Public}, nil
+}
+
+type conn struct {
+	inner     transport.Conn
+	sessionID string
+	self      *tss.PartyID
+	peer      *tss.PartyID
+
+	sendMtx sync.Mutex
+	send    *noise.CipherState
+	recvMtx sync.Mutex
+	recv    *noise.CipherState
+}
+
+var _ transport.Conn = (*conn)(nil)
+
+// Handshake authenticates the peer over inner and returns a Conn that seals what it sends and only returns messages
+// that authenticate as coming from peer. Both parties call it; the one with the lower party key initiates.
+// The handshake blocks on inner, closing inner aborts it.
+func Handshake(inner transport.Conn, sessionID string, self *tss.PartyID, identity *Identity, peer Peer) (transport.Conn, error) {
+	if sessionID == "" || self == nil || identity == nil || peer.ID == nil {
+		return nil, errors.New("a session id, both parties and this party's identity are required")
+	}
+	cmp := self.KeyInt().Cmp(peer.ID.KeyInt())
+	if cmp == 0 || bytes.Equal(identity.Public, peer.StaticKey) {
+		return nil, errors.New("the peer has this party's identity")
+	}
+	// decided by the party keys, which both sides agree on even if an identity key is misconfigured
+	initiator := cmp < 0
+	first, second := self, peer.ID
+	if !initiator {
+		first, second = second, first
+	}
+	hs, err := noise.NewHandshakeState(noise.Config{
+		CipherSuite:   cipherSuite,
+		Random:        rand.Reader,
+		Pattern:       noise.HandshakeKK,
+		Initiator:     initiator,
+		Prologue:      prologue(sessionID, first, second),
+		StaticKeypair: noise.DHKey{Private: identity.Private, Public: identity.Public},
+		PeerStatic:    peer.StaticKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &conn{inner: inner, sessionID: sessionID, self: self, peer: peer.ID}
+	var cs0, cs1 *noise.CipherState
+	if initiator {
+		if err = c.writeHandshake(hs); err == nil {
+			cs0, cs1, err = c.readHandshake(hs)
+		}
+		c.send, c.recv = cs0, cs1
+	} else {
+		if _, _, err = c.readHandshake(hs); err == nil {
+			cs0, cs1, err = c.writeHandshakeFinal(hs)
+		}
+		c.send, c.recv = cs1, cs0
+	}
+	if err == nil && c.send == nil {
+		err = errors.New("the handshake did not complete")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("securechannel: handshake with %s: %w", peer.ID, err)
+	}
+	return c, nil
+}
+
+func (c *conn) Send(env *transport.Envelope) error {
+	if env.SessionID != c.sessionID {
+		return errors.New("envelope belongs to another session")
+	}
+	if !samePartyID(env.From, c.self) {
+		return errors.New("securechannel: only this party's own messages can be sent")
+	}
+	c.sendMtx.Lock()
+	defer c.sendMtx.Unlock()
+	sealed, err := c.send.Encrypt([]byte{frameData}, associatedData(env), env.WireBytes)
+	if err != nil {
+		return err
+	}
+	out := *env
+	out.WireBytes = sealed
+	return c.inner.Send(&out)
+}
+
+func (c *conn) Recv() (*transport.Envelope, error) {
+	env, err := c.inner.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if env.SessionID != c.sessionID || !samePartyID(env.From, c.peer) {
+		return nil, fmt.Errorf("securechannel: received a message from %s, expected %s", env.From, c.peer)
+	}
+	if len(env.WireBytes) == 0 || env.WireBytes[0] != frameData {
+		return nil, errors.New("securechannel: expected a sealed message")
+	}
+	c.recvMtx.Lock()
+	defer c.recvMtx.Unlock()
+	plain, err := c.recv.Decrypt(nil, associatedData(env), env.WireBytes[1:])
+	if err != nil {
+		return nil, fmt.Errorf("securechannel: message from %s does not authenticate", c.peer)
+	}
+	out := *env
+	out.From = c.peer
+	out.WireBytes = plain
+	return &out, nil
+}
+
+func (c *conn) Close() error {
+	return c.inner.Close()
+}
+
+// ----- //
+
+func (c *conn) handshakeEnvelope(msg []byte) *transport.Envelope {
+	return &transport.Envelope{
+		SessionID: c.sessionID,
+		From:      c.self,
+		To:        []*tss.PartyID{c.peer},
+		WireBytes: append([]byte{frameHandshake}, msg...),
+	}
+}
+
+func (c *conn) writeHandshake(hs *noise.HandshakeState) error {
+	msg, _, _, err := hs.WriteMessage(nil, nil)
+	if err != nil {
+		return err
+	}
+	return c.inner.Send(c.handshakeEnvelope(msg))
+}
+
+func (c *conn) writeHandshakeFinal(hs *noise.HandshakeState) (*noise.CipherState, *noise.CipherState, error) {
+	msg, cs0, cs1, err := hs.WriteMessage(nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cs0, cs1, c.inner.Send(c.handshakeEnvelope(msg))
+}
+
+func (c *conn) readHandshake(hs *noise.HandshakeState) (*noise.CipherState, *noise.CipherState, error) {
+	env, err := c.inner.Recv()
+	if err != nil {
+		return nil, nil, err
+	}
+	if env.SessionID != c.sessionID || !samePartyID(env.From, c.peer) {
+		return nil, nil, fmt.Errorf("handshake message from %s, expected %s", env.From, c.peer)
+	}
+	if len(env.WireBytes) == 0 || env.WireBytes[0] != frameHandshake {
+		return nil, nil, errors.New("expected a handshake message")
+	}
+	_, cs0, cs1, err := hs.ReadMessage(nil, env.WireBytes[1:])
+	return cs0, cs1, err
+}
+
+// prologue binds the handshake to the session and to both parties' tss identities
+func prologue(sessionID string, initiator, responder *tss.PartyID) []byte {
+	var buf bytes.Buffer
+	writeField(&buf, []byte(protocolName))
+	writeField(&buf, []byte(sessionID))
+	for _, p := range []*tss.PartyID{initiator, responder} {
+		writeField(&buf, []byte(p.Id))
+		writeField(&buf, p.KeyInt().Bytes())
+	}
+	return buf.Bytes()
+}
+
+// associatedData is the routing of a message every transport carries unchanged
+func associatedData(env *transport.Envelope) []byte {
+	var buf bytes.Buffer
+	writeField(&buf, []byte(env.SessionID))
+	writeField(&buf, []byte(env.From.Id))
+	writeField(&buf, env.From.KeyInt().Bytes())
+	if env.IsBroadcast {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	for _, to := range env.To {
+		writeField(&buf, []byte(to.Id))
+		writeField(&buf, to.KeyInt().Bytes())
+	}
+	return buf.Bytes()
+}
+
+func writeField(buf *bytes.Buffer, bz []byte) {
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(bz)))
+	buf.Write(l[:])
+	buf.Write(bz)
+}
+
+func samePartyID(a, b *tss.PartyID) bool {
+	return a != nil && b != nil && a.Id == b.Id && a.KeyInt().Cmp(b.KeyInt()) == 0
+}
@@ -0,0 +1,231 @@
+package securechannel
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-rust/lindell/eddsa/keygen"
+	"go-rust/lindell/transport"
+	"go-rust/lindell/transport/relay"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+// pipeConn is one end of an in-memory transport. tamper, if set, may rewrite every envelope it sends.
+type pipeConn struct {
+	in, out chan *transport.Envelope
+	tamper  func(env *transport.Envelope)
+	once    sync.Once
+}
+
+func pipe() (*pipeConn, *pipeConn) {
+	a, b := make(chan *transport.Envelope, 16), make(chan *transport.Envelope, 16)
+	return &pipeConn{in: a, out: b}, &pipeConn{in: b, out: a}
+}
+
+func (c *pipeConn) Send(env *transport.Envelope) error {
+	cp := *env
+	cp.WireBytes = append([]byte(nil), env.WireBytes...)
+	if c.tamper != nil {
+		c.tamper(&cp)
+	}
+	c.out <- &cp
+	return nil
+}
+
+func (c *pipeConn) Recv() (*transport.Envelope, error) {
+	env, ok := <-c.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return env, nil
+}
+
+func (c *pipeConn) Close() error {
+	c.once.Do(func() { close(c.out) })
+	return nil
+}
+
+type fixture struct {
+	pIDs       tss.SortedPartyIDs
+	identities [2]*Identity
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{pIDs: tss.GenerateTestPartyIDs(2)}
+	for i := range f.identities {
+		var err error
+		f.identities[i], err = GenerateIdentity()
+		assert.NoError(t, err)
+	}
+	return f
+}
+
+func (f *fixture) peer(i int) Peer {
+	return Peer{ID: f.pIDs[i], StaticKey: f.identities[i].Public}
+}
+
+// handshake runs both sides over inner and returns the secured conns, or the errors
+func (f *fixture) handshake(sessionID string, inner [2]transport.Conn, peers [2]Peer) ([2]transport.Conn, [2]error) {
+	var conns [2]transport.Conn
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conns[i], errs[i] = Handshake(inner[i], sessionID, f.pIDs[i], f.identities[i], peers[i])
+			if errs[i] != nil {
+				// unblock the other side
+				inner[i].Close()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return conns, errs
+}
+
+func TestKeygenOverSecureChannel(t *testing.T) {
+	f := newFixture(t)
+	a, b := pipe()
+	conns, errs := f.handshake("keygen", [2]transport.Conn{a, b}, [2]Peer{f.peer(1), f.peer(0)})
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	p2pCtx := tss.NewPeerContext(f.pIDs)
+	var keys [2]keygen.LocalPartySaveData
+	runErrs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			out, end := make(chan tss.Message, 2), make(chan keygen.LocalPartySaveData, 1)
+			party := keygen.NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, f.pIDs[i], 2, 1), out, end)
+			var err error
+			keys[i], err = transport.Run(ctx, conns[i], "keygen", f.pIDs, party, out, end)
+			runErrs <- err
+		}(i)
+	}
+	assert.NoError(t, <-runErrs)
+	assert.NoError(t, <-runErrs)
+	assert.True(t, keys[0].EDDSAPub.Equals(keys[1].EDDSAPub), "both parties must agree on the public key")
+}
+
+func TestRejectsUnknownIdentity(t *testing.T) {
+	f := newFixture(t)
+	impostor, err := GenerateIdentity()
+	assert.NoError(t, err)
+	a, b := pipe()
+	// party 0 expects a different key for party 1
+	_, errs := f.handshake("impostor", [2]transport.Conn{a, b},
+		[2]Peer{{ID: f.pIDs[1], StaticKey: impostor.Public}, f.peer(0)})
+	assert.True(t, errs[0] != nil || errs[1] != nil, "the handshake must fail")
+}
+
+func TestRejectsHandshakeForAnotherSession(t *testing.T) {
+	f := newFixture(t)
+	a, b := pipe()
+	// the session id is part of the prologue, so rewriting it on the wire breaks the handshake
+	a.tamper = func(env *transport.Envelope) { env.SessionID = "other" }
+	b.tamper = func(env *transport.Envelope) { env.SessionID = "other" }
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, inner := range []transport.Conn{a, b} {
+		wg.Add(1)
+		go func(i int, inner transport.Conn) {
+			defer wg.Done()
+			_, errs[i] = Handshake(inner, []string{"one", "other"}[i], f.pIDs[i], f.identities[i], f.peer(1-i))
+			inner.Close()
+		}(i, inner)
+	}
+	wg.Wait()
+	assert.Error(t, errs[0])
+	assert.Error(t, errs[1])
+}
+
+func TestRejectsForgedMessages(t *testing.T) {
+	f := newFixture(t)
+	payload := []byte("round 1")
+	send := func(t *testing.T, tamper func(env *transport.Envelope)) ([2]transport.Conn, *pipeConn) {
+		a, b := pipe()
+		conns, errs := f.handshake("forgery", [2]transport.Conn{a, b}, [2]Peer{f.peer(1), f.peer(0)})
+		assert.NoError(t, errs[0])
+		assert.NoError(t, errs[1])
+		a.tamper = tamper
+		assert.NoError(t, conns[0].Send(&transport.Envelope{SessionID: "forgery", From: f.pIDs[0], IsBroadcast: true, WireBytes: payload}))
+		return conns, a
+	}
+
+	t.Run("intact", func(t *testing.T) {
+		conns, _ := send(t, nil)
+		env, err := conns[1].Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, payload, env.WireBytes)
+		assert.Equal(t, f.pIDs[0].Id, env.From.Id)
+	})
+	t.Run("flipped bit", func(t *testing.T) {
+		conns, _ := send(t, func(env *transport.Envelope) { env.WireBytes[len(env.WireBytes)-1] ^= 1 })
+		_, err := conns[1].Recv()
+		assert.Error(t, err)
+	})
+	t.Run("rerouted", func(t *testing.T) {
+		conns, _ := send(t, func(env *transport.Envelope) {
+			env.IsBroadcast = false
+			env.To = []*tss.PartyID{f.pIDs[1]}
+		})
+		_, err := conns[1].Recv()
+		assert.Error(t, err)
+	})
+	t.Run("replayed", func(t *testing.T) {
+		var sent *transport.Envelope
+		conns, a := send(t, func(env *transport.Envelope) { sent = env })
+		_, err := conns[1].Recv()
+		assert.NoError(t, err)
+		a.out <- sent
+		_, err = conns[1].Recv()
+		assert.Error(t, err)
+	})
+	t.Run("impersonated", func(t *testing.T) {
+		a, b := pipe()
+		conns, errs := f.handshake("forgery", [2]transport.Conn{a, b}, [2]Peer{f.peer(1), f.peer(0)})
+		assert.NoError(t, errs[0])
+		assert.NoError(t, errs[1])
+		stranger := tss.GenerateTestPartyIDs(1, 5)[0]
+		assert.Error(t, conns[0].Send(&transport.Envelope{SessionID: "forgery", From: stranger, WireBytes: payload}))
+		// nor can an unsealed message be slipped in underneath
+		a.Send(&transport.Envelope{SessionID: "forgery", From: f.pIDs[0], IsBroadcast: true, WireBytes: append([]byte{frameData}, payload...)})
+		_, err := conns[1].Recv()
+		assert.Error(t, err)
+	})
+}
+
+// the channel is independent of the transport underneath, here the HTTP relay
+func TestOverRelay(t *testing.T) {
+	f := newFixture(t)
+	hs := httptest.NewServer(relay.NewServer(time.Minute))
+	defer hs.Close()
+	client := relay.NewClient(hs.URL, hs.Client())
+	ctx := context.Background()
+	sess, err := client.CreateSession(ctx, tss.UnSortedPartyIDs{f.pIDs[0], f.pIDs[1]})
+	assert.NoError(t, err)
+
+	var inner [2]transport.Conn
+	for i := range inner {
+		inner[i], err = client.Join(ctx, sess.SessionID, f.pIDs[i], sess.Tokens[f.pIDs[i].Id])
+		assert.NoError(t, err)
+		defer inner[i].Close()
+	}
+	conns, errs := f.handshake(sess.SessionID, inner, [2]Peer{f.peer(1), f.peer(0)})
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+
+	assert.NoError(t, conns[1].Send(&transport.Envelope{SessionID: sess.SessionID, From: f.pIDs[1], To: []*tss.PartyID{f.pIDs[0]}, WireBytes: []byte("round 2")}))
+	env, err := conns[0].Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("round 2"), env.WireBytes)
+}
@@ -0,0 +1,157 @@
+// Package transport moves the messages of a two-party session between processes. A Conn carries Envelopes, the
+// routing of a tss.Message together with its WireBytes(), and Run plugs a party's out and end channels into a Conn.
+// Implementations live in the sub-packages, e.g. grpctransport.
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bnb-chain/tss-lib/tss"
+)
+
+// Envelope is one tss message of a session on the wire
+type Envelope struct {
+	SessionID string
+	// Type is the tss message type, i.e. the protocol round the message belongs to. Not every transport carries it.
+	Type        string
+	From        *tss.PartyID
+	To          []*tss.PartyID
+	IsBroadcast bool
+	WireBytes   []byte
+}
+
+// Conn is a bidirectional, ordered channel to the peer of one session. Recv returns io.EOF once the peer has closed it.
+type Conn interface {
+	Send(env *Envelope) error
+	Recv() (*Envelope, error)
+	Close() error
+}
+
+// Aborter is implemented by parties that want to know when Run gives their session up, e.g. to record why
+type Aborter interface {
+	Abort(err error)
+}
+
+// NewEnvelope wraps a message output by a party
+func NewEnvelope(sessionID string, msg tss.Message) (*Envelope, error) {
+	bz, routing, err := msg.WireBytes()
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		SessionID:   sessionID,
+		Type:        msg.Type(),
+		From:        routing.From,
+		To:          routing.To,
+		IsBroadcast: routing.IsBroadcast,
+		WireBytes:   bz,
+	}, nil
+}
+
+// Run starts party and exchanges its messages with the peer over conn until the party outputs its result on end.
+// ids is the sorted party set of the session; the sender of an incoming envelope is resolved against it by key, so
+// the peer cannot claim another identity's index or moniker. Run does not close conn. If Run fails and party is an
+// Aborter, it is told the error.
+func Run[T any](ctx context.Context, conn Conn, sessionID string, ids tss.SortedPartyIDs, party tss.Party,
+	out <-chan tss.Message, end <-chan T) (T, error) {
+	result, err := run(ctx, conn, sessionID, ids, party, out, end)
+	if err != nil {
+		if a, ok := party.(Aborter); ok {
+			a.Abort(err)
+		}
+	}
+	return result, err
+}
+
+func run[T any](ctx context.Context, conn Conn, sessionID string, ids tss.SortedPartyIDs, party tss.Party,
+	out <-chan tss.Message, end <-chan T) (T, error) {
+	var zero T
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, 2)
+	report := func(err error) {
+		select {
+		case errCh <- err:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		if err := party.Start(); err != nil {
+			report(err)
+		}
+	}()
+	go func() {
+		for {
+			env, err := conn.Recv()
+			if err != nil {
+				if ctx.Err() == nil {
+					if errors.Is(err, io.EOF) {
+						err = errors.New("the peer closed the session")
+					}
+					report(err)
+				}
+				return
+			}
+			if err = deliver(sessionID, ids, party, env); err != nil {
+				report(err)
+				return
+			}
+		}
+	}()
+
+	send := func(msg tss.Message) error {
+		env, err := NewEnvelope(sessionID, msg)
+		if err != nil {
+			return err
+		}
+		return conn.Send(env)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case err := <-errCh:
+			return zero, err
+		case msg := <-out:
+			if err := send(msg); err != nil {
+				return zero, err
+			}
+		case result := <-end:
+			// a party sends its last messages before its result, flush whatever is still buffered
+			for {
+				select {
+				case msg := <-out:
+					if err := send(msg); err != nil {
+						return zero, err
+					}
+				default:
+					return result, nil
+				}
+			}
+		}
+	}
+}
+
+func deliver(sessionID string, ids tss.SortedPartyIDs, party tss.Party, env *Envelope) error {
+	if env.SessionID != sessionID {
+		return fmt.Errorf("received a message of session %q in session %q", env.SessionID, sessionID)
+	}
+	if env.From == nil {
+		return errors.New("received a message without a sender")
+	}
+	from := ids.FindByKey(env.From.KeyInt())
+	if from == nil || from.Id != env.From.Id {
+		return fmt.Errorf("received a message from %s, who is not part of the session", env.From)
+	}
+	if from.KeyInt().Cmp(party.PartyID().KeyInt()) == 0 {
+		return errors.New("received a message that claims to come from this party")
+	}
+	if _, err := party.UpdateFromBytes(env.WireBytes, from, env.IsBroadcast); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package wstransport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/gorilla/websocket"
+)
+
+// Client is the client side of one WebSocket connection to a Server, over which it opens sessions
+type Client struct {
+	url    string
+	header http.Header
+	dialer *websocket.Dialer
+	// reconnectTimeout bounds how long the client keeps redialing a dropped socket
+	reconnectTimeout time.Duration
+
+	link   *link
+	token  string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Dial connects to the Server at url (ws:// or wss://). header is sent with every (re)connect, e.g. for
+// authentication. A dropped socket is redialed for up to reconnectTimeout before the open sessions fail.
+func Dial(ctx context.Context, url string, header http.Header, reconnectTimeout time.Duration) (*Client, error) {
+	c := &Client{
+		url:              url,
+		header:           header,
+		dialer:           websocket.DefaultDialer,
+		reconnectTimeout: reconnectTimeout,
+		link:             newLink(nil),
+	}
+	ws, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	go c.run(ws)
+	return c, nil
+}
+
+// Open starts a session on the connection on behalf of self
+func (c *Client) Open(sessionID string, self *tss.PartyID) (transport.Conn, error) {
+	if sessionID == "" || self == nil {
+		return nil, errors.New("a session id and the calling party are required")
+	}
+	c.link.mtx.Lock()
+	if c.link.err != nil {
+		c.link.mtx.Unlock()
+		return nil, c.link.err
+	}
+	if _, ok := c.link.sessions[sessionID]; ok {
+		c.link.mtx.Unlock()
+		return nil, fmt.Errorf("session %q is already open", sessionID)
+	}
+	sess := newSession(c.link, sessionID)
+	c.link.sessions[sessionID] = sess
+	c.link.mtx.Unlock()
+
+	if err := c.link.send(sess, &Frame{Kind: kindOpen, From: newPartyID(self)}); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Close drops the connection and fails every session still open on it
+func (c *Client) Close() error {
+	c.cancel()
+	c.link.fail(ErrClosed)
+	return nil
+}
+
+// connect dials, greets the server and resends what it has not received
+func (c *Client) connect(ctx context.Context) (*websocket.Conn, error) {
+	ws, _, err := c.dialer.DialContext(ctx, c.url, c.header)
+	if err != nil {
+		return nil, err
+	}
+	ws.SetReadDeadline(time.Now().Add(pongWait))
+	var welcome Frame
+	err = ws.WriteJSON(&Frame{Kind: kindHello, Token: c.token, Acks: c.link.acks()})
+	if err == nil {
+		err = ws.ReadJSON(&welcome)
+	}
+	if err == nil && (welcome.Kind != kindWelcome || welcome.Error != "") {
+		err = &rejectedError{reason: welcome.Error}
+	}
+	if err == nil {
+		c.token = welcome.Token
+		err = c.link.attach(ws, welcome.Acks, nil)
+	}
+	if err != nil {
+		ws.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// run reads from the socket and redials it whenever it drops
+func (c *Client) run(ws *websocket.Conn) {
+	for {
+		c.link.readLoop(ws)
+		c.link.detach(ws)
+		ws.Close()
+		if c.ctx.Err() != nil {
+			return
+		}
+		var err error
+		if ws, err = c.reconnect(); err != nil {
+			c.link.fail(fmt.Errorf("wstransport: reconnecting: %w", err))
+			return
+		}
+	}
+}
+
+func (c *Client) reconnect() (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.reconnectTimeout)
+	defer cancel()
+	backoff := 50 * time.Millisecond
+	for {
+		ws, err := c.connect(ctx)
+		var rejected *rejectedError
+		if err == nil || errors.As(err, &rejected) {
+			return ws, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		if backoff < 2*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// rejectedError is the server turning a connection down, which redialing does not fix
+type rejectedError struct {
+	reason string
+}
+
+func (e *rejectedError) Error() string {
+	return "wstransport: the server rejected the connection: " + e.reason
+}
@@ -0,0 +1,441 @@
+// Package wstransport multiplexes two-party sessions over one WebSocket, for clients such as mobile apps that can
+// only keep a single socket open. The client Dials a Server once and Opens any number of sessions on the connection,
+// which the server side Accepts by session ID.
+//
+// Every session frame is numbered and kept until the peer acknowledges it. When the socket drops, the client redials
+// and both sides exchange the last sequence number they received per session and resend the rest, so a session
+// survives a reconnect without losing or repeating messages.
+package wstransport
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	kindHello   = "hello"   // client -> server, first frame on every socket
+	kindWelcome = "welcome" // server -> client, answer to hello
+	kindOpen    = "open"    // client -> server, opens a session
+	kindMsg     = "msg"     // a tss message of a session
+	kindClose   = "close"   // the sender is done with a session
+	kindAck     = "ack"     // acknowledges the session frames up to Ack
+
+	pingPeriod = 15 * time.Second
+	pongWait   = 3 * pingPeriod
+	// a socket carries a handful of frames per session, this only bounds what a peer can make us buffer
+	maxFrameBytes = 1 << 20
+)
+
+// ErrClosed is returned by the sessions of a link that was closed locally
+var ErrClosed = errors.New("wstransport: connection closed")
+
+type PartyID struct {
+	ID      string `json:"id"`
+	Moniker string `json:"moniker"`
+	Key     []byte `json:"key"`
+}
+
+// Frame is one JSON text message on the socket. Open, msg and close frames are numbered per session and direction.
+type Frame struct {
+	Kind      string `json:"kind"`
+	SessionID string `json:"session_id,omitempty"`
+	Seq       uint64 `json:"seq,omitempty"`
+	Ack       uint64 `json:"ack,omitempty"`
+	// Type is the tss message type of a msg frame, i.e. its round
+	Type        string     `json:"type,omitempty"`
+	From        *PartyID   `json:"from,omitempty"`
+	To          []*PartyID `json:"to,omitempty"`
+	IsBroadcast bool       `json:"is_broadcast,omitempty"`
+	WireBytes   []byte     `json:"wire_bytes,omitempty"`
+	// Token identifies the link across reconnects; Acks holds the last seq received per session
+	Token string            `json:"token,omitempty"`
+	Acks  map[string]uint64 `json:"acks,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+// link is one side of a logical connection, which outlives the sockets it runs over
+type link struct {
+	// gorilla allows one writer at a time. It is held across numbering and writing a frame so frames go out in
+	// order, and is always taken before mtx.
+	writeMtx sync.Mutex
+	mtx      sync.Mutex
+	ws       *websocket.Conn // nil while disconnected
+	sessions map[string]*session
+	err      error // set once the link is dead
+	// onOpen is called for a session the peer opened, the client side has none and refuses them. It is called
+	// without mtx held, the server takes its own mutex in it and holds that one while taking mtx.
+	onOpen func(s *session) error
+}
+
+func newLink(onOpen func(s *session) error) *link {
+	return &link{sessions: make(map[string]*session), onOpen: onOpen}
+}
+
+// acks returns the last seq received for each session
+func (l *link) acks() map[string]uint64 {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	acks := make(map[string]uint64, len(l.sessions))
+	for id, s := range l.sessions {
+		acks[id] = s.recvSeq
+	}
+	return acks
+}
+
+// attach makes ws the socket of the link, writes greeting if any and resends every frame the peer has not seen
+func (l *link) attach(ws *websocket.Conn, peerAcks map[string]uint64, greeting *Frame) error {
+	l.writeMtx.Lock()
+	defer l.writeMtx.Unlock()
+	l.mtx.Lock()
+	if l.err != nil {
+		l.mtx.Unlock()
+		return l.err
+	}
+	l.ws = ws
+	var resend []*Frame
+	for id, s := range l.sessions {
+		s.acked(peerAcks[id])
+		resend = append(resend, s.unacked...)
+	}
+	l.mtx.Unlock()
+
+	if greeting != nil {
+		if err := ws.WriteJSON(greeting); err != nil {
+			return err
+		}
+	}
+	for _, f := range resend {
+		if err := ws.WriteJSON(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detach forgets ws and reports whether it was the current socket
+func (l *link) detach(ws *websocket.Conn) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.ws != ws {
+		return false
+	}
+	l.ws = nil
+	return true
+}
+
+// fail kills the link and every session on it
+func (l *link) fail(err error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.err != nil {
+		return
+	}
+	l.err = err
+	if l.ws != nil {
+		l.ws.Close()
+		l.ws = nil
+	}
+	for _, s := range l.sessions {
+		s.fail(err)
+	}
+}
+
+// send numbers a session frame and writes it, or keeps it for the next socket while disconnected
+func (l *link) send(s *session, f *Frame) error {
+	l.writeMtx.Lock()
+	defer l.writeMtx.Unlock()
+	l.mtx.Lock()
+	if l.err != nil {
+		l.mtx.Unlock()
+		return l.err
+	}
+	s.sendSeq++
+	f.SessionID, f.Seq = s.id, s.sendSeq
+	s.unacked = append(s.unacked, f)
+	ws := l.ws
+	l.mtx.Unlock()
+
+	if ws != nil {
+		// a failed write surfaces in the read loop, which reconnects and resends
+		_ = ws.WriteJSON(f)
+	}
+	return nil
+}
+
+func (l *link) writeAck(sessionID string, seq uint64) {
+	l.writeMtx.Lock()
+	defer l.writeMtx.Unlock()
+	l.mtx.Lock()
+	ws := l.ws
+	l.mtx.Unlock()
+	if ws != nil {
+		_ = ws.WriteJSON(&Frame{Kind: kindAck, SessionID: sessionID, Ack: seq})
+	}
+}
+
+// readLoop handles the frames of ws until it fails
+func (l *link) readLoop(ws *websocket.Conn) error {
+	ws.SetReadLimit(maxFrameBytes)
+	ws.SetReadDeadline(time.Now().Add(pongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingPeriod)); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		var f Frame
+		if err := ws.ReadJSON(&f); err != nil {
+			return err
+		}
+		ws.SetReadDeadline(time.Now().Add(pongWait))
+		if err := l.handle(&f); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *link) handle(f *Frame) error {
+	if f.Kind == kindAck {
+		l.mtx.Lock()
+		if s, ok := l.sessions[f.SessionID]; ok {
+			s.acked(f.Ack)
+		}
+		l.mtx.Unlock()
+		return nil
+	}
+	if f.Kind != kindOpen && f.Kind != kindMsg && f.Kind != kindClose {
+		return errors.New("wstransport: unexpected frame " + f.Kind)
+	}
+	if f.SessionID == "" || f.Seq == 0 {
+		return errors.New("wstransport: session frame without a session or seq")
+	}
+
+	l.mtx.Lock()
+	s, ok := l.sessions[f.SessionID]
+	if !ok {
+		if f.Kind != kindOpen || f.Seq != 1 {
+			// a late frame of a session that is gone; acknowledge it so the peer stops resending it
+			l.mtx.Unlock()
+			l.writeAck(f.SessionID, f.Seq)
+			return nil
+		}
+		s = newSession(l, f.SessionID)
+		l.sessions[s.id] = s
+	}
+	switch {
+	case f.Seq <= s.recvSeq:
+		// resent after a reconnect, already handled
+		l.mtx.Unlock()
+		l.writeAck(f.SessionID, s.recvSeq)
+		return nil
+	case f.Seq != s.recvSeq+1:
+		l.mtx.Unlock()
+		return errors.New("wstransport: frames out of order")
+	}
+	s.recvSeq = f.Seq
+	var rejected error
+	opened := false
+	switch f.Kind {
+	case kindOpen:
+		if f.From == nil || l.onOpen == nil {
+			rejected = errors.New("the session cannot be opened from this side")
+		} else {
+			s.peer = f.From.unmarshal()
+			opened = true
+		}
+	case kindMsg:
+		env := &transport.Envelope{
+			SessionID:   s.id,
+			Type:        f.Type,
+			From:        f.From.unmarshal(),
+			IsBroadcast: f.IsBroadcast,
+			WireBytes:   f.WireBytes,
+		}
+		for _, to := range f.To {
+			env.To = append(env.To, to.unmarshal())
+		}
+		s.push(env)
+	case kindClose:
+		err := io.EOF
+		if f.Error != "" {
+			err = errors.New(f.Error)
+		}
+		s.peerClosed(err)
+	}
+	l.mtx.Unlock()
+
+	if opened {
+		rejected = l.onOpen(s)
+	}
+	l.writeAck(f.SessionID, f.Seq)
+	if rejected != nil {
+		s.closeWithError(rejected)
+	}
+	return nil
+}
+
+// ----- //
+
+// session is a transport.Conn multiplexed on a link. Its fields are guarded by the link's mtx.
+type session struct {
+	link *link
+	id   string
+	peer *tss.PartyID
+
+	sendSeq uint64
+	unacked []*Frame
+	recvSeq uint64
+
+	inbox   []*transport.Envelope
+	notify  chan struct{}
+	err     error // returned by Recv once the inbox is drained
+	closing bool
+	closed  sync.Once
+}
+
+var _ transport.Conn = (*session)(nil)
+
+func newSession(l *link, id string) *session {
+	return &session{link: l, id: id, notify: make(chan struct{}, 1)}
+}
+
+func (s *session) Send(env *transport.Envelope) error {
+	if env.SessionID != s.id {
+		return errors.New("envelope belongs to another session")
+	}
+	s.link.mtx.Lock()
+	closing := s.closing
+	s.link.mtx.Unlock()
+	if closing {
+		return ErrClosed
+	}
+	f := &Frame{
+		Kind:        kindMsg,
+		Type:        env.Type,
+		From:        newPartyID(env.From),
+		IsBroadcast: env.IsBroadcast,
+		WireBytes:   env.WireBytes,
+	}
+	for _, to := range env.To {
+		f.To = append(f.To, newPartyID(to))
+	}
+	return s.link.send(s, f)
+}
+
+func (s *session) Recv() (*transport.Envelope, error) {
+	for {
+		s.link.mtx.Lock()
+		if len(s.inbox) > 0 {
+			env := s.inbox[0]
+			s.inbox = s.inbox[1:]
+			s.link.mtx.Unlock()
+			return env, nil
+		}
+		if s.err != nil {
+			err := s.err
+			s.link.mtx.Unlock()
+			return nil, err
+		}
+		s.link.mtx.Unlock()
+		<-s.notify
+	}
+}
+
+// Close tells the peer this side is done and ends Recv
+func (s *session) Close() error {
+	s.closeWithError(nil)
+	return nil
+}
+
+func (s *session) closeWithError(reason error) {
+	s.closed.Do(func() {
+		f := &Frame{Kind: kindClose}
+		if reason != nil {
+			f.Error = reason.Error()
+		}
+		_ = s.link.send(s, f)
+		s.link.mtx.Lock()
+		s.closing = true
+		s.fail(ErrClosed)
+		s.forgetIfDone()
+		s.link.mtx.Unlock()
+	})
+}
+
+// the methods below are called with the link's mtx held
+
+func (s *session) push(env *transport.Envelope) {
+	s.inbox = append(s.inbox, env)
+	s.wake()
+}
+
+func (s *session) peerClosed(err error) {
+	s.fail(err)
+}
+
+func (s *session) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+	s.wake()
+}
+
+func (s *session) acked(seq uint64) {
+	for len(s.unacked) > 0 && s.unacked[0].Seq <= seq {
+		s.unacked = s.unacked[1:]
+	}
+	s.forgetIfDone()
+}
+
+// forgetIfDone drops a locally closed session once the peer has everything it sent
+func (s *session) forgetIfDone() {
+	if s.closing && len(s.unacked) == 0 && s.link.sessions[s.id] == s {
+		delete(s.link.sessions, s.id)
+	}
+}
+
+func (s *session) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// ----- //
+
+func newPartyID(p *tss.PartyID) *PartyID {
+	if p == nil {
+		return nil
+	}
+	return &PartyID{ID: p.Id, Moniker: p.Moniker, Key: p.Key}
+}
+
+func (p *PartyID) unmarshal() *tss.PartyID {
+	if p == nil {
+		return nil
+	}
+	return tss.NewPartyID(p.ID, p.Moniker, new(big.Int).SetBytes(p.Key))
+}
@@ -0,0 +1,181 @@
+package wstransport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/gorilla/websocket"
+)
+
+// defaultOfferTimeout is how long a session a client opened waits for its Accept before it is refused
+const defaultOfferTimeout = time.Minute
+
+// Server is the http.Handler of the WebSocket endpoint. It hands each session a client opens to the Accept call
+// waiting for its session ID.
+type Server struct {
+	upgrader         websocket.Upgrader
+	reconnectTimeout time.Duration
+	offerTimeout     time.Duration
+
+	// taken before the mtx of any link
+	mtx     sync.Mutex
+	links   map[string]*link // by token
+	pending map[string]chan *session
+}
+
+// NewServer returns a Server that keeps the sessions of a dropped client for reconnectTimeout.
+// checkOrigin is passed to the websocket upgrader, nil only accepts same-origin browsers and non-browser clients.
+func NewServer(reconnectTimeout time.Duration, checkOrigin func(r *http.Request) bool) *Server {
+	return &Server{
+		upgrader:         websocket.Upgrader{CheckOrigin: checkOrigin},
+		reconnectTimeout: reconnectTimeout,
+		offerTimeout:     defaultOfferTimeout,
+		links:            make(map[string]*link),
+		pending:          make(map[string]chan *session),
+	}
+}
+
+// Accept waits until a client opens sessionID and returns the session as a Conn together with the party that
+// opened it
+func (s *Server) Accept(ctx context.Context, sessionID string) (transport.Conn, *tss.PartyID, error) {
+	ch := s.sessionChan(sessionID)
+	defer s.release(sessionID)
+	select {
+	case sess := <-ch:
+		return sess, sess.peer, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has replied already
+		return
+	}
+	defer ws.Close()
+	ws.SetReadLimit(maxFrameBytes)
+	ws.SetReadDeadline(time.Now().Add(pongWait))
+	var hello Frame
+	if err = ws.ReadJSON(&hello); err != nil || hello.Kind != kindHello {
+		return
+	}
+
+	token, l, err := s.resume(hello.Token)
+	if err != nil {
+		ws.WriteJSON(&Frame{Kind: kindWelcome, Error: err.Error()})
+		return
+	}
+	welcome := &Frame{Kind: kindWelcome, Token: token, Acks: l.acks()}
+	if err = l.attach(ws, hello.Acks, welcome); err != nil {
+		return
+	}
+	l.readLoop(ws)
+	if l.detach(ws) {
+		// give the client a while to come back before failing its sessions
+		time.AfterFunc(s.reconnectTimeout, func() {
+			l.mtx.Lock()
+			reconnected := l.ws != nil
+			l.mtx.Unlock()
+			if !reconnected {
+				s.forget(token, l)
+				l.fail(errors.New("wstransport: the client did not reconnect"))
+			}
+		})
+	}
+}
+
+// resume returns the link of token, or a new link if token is empty. A socket still attached to the link is
+// replaced.
+func (s *Server) resume(token string) (string, *link, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if token == "" {
+		token = newToken()
+		l := newLink(s.offer)
+		s.links[token] = l
+		return token, l, nil
+	}
+	l, ok := s.links[token]
+	if !ok {
+		return "", nil, errors.New("unknown or expired connection token")
+	}
+	l.mtx.Lock()
+	if l.ws != nil {
+		l.ws.Close()
+	}
+	l.mtx.Unlock()
+	return token, l, nil
+}
+
+func (s *Server) forget(token string, l *link) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.links[token] == l {
+		delete(s.links, token)
+	}
+}
+
+// offer queues a session a client opened for Accept. A session no Accept takes within offerTimeout is dropped and
+// refused, so IDs nobody accepts do not pile up.
+func (s *Server) offer(sess *session) error {
+	ch := s.sessionChan(sess.id)
+	select {
+	case ch <- sess:
+	default:
+		return errors.New("the session already has a peer")
+	}
+	time.AfterFunc(s.offerTimeout, func() {
+		s.mtx.Lock()
+		var stale *session
+		select {
+		case stale = <-ch:
+			if s.pending[sess.id] == ch {
+				delete(s.pending, sess.id)
+			}
+		default:
+			// accepted already
+		}
+		s.mtx.Unlock()
+		if stale != nil {
+			stale.closeWithError(errors.New("the session was not accepted in time"))
+		}
+	})
+	return nil
+}
+
+func (s *Server) sessionChan(sessionID string) chan *session {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	ch, ok := s.pending[sessionID]
+	if !ok {
+		ch = make(chan *session, 1)
+		s.pending[sessionID] = ch
+	}
+	return ch
+}
+
+// release forgets the pending channel of sessionID once Accept returns. IDs are not remembered, a client opening the
+// ID again later is queued for the next Accept.
+func (s *Server) release(sessionID string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.pending, sessionID)
+}
+
+func newToken() string {
+	bz := make([]byte, 16)
+	if _, err := rand.Read(bz); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(bz)
+}
@@ -0,0 +1,230 @@
+package wstransport
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-rust/lindell/eddsa"
+	"go-rust/lindell/eddsa/keygen"
+	"go-rust/lindell/eddsa/signing"
+	"go-rust/lindell/transport"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func startServer(t *testing.T) (*Server, *Client) {
+	srv := NewServer(10*time.Second, nil)
+	hs := httptest.NewServer(srv)
+	t.Cleanup(hs.Close)
+	client, err := Dial(context.Background(), "ws"+strings.TrimPrefix(hs.URL, "http"), nil, 10*time.Second)
+	assert.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return srv, client
+}
+
+// dropSocket closes the client's current socket as a flaky mobile network would
+func dropSocket(c *Client) {
+	c.link.mtx.Lock()
+	defer c.link.mtx.Unlock()
+	if c.link.ws != nil {
+		c.link.ws.UnderlyingConn().Close()
+	}
+}
+
+// runPair runs party 0 behind the server and party 1 behind the client. wrap, if set, wraps the server's Conn.
+func runPair[T any](t *testing.T, srv *Server, client *Client, sessionID string, pIDs tss.SortedPartyIDs,
+	wrap func(transport.Conn) transport.Conn, newParty func(i int, out chan<- tss.Message, end chan<- T) tss.Party) [2]T {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var results [2]T
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			var conn transport.Conn
+			var err error
+			if i == 0 {
+				var peer *tss.PartyID
+				conn, peer, err = srv.Accept(ctx, sessionID)
+				if err == nil && peer.KeyInt().Cmp(pIDs[1].KeyInt()) != 0 {
+					t.Errorf("accepted %s, expected %s", peer, pIDs[1])
+				}
+				if err == nil && wrap != nil {
+					conn = wrap(conn)
+				}
+			} else {
+				conn, err = client.Open(sessionID, pIDs[1])
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+
+			out, end := make(chan tss.Message, 2), make(chan T, 1)
+			results[i], err = transport.Run(ctx, conn, sessionID, pIDs, newParty(i, out, end), out, end)
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, <-errs)
+	}
+	return results
+}
+
+func keygenParty(pIDs tss.SortedPartyIDs) func(int, chan<- tss.Message, chan<- keygen.LocalPartySaveData) tss.Party {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	return func(i int, out chan<- tss.Message, end chan<- keygen.LocalPartySaveData) tss.Party {
+		return keygen.NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], 2, 1), out, end)
+	}
+}
+
+func TestMultiplexedSessions(t *testing.T) {
+	srv, client := startServer(t)
+
+	// concurrent keygens share the one socket
+	const sessions = 3
+	pIDs := make([]tss.SortedPartyIDs, sessions)
+	keys := make([][2]keygen.LocalPartySaveData, sessions)
+	var wg sync.WaitGroup
+	for s := 0; s < sessions; s++ {
+		pIDs[s] = tss.SortPartyIDs(tss.UnSortedPartyIDs(tss.GenerateTestPartyIDs(2, 10*s)))
+		wg.Add(1)
+		go func(s int) {
+			defer wg.Done()
+			keys[s] = runPair(t, srv, client, fmt.Sprintf("keygen-%d", s), pIDs[s], nil, keygenParty(pIDs[s]))
+		}(s)
+	}
+	wg.Wait()
+
+	msg := []byte("over a websocket")
+	for s := 0; s < sessions; s++ {
+		assert.True(t, keys[s][0].EDDSAPub.Equals(keys[s][1].EDDSAPub), "both parties must agree on the public key")
+		p2pCtx := tss.NewPeerContext(pIDs[s])
+		sigs := runPair(t, srv, client, fmt.Sprintf("signing-%d", s), pIDs[s], nil,
+			func(i int, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Party {
+				return signing.NewLocalParty(msg, tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[s][i], 2, 1), keys[s][i], out, end)
+			})
+		pub := ed25519.PublicKey(eddsa.EncodePoint(keys[s][0].EDDSAPub))
+		for _, sig := range sigs {
+			assert.True(t, ed25519.Verify(pub, msg, sig.GetSignature()), "ed25519 verify must pass")
+		}
+	}
+}
+
+// dropOnFirstRecv drops the client's socket as soon as the server side has received its first message
+type dropOnFirstRecv struct {
+	transport.Conn
+	client *Client
+	once   sync.Once
+}
+
+func (c *dropOnFirstRecv) Recv() (*transport.Envelope, error) {
+	env, err := c.Conn.Recv()
+	c.once.Do(func() { dropSocket(c.client) })
+	return env, err
+}
+
+func TestKeygenSurvivesReconnect(t *testing.T) {
+	srv, client := startServer(t)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	keys := runPair(t, srv, client, "flaky", pIDs,
+		func(conn transport.Conn) transport.Conn { return &dropOnFirstRecv{Conn: conn, client: client} },
+		keygenParty(pIDs))
+	assert.True(t, keys[0].EDDSAPub.Equals(keys[1].EDDSAPub), "both parties must agree on the public key")
+}
+
+func TestNoLossOrDuplicatesAcrossReconnects(t *testing.T) {
+	srv, client := startServer(t)
+	pIDs := tss.GenerateTestPartyIDs(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientConn, err := client.Open("ordered", pIDs[1])
+	assert.NoError(t, err)
+	serverConn, peer, err := srv.Accept(ctx, "ordered")
+	assert.NoError(t, err)
+	assert.Equal(t, pIDs[1].Id, peer.Id)
+
+	const n = 20
+	go func() {
+		for i := 0; i < n; i++ {
+			if i%5 == 2 {
+				dropSocket(client)
+			}
+			clientConn.Send(&transport.Envelope{SessionID: "ordered", Type: "test", From: pIDs[1], IsBroadcast: true, WireBytes: []byte{byte(i)}})
+		}
+		clientConn.Close()
+	}()
+	for i := 0; i < n; i++ {
+		env, err := serverConn.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{byte(i)}, env.WireBytes)
+		assert.Equal(t, "test", env.Type)
+	}
+	_, err = serverConn.Recv()
+	assert.ErrorIs(t, err, io.EOF, "the client closed the session")
+}
+
+func TestSecondPeerRejected(t *testing.T) {
+	srv, first := startServer(t)
+	pIDs := tss.GenerateTestPartyIDs(2)
+
+	_, err := first.Open("busy", pIDs[1])
+	assert.NoError(t, err)
+	_, err = first.Open("busy", pIDs[1])
+	assert.Error(t, err, "a session id is opened once per connection")
+
+	hs := httptest.NewServer(srv)
+	defer hs.Close()
+	second, err := Dial(context.Background(), "ws"+strings.TrimPrefix(hs.URL, "http"), nil, time.Second)
+	assert.NoError(t, err)
+	defer second.Close()
+
+	// wait for the first open to be queued, then a second peer is turned away
+	time.Sleep(100 * time.Millisecond)
+	conn, err := second.Open("busy", pIDs[0])
+	assert.NoError(t, err)
+	_, err = conn.Recv()
+	assert.EqualError(t, err, "the session already has a peer")
+}
+
+func TestUnknownTokenRejected(t *testing.T) {
+	srv, _ := startServer(t)
+	hs := httptest.NewServer(srv)
+	defer hs.Close()
+	c := &Client{
+		url:    "ws" + strings.TrimPrefix(hs.URL, "http"),
+		dialer: websocket.DefaultDialer,
+		token:  "forged",
+		link:   newLink(nil),
+	}
+	_, err := c.connect(context.Background())
+	var rejected *rejectedError
+	assert.ErrorAs(t, err, &rejected)
+}
+
+func TestUnacceptedSessionExpires(t *testing.T) {
+	srv, client := startServer(t)
+	srv.offerTimeout = 50 * time.Millisecond
+	pIDs := tss.GenerateTestPartyIDs(2)
+
+	conn, err := client.Open("nobody-accepts", pIDs[1])
+	assert.NoError(t, err)
+	_, err = conn.Recv()
+	assert.EqualError(t, err, "the session was not accepted in time")
+
+	srv.mtx.Lock()
+	assert.Empty(t, srv.pending)
+	srv.mtx.Unlock()
+}
@@ -0,0 +1,34 @@
+// Package zeroize overwrites secrets in place once they are no longer needed. Go cannot promise that no other copy
+// is left: the garbage collector may have moved a value and big.Int arithmetic leaves its intermediate results
+// behind. Wiping the copies we own still shortens the time a secret can be read from memory or a core dump.
+package zeroize
+
+import "math/big"
+
+// Bytes overwrites b with zeros
+func Bytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// Uints overwrites u with zeros
+func Uints(u []uint) {
+	for i := range u {
+		u[i] = 0
+	}
+}
+
+// BigInt sets x to 0, overwriting every word of its backing array first, including the spare capacity that may
+// hold an earlier value. A nil x is ignored.
+func BigInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	words = words[:cap(words)]
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
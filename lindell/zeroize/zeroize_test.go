@@ -0,0 +1,30 @@
+package zeroize
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBigInt(t *testing.T) {
+	x, ok := new(big.Int).SetString("987654321987654321987654321987654321", 10)
+	assert.True(t, ok)
+	words := x.Bits()
+	// a smaller value reuses the backing array and leaves the old high words in its capacity
+	x.Rsh(x, 64)
+	BigInt(x)
+	assert.Equal(t, 0, x.Sign())
+	for i, w := range words[:cap(words)] {
+		assert.Zero(t, w, "word %d", i)
+	}
+	BigInt(nil)
+}
+
+func TestSlices(t *testing.T) {
+	b, u := []byte{1, 2, 3}, []uint{4, 5}
+	Bytes(b)
+	Uints(u)
+	assert.Equal(t, []byte{0, 0, 0}, b)
+	assert.Equal(t, []uint{0, 0}, u)
+}
@@ -0,0 +1,117 @@
+package zkp
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+)
+
+const (
+	DLEQProofBytesParts = 5
+)
+
+type (
+	// DLEQProof is a Chaum-Pedersen proof that H1 = x·G1 and H2 = x·G2 share the discrete log x.
+	// The challenge is SHA-256 over the SEC1-compressed G1, H1, G2, H2, A1, A2 reduced mod q, the same as
+	// DLEqProof in lindellcore, so proofs made on either side of the ffi verify on the other.
+	DLEQProof struct {
+		A1, A2 *crypto.ECPoint
+		Z      *big.Int
+	}
+)
+
+func NewDLEQProof(x *big.Int, G1, H1, G2, H2 *crypto.ECPoint) (*DLEQProof, error) {
+	if x == nil || G1 == nil || H1 == nil || G2 == nil || H2 == nil {
+		return nil, errors.New("NewDLEQProof constructor received nil value(s)")
+	}
+	q := G1.Curve().Params().N
+	w := common.GetRandomPositiveInt(q)
+	a1 := G1.ScalarMult(w)
+	a2 := G2.ScalarMult(w)
+	e := dleqChallenge(G1, H1, G2, H2, a1, a2)
+
+	// z = w + e·x mod q
+	modQ := common.ModInt(q)
+	z := modQ.Add(w, modQ.Mul(e, x))
+	return &DLEQProof{A1: a1, A2: a2, Z: z}, nil
+}
+
+func DLEQProofFromBytes(ec elliptic.Curve, bzs [][]byte) (*DLEQProof, error) {
+	if !common.NonEmptyMultiBytes(bzs, DLEQProofBytesParts) {
+		return nil, fmt.Errorf("expected %d byte parts to construct DLEQProof", DLEQProofBytesParts)
+	}
+	a1, err := crypto.NewECPoint(ec, new(big.Int).SetBytes(bzs[0]), new(big.Int).SetBytes(bzs[1]))
+	if err != nil {
+		return nil, fmt.Errorf("DLEQProofFromBytes: %v", err)
+	}
+	a2, err := crypto.NewECPoint(ec, new(big.Int).SetBytes(bzs[2]), new(big.Int).SetBytes(bzs[3]))
+	if err != nil {
+		return nil, fmt.Errorf("DLEQProofFromBytes: %v", err)
+	}
+	return &DLEQProof{A1: a1, A2: a2, Z: new(big.Int).SetBytes(bzs[4])}, nil
+}
+
+func (pf *DLEQProof) Verify(G1, H1, G2, H2 *crypto.ECPoint) bool {
+	if pf == nil || !pf.ValidateBasic() || G1 == nil || H1 == nil || G2 == nil || H2 == nil {
+		return false
+	}
+	for _, P := range []*crypto.ECPoint{G1, H1, G2, H2, pf.A1, pf.A2} {
+		if !P.ValidateBasic() {
+			return false
+		}
+	}
+	if pf.Z.Cmp(G1.Curve().Params().N) >= 0 {
+		return false
+	}
+	e := dleqChallenge(G1, H1, G2, H2, pf.A1, pf.A2)
+
+	// z·G1 == A1 + e·H1 and z·G2 == A2 + e·H2
+	rhs1, err := pf.A1.Add(H1.ScalarMult(e))
+	if err != nil || !G1.ScalarMult(pf.Z).Equals(rhs1) {
+		return false
+	}
+	rhs2, err := pf.A2.Add(H2.ScalarMult(e))
+	if err != nil || !G2.ScalarMult(pf.Z).Equals(rhs2) {
+		return false
+	}
+	return true
+}
+
+func (pf *DLEQProof) ValidateBasic() bool {
+	return pf.A1 != nil && pf.A2 != nil && pf.Z != nil
+}
+
+func (pf *DLEQProof) Bytes() [DLEQProofBytesParts][]byte {
+	return [...][]byte{
+		pf.A1.X().Bytes(),
+		pf.A1.Y().Bytes(),
+		pf.A2.X().Bytes(),
+		pf.A2.Y().Bytes(),
+		pf.Z.Bytes(),
+	}
+}
+
+// ----- //
+
+func dleqChallenge(points ...*crypto.ECPoint) *big.Int {
+	h := sha256.New()
+	for _, P := range points {
+		h.Write(compressPoint(P))
+	}
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return e.Mod(e, points[0].Curve().Params().N)
+}
+
+// compressPoint is the SEC1 compressed encoding used by curv's Point::to_bytes(true)
+func compressPoint(P *crypto.ECPoint) []byte {
+	size := (P.Curve().Params().BitSize + 7) / 8
+	bz := make([]byte, 1+size)
+	bz[0] = 2 | byte(P.Y().Bit(0))
+	P.X().FillBytes(bz[1:])
+	return bz
+}
@@ -0,0 +1,35 @@
+package zkp
+
+import (
+	"crypto/elliptic"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDLEQProof(t *testing.T) {
+	for _, ec := range []elliptic.Curve{tss.S256(), elliptic.P256()} {
+		q := ec.Params().N
+		x := common.GetRandomPositiveInt(q)
+		G1 := crypto.ScalarBaseMult(ec, one)
+		G2 := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(q))
+		H1, H2 := G1.ScalarMult(x), G2.ScalarMult(x)
+
+		pf, err := NewDLEQProof(x, G1, H1, G2, H2)
+		assert.NoError(t, err)
+		assert.True(t, pf.Verify(G1, H1, G2, H2), "proof must verify")
+
+		bzs := pf.Bytes()
+		pf2, err := DLEQProofFromBytes(ec, bzs[:])
+		assert.NoError(t, err)
+		assert.True(t, pf2.Verify(G1, H1, G2, H2), "decoded proof must verify")
+
+		// H2 with a different discrete log must not pass
+		H2x := G2.ScalarMult(common.GetRandomPositiveInt(q))
+		assert.False(t, pf.Verify(G1, H1, G2, H2x), "proof must not verify unequal discrete logs")
+		assert.False(t, pf.Verify(G2, H2, G1, H1), "proof must not verify swapped statements")
+	}
+}
@@ -0,0 +1,64 @@
+package zkp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+)
+
+const (
+	// MinPaillierModulusBitLen is the smallest Paillier modulus accepted from a counterparty
+	MinPaillierModulusBitLen = 2048
+
+	PaillierModulusProofBytesParts = paillier.ProofIters
+)
+
+// NewPaillierModulusProof proves that the Paillier modulus of sk is square-free (gcd(N, phi(N)) == 1),
+// using the GG18Spec (6) proof bound to the prover's party key k and the joint public key.
+func NewPaillierModulusProof(sk *paillier.PrivateKey, k *big.Int, ecdsaPub *crypto.ECPoint) (paillier.Proof, error) {
+	if sk == nil || k == nil || ecdsaPub == nil {
+		return paillier.Proof{}, errors.New("NewPaillierModulusProof received nil value(s)")
+	}
+	return sk.Proof(k, ecdsaPub), nil
+}
+
+// VerifyPaillierModulus checks that N is long enough, has no small factors and comes with a valid square-free proof.
+func VerifyPaillierModulus(N *big.Int, pf paillier.Proof, k *big.Int, ecdsaPub *crypto.ECPoint) error {
+	if N == nil || k == nil || ecdsaPub == nil {
+		return errors.New("VerifyPaillierModulus received nil value(s)")
+	}
+	if N.BitLen() < MinPaillierModulusBitLen {
+		return fmt.Errorf("paillier modulus is too short (%d < %d bits)", N.BitLen(), MinPaillierModulusBitLen)
+	}
+	if N.Bit(0) == 0 {
+		return errors.New("paillier modulus is even")
+	}
+	for i, pi := range pf {
+		if pi == nil || pi.Sign() != 1 || pi.Cmp(N) != -1 {
+			return fmt.Errorf("paillier modulus proof element %d is out of range", i)
+		}
+	}
+	// Verify also rejects moduli divisible by any prime < 1000
+	ok, err := pf.Verify(N, k, ecdsaPub)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("paillier modulus proof failed to verify")
+	}
+	return nil
+}
+
+func PaillierModulusProofFromBytes(bzs [][]byte) (paillier.Proof, error) {
+	var pf paillier.Proof
+	if len(bzs) != PaillierModulusProofBytesParts {
+		return pf, fmt.Errorf("expected %d byte parts to construct a paillier modulus proof", PaillierModulusProofBytesParts)
+	}
+	for i := range pf {
+		pf[i] = new(big.Int).SetBytes(bzs[i])
+	}
+	return pf, nil
+}
@@ -0,0 +1,48 @@
+package zkp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaillierModulusProof(t *testing.T) {
+	key := loadKeygenTestFixture(t, 0)
+	key.ECDSAPub.SetCurve(tss.S256())
+
+	pf, err := NewPaillierModulusProof(key.PaillierSK, key.ShareID, key.ECDSAPub)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyPaillierModulus(key.PaillierSK.N, pf, key.ShareID, key.ECDSAPub))
+
+	bzs := common.BigIntsToBytes(pf[:])
+	pf2, err := PaillierModulusProofFromBytes(bzs)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyPaillierModulus(key.PaillierSK.N, pf2, key.ShareID, key.ECDSAPub))
+
+	// the proof is bound to the prover's party key
+	assert.Error(t, VerifyPaillierModulus(key.PaillierSK.N, pf, new(big.Int).Add(key.ShareID, big.NewInt(1)), key.ECDSAPub))
+}
+
+func TestPaillierModulusRejectsMalformedModuli(t *testing.T) {
+	key := loadKeygenTestFixture(t, 0)
+	key.ECDSAPub.SetCurve(tss.S256())
+	pf, err := NewPaillierModulusProof(key.PaillierSK, key.ShareID, key.ECDSAPub)
+	assert.NoError(t, err)
+
+	short := new(big.Int).Rsh(key.PaillierSK.N, 1024)
+	assert.Error(t, VerifyPaillierModulus(short, pf, key.ShareID, key.ECDSAPub), "short moduli must be rejected")
+
+	withSmallFactor := new(big.Int).Mul(key.PaillierSK.N, big.NewInt(7))
+	assert.Error(t, VerifyPaillierModulus(withSmallFactor, pf, key.ShareID, key.ECDSAPub), "small factors must be rejected")
+
+	// a modulus with a square factor cannot produce valid N-th roots
+	p := common.GetRandomPrimeInt(1024)
+	squared := new(big.Int).Mul(p, p)
+	assert.Error(t, VerifyPaillierModulus(squared, pf, key.ShareID, key.ECDSAPub))
+
+	_, err = PaillierModulusProofFromBytes(common.BigIntsToBytes(pf[:3]))
+	assert.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package zkp
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+)
+
+const (
+	PDLwSlackProofBytesParts = 8
+)
+
+var (
+	zero = big.NewInt(0)
+	one  = big.NewInt(1)
+)
+
+type (
+	// PDLwSlackProof proves that a Paillier ciphertext c encrypts the discrete log x of Q = x·G,
+	// up to the slack allowed by Lindell 2017 (x is only guaranteed to lie in [-q^3, q^3]).
+	// The commitments are made against the verifier's ring-Pedersen parameters (NTilde, h1, h2).
+	PDLwSlackProof struct {
+		Z          *big.Int
+		U1         *crypto.ECPoint
+		U2, U3     *big.Int
+		S1, S2, S3 *big.Int
+	}
+)
+
+// NewPDLwSlackProof is run by the Paillier key owner, who knows x and the encryption randomness r of c = Enc(x; r).
+func NewPDLwSlackProof(ec elliptic.Curve, pk *paillier.PublicKey, c *big.Int, Q *crypto.ECPoint, NTilde, h1, h2, x, r *big.Int,
+) (*PDLwSlackProof, error) {
+	if ec == nil || pk == nil || c == nil || Q == nil || NTilde == nil || h1 == nil || h2 == nil || x == nil || r == nil {
+		return nil, errors.New("NewPDLwSlackProof constructor received nil value(s)")
+	}
+
+	q := ec.Params().N
+	q3 := new(big.Int).Mul(q, q)
+	q3 = new(big.Int).Mul(q, q3)
+	qNTilde := new(big.Int).Mul(q, NTilde)
+	q3NTilde := new(big.Int).Mul(q3, NTilde)
+
+	alpha := common.GetRandomPositiveInt(q3)
+	beta := common.GetRandomPositiveRelativelyPrimeInt(pk.N)
+	rho := common.GetRandomPositiveInt(qNTilde)
+	gamma := common.GetRandomPositiveInt(q3NTilde)
+
+	// z = h1^x * h2^rho mod NTilde
+	modNTilde := common.ModInt(NTilde)
+	z := modNTilde.Exp(h1, x)
+	z = modNTilde.Mul(z, modNTilde.Exp(h2, rho))
+
+	// u1 = alpha·G
+	u1 := crypto.ScalarBaseMult(ec, new(big.Int).Mod(alpha, q))
+
+	// u2 = Gamma^alpha * beta^N mod N^2
+	modNSquared := common.ModInt(pk.NSquare())
+	u2 := modNSquared.Exp(pk.Gamma(), alpha)
+	u2 = modNSquared.Mul(u2, modNSquared.Exp(beta, pk.N))
+
+	// u3 = h1^alpha * h2^gamma mod NTilde
+	u3 := modNTilde.Exp(h1, alpha)
+	u3 = modNTilde.Mul(u3, modNTilde.Exp(h2, gamma))
+
+	e := pdlChallenge(ec, pk, c, Q, NTilde, h1, h2, z, u1, u2, u3)
+
+	// s1 = e * x + alpha
+	s1 := new(big.Int).Mul(e, x)
+	s1 = new(big.Int).Add(s1, alpha)
+
+	// s2 = r^e * beta mod N
+	modN := common.ModInt(pk.N)
+	s2 := modN.Exp(r, e)
+	s2 = modN.Mul(s2, beta)
+
+	// s3 = e * rho + gamma
+	s3 := new(big.Int).Mul(e, rho)
+	s3 = new(big.Int).Add(s3, gamma)
+
+	return &PDLwSlackProof{Z: z, U1: u1, U2: u2, U3: u3, S1: s1, S2: s2, S3: s3}, nil
+}
+
+func PDLwSlackProofFromBytes(ec elliptic.Curve, bzs [][]byte) (*PDLwSlackProof, error) {
+	if !common.NonEmptyMultiBytes(bzs, PDLwSlackProofBytesParts) {
+		return nil, fmt.Errorf("expected %d byte parts to construct PDLwSlackProof", PDLwSlackProofBytesParts)
+	}
+	u1, err := crypto.NewECPoint(ec, new(big.Int).SetBytes(bzs[1]), new(big.Int).SetBytes(bzs[2]))
+	if err != nil {
+		return nil, fmt.Errorf("PDLwSlackProofFromBytes: %v", err)
+	}
+	return &PDLwSlackProof{
+		Z:  new(big.Int).SetBytes(bzs[0]),
+		U1: u1,
+		U2: new(big.Int).SetBytes(bzs[3]),
+		U3: new(big.Int).SetBytes(bzs[4]),
+		S1: new(big.Int).SetBytes(bzs[5]),
+		S2: new(big.Int).SetBytes(bzs[6]),
+		S3: new(big.Int).SetBytes(bzs[7]),
+	}, nil
+}
+
+// Verify is run by the counterparty, using its own ring-Pedersen parameters (NTilde, h1, h2).
+func (pf *PDLwSlackProof) Verify(ec elliptic.Curve, pk *paillier.PublicKey, c *big.Int, Q *crypto.ECPoint, NTilde, h1, h2 *big.Int) bool {
+	if pf == nil || !pf.ValidateBasic() || ec == nil || pk == nil || c == nil || Q == nil || NTilde == nil || h1 == nil || h2 == nil {
+		return false
+	}
+	if !Q.ValidateBasic() || !pf.U1.ValidateBasic() {
+		return false
+	}
+
+	q := ec.Params().N
+	q3 := new(big.Int).Mul(q, q)
+	q3 = new(big.Int).Mul(q, q3)
+	NSquare := pk.NSquare()
+
+	if pf.S1.Cmp(q3) == 1 {
+		return false
+	}
+	if !inMultiplicativeGroup(NSquare, c) || !inMultiplicativeGroup(NSquare, pf.U2) ||
+		!inMultiplicativeGroup(pk.N, pf.S2) || !inMultiplicativeGroup(NTilde, pf.Z) || !inMultiplicativeGroup(NTilde, pf.U3) {
+		return false
+	}
+
+	e := pdlChallenge(ec, pk, c, Q, NTilde, h1, h2, pf.Z, pf.U1, pf.U2, pf.U3)
+	minusE := new(big.Int).Sub(zero, e)
+
+	{ // s1·G == u1 + e·Q
+		s1G := crypto.ScalarBaseMult(ec, new(big.Int).Mod(pf.S1, q))
+		u1PlusEQ, err := pf.U1.Add(Q.ScalarMult(e))
+		if err != nil || !s1G.Equals(u1PlusEQ) {
+			return false
+		}
+	}
+
+	{ // u2 == Gamma^s1 * s2^N * c^-e mod N^2
+		modNSquared := common.ModInt(NSquare)
+		cExpMinusE := modNSquared.Exp(c, minusE)
+		if cExpMinusE == nil {
+			return false
+		}
+		products := modNSquared.Mul(modNSquared.Exp(pk.Gamma(), pf.S1), modNSquared.Exp(pf.S2, pk.N))
+		products = modNSquared.Mul(products, cExpMinusE)
+		if pf.U2.Cmp(products) != 0 {
+			return false
+		}
+	}
+
+	{ // u3 == h1^s1 * h2^s3 * z^-e mod NTilde
+		modNTilde := common.ModInt(NTilde)
+		zExpMinusE := modNTilde.Exp(pf.Z, minusE)
+		if zExpMinusE == nil {
+			return false
+		}
+		products := modNTilde.Mul(modNTilde.Exp(h1, pf.S1), modNTilde.Exp(h2, pf.S3))
+		products = modNTilde.Mul(products, zExpMinusE)
+		if pf.U3.Cmp(products) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (pf *PDLwSlackProof) ValidateBasic() bool {
+	return pf.Z != nil &&
+		pf.U1 != nil &&
+		pf.U2 != nil &&
+		pf.U3 != nil &&
+		pf.S1 != nil &&
+		pf.S2 != nil &&
+		pf.S3 != nil
+}
+
+func (pf *PDLwSlackProof) Bytes() [PDLwSlackProofBytesParts][]byte {
+	return [...][]byte{
+		pf.Z.Bytes(),
+		pf.U1.X().Bytes(),
+		pf.U1.Y().Bytes(),
+		pf.U2.Bytes(),
+		pf.U3.Bytes(),
+		pf.S1.Bytes(),
+		pf.S2.Bytes(),
+		pf.S3.Bytes(),
+	}
+}
+
+// ----- //
+
+func pdlChallenge(ec elliptic.Curve, pk *paillier.PublicKey, c *big.Int, Q *crypto.ECPoint, NTilde, h1, h2, z *big.Int,
+	u1 *crypto.ECPoint, u2, u3 *big.Int) *big.Int {
+	ecParams := ec.Params()
+	eHash := common.SHA512_256i(append(pk.AsInts(),
+		ecParams.Gx, ecParams.Gy, Q.X(), Q.Y(), c, NTilde, h1, h2, z, u1.X(), u1.Y(), u2, u3)...)
+	return common.RejectionSample(ecParams.N, eHash)
+}
+
+// inMultiplicativeGroup reports whether 0 < v < n and gcd(v, n) == 1
+func inMultiplicativeGroup(n, v *big.Int) bool {
+	if v.Cmp(zero) != 1 || v.Cmp(n) != -1 {
+		return false
+	}
+	return new(big.Int).GCD(nil, nil, v, n).Cmp(one) == 0
+}
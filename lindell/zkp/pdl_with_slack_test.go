@@ -0,0 +1,81 @@
+package zkp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testFixtureDirFormat  = "%s/../../test/_ecdsa_fixtures"
+	testFixtureFileFormat = "keygen_data_%d.json"
+)
+
+func loadKeygenTestFixture(t *testing.T, partyIndex int) keygen.LocalPartySaveData {
+	_, callerFileName, _, _ := runtime.Caller(0)
+	fixtureDirName := fmt.Sprintf(testFixtureDirFormat, filepath.Dir(callerFileName))
+	bz, err := os.ReadFile(fmt.Sprintf("%s/"+testFixtureFileFormat, fixtureDirName, partyIndex))
+	if err != nil || len(bz) == 0 {
+		t.Skipf("keygen fixture %d is not available, create it with go generate ./lindell/signing/: %v", partyIndex, err)
+	}
+	var key keygen.LocalPartySaveData
+	if err = json.Unmarshal(bz, &key); err != nil || key.PaillierSK == nil {
+		t.Skipf("keygen fixture %d could not be parsed: %v", partyIndex, err)
+	}
+	return key
+}
+
+func TestPDLwSlackProof(t *testing.T) {
+	ec := tss.S256()
+	prover, verifier := loadKeygenTestFixture(t, 0), loadKeygenTestFixture(t, 1)
+	pk := &prover.PaillierSK.PublicKey
+
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	Q := crypto.ScalarBaseMult(ec, x)
+	c, r, err := pk.EncryptAndReturnRandomness(x)
+	assert.NoError(t, err)
+
+	pf, err := NewPDLwSlackProof(ec, pk, c, Q, verifier.NTildei, verifier.H1i, verifier.H2i, x, r)
+	assert.NoError(t, err)
+	assert.True(t, pf.Verify(ec, pk, c, Q, verifier.NTildei, verifier.H1i, verifier.H2i), "proof must verify")
+
+	bzs := pf.Bytes()
+	pf2, err := PDLwSlackProofFromBytes(ec, bzs[:])
+	assert.NoError(t, err)
+	assert.True(t, pf2.Verify(ec, pk, c, Q, verifier.NTildei, verifier.H1i, verifier.H2i), "decoded proof must verify")
+
+	// a ciphertext of a different value must not pass against Q
+	c2, _, err := pk.EncryptAndReturnRandomness(new(big.Int).Add(x, one))
+	assert.NoError(t, err)
+	assert.False(t, pf.Verify(ec, pk, c2, Q, verifier.NTildei, verifier.H1i, verifier.H2i), "proof must not verify a different ciphertext")
+
+	// nor must a different public share
+	Q2 := crypto.ScalarBaseMult(ec, new(big.Int).Add(x, one))
+	assert.False(t, pf.Verify(ec, pk, c, Q2, verifier.NTildei, verifier.H1i, verifier.H2i), "proof must not verify a different public share")
+}
+
+func TestPDLwSlackProofRejectsWrongWitness(t *testing.T) {
+	ec := tss.S256()
+	prover, verifier := loadKeygenTestFixture(t, 0), loadKeygenTestFixture(t, 1)
+	pk := &prover.PaillierSK.PublicKey
+
+	x := common.GetRandomPositiveInt(ec.Params().N)
+	Q := crypto.ScalarBaseMult(ec, x)
+	// the ciphertext encrypts something other than log_G(Q)
+	c, r, err := pk.EncryptAndReturnRandomness(new(big.Int).Add(x, one))
+	assert.NoError(t, err)
+
+	pf, err := NewPDLwSlackProof(ec, pk, c, Q, verifier.NTildei, verifier.H1i, verifier.H2i, x, r)
+	assert.NoError(t, err)
+	assert.False(t, pf.Verify(ec, pk, c, Q, verifier.NTildei, verifier.H1i, verifier.H2i))
+}